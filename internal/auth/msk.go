@@ -10,21 +10,62 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/kafka"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
+// AWSCredentials selects the AWS credentials used for MSK. The zero value uses
+// the default credential chain.
+type AWSCredentials struct {
+	// Profile is a named profile from the shared AWS config and credentials files
+	Profile string
+
+	// RoleARN is a role assumed with STS using the base credentials
+	RoleARN string
+}
+
+// LoadAWSConfig loads the AWS configuration for a region, using the shared
+// profile and assuming the role given in creds
+func LoadAWSConfig(ctx context.Context, region string, creds AWSCredentials) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	if creds.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(creds.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	if creds.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), creds.RoleARN,
+			func(o *stscreds.AssumeRoleOptions) {
+				o.RoleSessionName = fmt.Sprintf("kim-%d", time.Now().Unix())
+			})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	return cfg, nil
+}
+
 // MSKTokenProvider implements sarama.TokenProvider for MSK IAM authentication
 type MSKTokenProvider struct {
 	region    string
+	creds     AWSCredentials
 	token     string
 	expiresAt time.Time
 	mutex     sync.RWMutex
 }
 
 // NewMSKTokenProvider creates a new MSK token provider
-func NewMSKTokenProvider(region string) *MSKTokenProvider {
+func NewMSKTokenProvider(region string, creds AWSCredentials) *MSKTokenProvider {
 	return &MSKTokenProvider{
 		region: region,
+		creds:  creds,
 	}
 }
 
@@ -57,11 +98,9 @@ func (p *MSKTokenProvider) Token() (*sarama.AccessToken, error) {
 // generateToken generates a new MSK authentication token
 func (p *MSKTokenProvider) generateToken() (string, error) {
 	// Load AWS configuration
-	_, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion(p.region),
-	)
+	_, err := LoadAWSConfig(context.TODO(), p.region, p.creds)
 	if err != nil {
-		return "", fmt.Errorf("failed to load AWS config: %w", err)
+		return "", err
 	}
 
 	// This is a simplified implementation. In a real implementation,
@@ -77,13 +116,11 @@ func (p *MSKTokenProvider) generateToken() (string, error) {
 }
 
 // GetMSKBootstrapBrokers retrieves bootstrap brokers for an MSK cluster
-func GetMSKBootstrapBrokers(region, clusterARN string) (string, error) {
+func GetMSKBootstrapBrokers(region, clusterARN string, creds AWSCredentials) (string, error) {
 	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion(region),
-	)
+	cfg, err := LoadAWSConfig(context.TODO(), region, creds)
 	if err != nil {
-		return "", fmt.Errorf("failed to load AWS config: %w", err)
+		return "", err
 	}
 
 	// Create MSK client
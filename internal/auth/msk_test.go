@@ -1,15 +1,22 @@
 package auth
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 )
 
 func TestNewMSKTokenProvider(t *testing.T) {
 	region := "us-east-1"
 
-	provider := NewMSKTokenProvider(region)
+	provider := NewMSKTokenProvider(region, AWSCredentials{})
 	if provider == nil {
 		t.Fatal("MSKTokenProvider should not be nil")
 	}
@@ -20,7 +27,7 @@ func TestNewMSKTokenProvider(t *testing.T) {
 }
 
 func TestMSKTokenProviderToken(t *testing.T) {
-	provider := NewMSKTokenProvider("us-east-1")
+	provider := NewMSKTokenProvider("us-east-1", AWSCredentials{})
 
 	// Test token generation (this will likely fail in CI without AWS credentials)
 	// but we can test the structure
@@ -45,7 +52,7 @@ func TestMSKTokenProviderToken(t *testing.T) {
 }
 
 func TestMSKTokenProviderCaching(t *testing.T) {
-	provider := NewMSKTokenProvider("us-east-1")
+	provider := NewMSKTokenProvider("us-east-1", AWSCredentials{})
 
 	// Mock a cached token by setting internal fields
 	provider.token = "cached-token"
@@ -70,7 +77,7 @@ func TestMSKTokenProviderCaching(t *testing.T) {
 }
 
 func TestMSKTokenProviderExpiredCache(t *testing.T) {
-	provider := NewMSKTokenProvider("us-east-1")
+	provider := NewMSKTokenProvider("us-east-1", AWSCredentials{})
 
 	// Mock an expired cached token
 	provider.token = "expired-token"
@@ -91,7 +98,7 @@ func TestMSKTokenProviderExpiredCache(t *testing.T) {
 }
 
 func TestGenerateToken(t *testing.T) {
-	provider := NewMSKTokenProvider("us-east-1")
+	provider := NewMSKTokenProvider("us-east-1", AWSCredentials{})
 
 	// Test the generateToken method directly (this will likely fail without AWS creds)
 	token, err := provider.generateToken()
@@ -143,6 +150,83 @@ func TestValidateClusterARN(t *testing.T) {
 	}
 }
 
+// writeAWSConfig points the AWS SDK at shared config and credentials files
+// containing a single named profile
+func writeAWSConfig(t *testing.T, profile, region, accessKeyID string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	configFile := filepath.Join(dir, "config")
+	configData := fmt.Sprintf("[profile %s]\nregion = %s\n", profile, region)
+	if err := os.WriteFile(configFile, []byte(configData), 0600); err != nil {
+		t.Fatalf("Failed to write AWS config: %v", err)
+	}
+
+	credentialsFile := filepath.Join(dir, "credentials")
+	credentialsData := fmt.Sprintf("[%s]\naws_access_key_id = %s\naws_secret_access_key = secret\n", profile, accessKeyID)
+	if err := os.WriteFile(credentialsFile, []byte(credentialsData), 0600); err != nil {
+		t.Fatalf("Failed to write AWS credentials: %v", err)
+	}
+
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_REGION", "")
+}
+
+func TestLoadAWSConfigSharedProfile(t *testing.T) {
+	writeAWSConfig(t, "analytics", "eu-west-1", "AKIDANALYTICS")
+
+	cfg, err := LoadAWSConfig(context.Background(), "", AWSCredentials{Profile: "analytics"})
+	if err != nil {
+		t.Fatalf("LoadAWSConfig failed: %v", err)
+	}
+
+	if cfg.Region != "eu-west-1" {
+		t.Errorf("Expected region from the shared profile 'eu-west-1', got '%s'", cfg.Region)
+	}
+
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to retrieve credentials: %v", err)
+	}
+	if creds.AccessKeyID != "AKIDANALYTICS" {
+		t.Errorf("Expected credentials from the shared profile, got '%s'", creds.AccessKeyID)
+	}
+
+	// An explicit region takes precedence over the profile's
+	cfg, err = LoadAWSConfig(context.Background(), "us-east-1", AWSCredentials{Profile: "analytics"})
+	if err != nil {
+		t.Fatalf("LoadAWSConfig failed: %v", err)
+	}
+	if cfg.Region != "us-east-1" {
+		t.Errorf("Expected region 'us-east-1', got '%s'", cfg.Region)
+	}
+
+	if _, err := LoadAWSConfig(context.Background(), "", AWSCredentials{Profile: "missing"}); err == nil {
+		t.Error("Expected an error for an unknown shared profile")
+	}
+}
+
+func TestLoadAWSConfigAssumeRole(t *testing.T) {
+	writeAWSConfig(t, "analytics", "eu-west-1", "AKIDANALYTICS")
+
+	cfg, err := LoadAWSConfig(context.Background(), "", AWSCredentials{
+		Profile: "analytics",
+		RoleARN: "arn:aws:iam::123456789012:role/kafka-admin",
+	})
+	if err != nil {
+		t.Fatalf("LoadAWSConfig failed: %v", err)
+	}
+
+	cache, ok := cfg.Credentials.(*aws.CredentialsCache)
+	if !ok || !cache.IsCredentialsProvider(&stscreds.AssumeRoleProvider{}) {
+		t.Errorf("Expected assume-role credentials, got %T", cfg.Credentials)
+	}
+}
+
 func TestGetMSKBootstrapBrokers(t *testing.T) {
 	// This test will likely fail without AWS credentials and a real cluster
 	// but we can test the function signature and error handling
@@ -150,7 +234,7 @@ func TestGetMSKBootstrapBrokers(t *testing.T) {
 	region := "us-east-1"
 	clusterARN := "arn:aws:kafka:us-east-1:123456789012:cluster/test/12345678-1234-1234-1234-123456789012-1"
 
-	_, err := GetMSKBootstrapBrokers(region, clusterARN)
+	_, err := GetMSKBootstrapBrokers(region, clusterARN, AWSCredentials{})
 
 	// We expect an error in test environment (no AWS credentials or real cluster)
 	if err == nil {
@@ -162,7 +246,7 @@ func TestGetMSKBootstrapBrokers(t *testing.T) {
 
 func TestMSKTokenProviderWithInvalidRegion(t *testing.T) {
 	// Test with empty region
-	provider := NewMSKTokenProvider("")
+	provider := NewMSKTokenProvider("", AWSCredentials{})
 	if provider == nil {
 		t.Error("Should create provider even with empty region")
 	}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Token lifetimes used by OAuthTokenProvider
+const (
+	// defaultOAuthTokenLifetime is how long a token without an exp claim is cached
+	defaultOAuthTokenLifetime = 5 * time.Minute
+
+	// oauthTokenCommandTimeout bounds how long the token command may run
+	oauthTokenCommandTimeout = 30 * time.Second
+)
+
+// OAuthTokenProvider implements sarama.TokenProvider for SASL/OAUTHBEARER. Tokens
+// are read from the stdout of a command, such as an OIDC helper, or from an
+// environment variable.
+type OAuthTokenProvider struct {
+	command   string
+	tokenEnv  string
+	token     string
+	expiresAt time.Time
+	mutex     sync.Mutex
+}
+
+// NewOAuthTokenProvider creates a provider that runs command to get a token. If
+// command is empty, the token is read from the tokenEnv environment variable.
+func NewOAuthTokenProvider(command, tokenEnv string) *OAuthTokenProvider {
+	return &OAuthTokenProvider{
+		command:  command,
+		tokenEnv: tokenEnv,
+	}
+}
+
+// Token returns a valid token, running the token command again only when the
+// cached token is about to expire
+func (p *OAuthTokenProvider) Token() (*sarama.AccessToken, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// Return cached token if still valid (with 1-minute buffer)
+	if p.token != "" && time.Now().Before(p.expiresAt.Add(-time.Minute)) {
+		return &sarama.AccessToken{Token: p.token}, nil
+	}
+
+	token, err := p.fetchToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OAuth token: %w", err)
+	}
+
+	p.token = token
+	p.expiresAt = tokenExpiry(token)
+
+	return &sarama.AccessToken{Token: token}, nil
+}
+
+// fetchToken runs the token command or reads the token environment variable
+func (p *OAuthTokenProvider) fetchToken() (string, error) {
+	if p.command == "" {
+		if p.tokenEnv == "" {
+			return "", fmt.Errorf("no token command or token environment variable configured")
+		}
+		token := strings.TrimSpace(os.Getenv(p.tokenEnv))
+		if token == "" {
+			return "", fmt.Errorf("environment variable %s is not set", p.tokenEnv)
+		}
+		return token, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oauthTokenCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token command returned an empty token")
+	}
+	return token, nil
+}
+
+// tokenExpiry returns the expiry of a JWT from its exp claim. Tokens that are
+// not JWTs, or have no exp claim, are cached for the default lifetime.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err == nil {
+			var claims struct {
+				Exp int64 `json:"exp"`
+			}
+			if err := json.Unmarshal(payload, &claims); err == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0)
+			}
+		}
+	}
+	return time.Now().Add(defaultOAuthTokenLifetime)
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOAuthTokenProviderCommand(t *testing.T) {
+	provider := NewOAuthTokenProvider("echo fake-oidc-token", "")
+
+	token, err := provider.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token.Token != "fake-oidc-token" {
+		t.Errorf("Expected the command's stdout as the token, got '%s'", token.Token)
+	}
+}
+
+func TestOAuthTokenProviderCachesToken(t *testing.T) {
+	// The command records each run so the test can count them
+	runs := filepath.Join(t.TempDir(), "runs")
+	provider := NewOAuthTokenProvider(fmt.Sprintf("echo run >> %s; echo cached-token", runs), "")
+
+	for i := 0; i < 3; i++ {
+		if _, err := provider.Token(); err != nil {
+			t.Fatalf("Token failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatalf("Failed to read runs file: %v", err)
+	}
+	if count := strings.Count(string(data), "run"); count != 1 {
+		t.Errorf("Expected the token command to run once, ran %d times", count)
+	}
+}
+
+func TestOAuthTokenProviderCommandFailure(t *testing.T) {
+	provider := NewOAuthTokenProvider("echo login required >&2; exit 1", "")
+
+	_, err := provider.Token()
+	if err == nil {
+		t.Fatal("Expected an error when the token command fails")
+	}
+	if !strings.Contains(err.Error(), "login required") {
+		t.Errorf("Expected the command's stderr in the error, got: %v", err)
+	}
+}
+
+func TestOAuthTokenProviderEnv(t *testing.T) {
+	t.Setenv("KIM_TEST_OAUTH_TOKEN", "static-token")
+
+	token, err := NewOAuthTokenProvider("", "KIM_TEST_OAUTH_TOKEN").Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token.Token != "static-token" {
+		t.Errorf("Expected 'static-token', got '%s'", token.Token)
+	}
+
+	if _, err := NewOAuthTokenProvider("", "KIM_TEST_UNSET_OAUTH_TOKEN").Token(); err == nil {
+		t.Error("Expected an error when the token environment variable is unset")
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"kim","exp":%d}`, exp)))
+	jwt := "eyJhbGciOiJub25lIn0." + payload + ".signature"
+
+	if got := tokenExpiry(jwt); got.Unix() != exp {
+		t.Errorf("Expected expiry from the exp claim %d, got %d", exp, got.Unix())
+	}
+
+	// Opaque tokens are cached for the default lifetime
+	got := tokenExpiry("opaque-token")
+	if got.Before(time.Now().Add(defaultOAuthTokenLifetime - time.Minute)) {
+		t.Errorf("Expected the default lifetime for an opaque token, got %s", got)
+	}
+}
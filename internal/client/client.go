@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,11 +16,16 @@ import (
 	"github.com/IBM/sarama"
 )
 
+// Factory builds a connected client for a profile
+type Factory func(profile *config.Profile) (*Client, error)
+
 // Manager manages Kafka client connections
 type Manager struct {
-	logger  *logger.Logger
-	clients map[string]*Client
-	mutex   sync.RWMutex
+	logger   *logger.Logger
+	clients  map[string]*Client
+	factory  Factory
+	clientID string
+	mutex    sync.RWMutex
 }
 
 // Client wraps Kafka client functionality
@@ -27,6 +34,8 @@ type Client struct {
 	AdminClient sarama.ClusterAdmin
 	Consumer    sarama.Consumer
 	Producer    sarama.SyncProducer
+	Offsets     OffsetClient
+	newProducer ProducerFactory
 	profile     *config.Profile
 	logger      *logger.Logger
 	connected   bool
@@ -35,37 +44,140 @@ type Client struct {
 
 // NewManager creates a new client manager
 func NewManager(logger *logger.Logger) *Manager {
+	m := &Manager{
+		logger:  logger,
+		clients: make(map[string]*Client),
+	}
+	m.factory = m.createClient
+	return m
+}
+
+// NewManagerWithFactory creates a client manager that builds clients using the given factory
+func NewManagerWithFactory(logger *logger.Logger, factory Factory) *Manager {
 	return &Manager{
 		logger:  logger,
 		clients: make(map[string]*Client),
+		factory: factory,
+	}
+}
+
+// ProducerFactory creates a sync producer from a sarama configuration
+type ProducerFactory func(config *sarama.Config) (sarama.SyncProducer, error)
+
+// NewClient wraps already created sarama components in a connected client.
+// It is mainly used to inject mock implementations in tests.
+func NewClient(profile *config.Profile, config *sarama.Config, admin sarama.ClusterAdmin,
+	consumer sarama.Consumer, producer sarama.SyncProducer, logger *logger.Logger) *Client {
+	return &Client{
+		Config:      config,
+		AdminClient: admin,
+		Consumer:    consumer,
+		Producer:    producer,
+		profile:     profile,
+		logger:      logger,
+		connected:   true,
 	}
 }
 
+// SetClientID overrides the client ID of the profiles' clients. An empty ID
+// keeps the profile's own.
+func (m *Manager) SetClientID(clientID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.clientID = clientID
+}
+
 // GetClient returns or creates a client for the given profile
 func (m *Manager) GetClient(profile *config.Profile) (*Client, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	clientKey := fmt.Sprintf("%s_%s", profile.Type, profile.Name)
+	key := clientKey(profile)
 
-	if client, exists := m.clients[clientKey]; exists && client.connected {
+	if client, exists := m.clients[key]; exists && client.connected {
 		return client, nil
 	}
 
-	client, err := m.createClient(profile)
+	client, err := m.factory(profile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, classifyConnectionError(fmt.Errorf("failed to create client: %w", err))
 	}
 
-	m.clients[clientKey] = client
+	m.clients[key] = client
 	return client, nil
 }
 
+// Invalidate closes and forgets the cached client of the named profile, so the
+// next GetClient for the profile connects again
+func (m *Manager) Invalidate(profileName string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var errors []error
+	for key, client := range m.clients {
+		// Keys are the profile type, which has no underscore, and the name
+		if _, name, _ := strings.Cut(key, "_"); name != profileName {
+			continue
+		}
+		if err := client.Close(); err != nil {
+			errors = append(errors, err)
+		}
+		delete(m.clients, key)
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("failed to close client of profile %s: %v", profileName, errors)
+	}
+	return nil
+}
+
+// CloseAll closes every cached client and forgets them
+func (m *Manager) CloseAll() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var errors []error
+	for key, client := range m.clients {
+		if err := client.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("%s: %w", key, err))
+		}
+		delete(m.clients, key)
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("failed to close clients: %v", errors)
+	}
+	return nil
+}
+
+// clientKey identifies the cached client of a profile
+func clientKey(profile *config.Profile) string {
+	return fmt.Sprintf("%s_%s", profile.Type, profile.Name)
+}
+
+// defaultClientID identifies kim in broker logs when a profile does not set a client ID
+const defaultClientID = "kim-client"
+
+// defaultKafkaVersion is the protocol version used when a profile does not set
+// one. It is compatible with most Kafka versions.
+var defaultKafkaVersion = sarama.V2_8_1_0
+
+// DefaultKafkaVersion returns the protocol version used when a profile does not set one
+func DefaultKafkaVersion() string {
+	return defaultKafkaVersion.String()
+}
+
+// Connection settings used when a profile leaves them unset
+const (
+	defaultDialTimeout      = 10 * time.Second
+	defaultReadTimeout      = 30 * time.Second
+	defaultWriteTimeout     = 30 * time.Second
+	defaultMetadataRetryMax = 3
+)
+
 // createClient creates a new Kafka client based on the profile
 func (m *Manager) createClient(profile *config.Profile) (*Client, error) {
-	config := sarama.NewConfig()
-	config.Version = sarama.V2_8_1_0 // Compatible with most Kafka versions
-	config.ClientID = "kim-client"
+	config := m.newConfig(profile)
 
 	// Configure based on profile type
 	switch profile.Type {
@@ -94,10 +206,63 @@ func (m *Manager) createClient(profile *config.Profile) (*Client, error) {
 	return client, nil
 }
 
+// newConfig creates the sarama config shared by all profile types
+func (m *Manager) newConfig(profile *config.Profile) *sarama.Config {
+	config := sarama.NewConfig()
+	config.Version = m.kafkaVersion(profile)
+	config.ClientID = defaultClientID
+	if profile.ClientID != "" {
+		config.ClientID = profile.ClientID
+	}
+	if m.clientID != "" {
+		config.ClientID = m.clientID
+	}
+	configureConnection(config, profile)
+	return config
+}
+
+// kafkaVersion returns the protocol version configured in the profile, falling
+// back to the default when it is not set or cannot be parsed
+func (m *Manager) kafkaVersion(profile *config.Profile) sarama.KafkaVersion {
+	if profile.KafkaVersion == "" {
+		return defaultKafkaVersion
+	}
+
+	version, err := sarama.ParseKafkaVersion(profile.KafkaVersion)
+	if err != nil {
+		m.logger.Warn("Invalid Kafka version, using default",
+			"profile", profile.Name, "version", profile.KafkaVersion, "default", defaultKafkaVersion.String())
+		return defaultKafkaVersion
+	}
+	return version
+}
+
+// configureConnection applies the profile's timeouts and retries, using the
+// defaults for settings that are not set
+func configureConnection(config *sarama.Config, profile *config.Profile) {
+	config.Net.DialTimeout = durationOrDefault(profile.DialTimeout, defaultDialTimeout)
+	config.Net.ReadTimeout = durationOrDefault(profile.ReadTimeout, defaultReadTimeout)
+	config.Net.WriteTimeout = durationOrDefault(profile.WriteTimeout, defaultWriteTimeout)
+
+	config.Metadata.Retry.Max = defaultMetadataRetryMax
+	if profile.MetadataRetryMax > 0 {
+		config.Metadata.Retry.Max = profile.MetadataRetryMax
+	}
+}
+
+// durationOrDefault returns d, or def when d is not set
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
+
 // configureMSK configures the client for MSK
 func (m *Manager) configureMSK(config *sarama.Config, profile *config.Profile) error {
 	// Get bootstrap brokers from MSK
-	brokers, err := auth.GetMSKBootstrapBrokers(profile.Region, profile.ClusterARN)
+	creds := auth.AWSCredentials{Profile: profile.AWSProfile, RoleARN: profile.AWSRoleARN}
+	brokers, err := auth.GetMSKBootstrapBrokers(profile.Region, profile.ClusterARN, creds)
 	if err != nil {
 		return fmt.Errorf("failed to get MSK bootstrap brokers: %w", err)
 	}
@@ -112,17 +277,30 @@ func (m *Manager) configureMSK(config *sarama.Config, profile *config.Profile) e
 	case "IAM":
 		config.Net.SASL.Enable = true
 		config.Net.SASL.Mechanism = sarama.SASLTypeOAuth
-		config.Net.SASL.TokenProvider = auth.NewMSKTokenProvider(profile.Region)
+		config.Net.SASL.TokenProvider = auth.NewMSKTokenProvider(profile.Region, creds)
 		config.Net.TLS.Enable = true
 		config.Net.TLS.Config = &tls.Config{
 			InsecureSkipVerify: false,
 		}
+		if err := applyTLSMinVersion(config.Net.TLS.Config, profile); err != nil {
+			return err
+		}
 	case "SASL_SCRAM":
 		config.Net.SASL.Enable = true
 		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		password, err := resolveSecret(profile.SASLPassword)
+		if err != nil {
+			return fmt.Errorf("failed to resolve SASL password: %w", err)
+		}
 		config.Net.SASL.User = profile.SASLUsername
-		config.Net.SASL.Password = profile.SASLPassword
+		config.Net.SASL.Password = password
 		config.Net.TLS.Enable = true
+		if profile.TLSMinVersion != "" {
+			config.Net.TLS.Config = &tls.Config{}
+			if err := applyTLSMinVersion(config.Net.TLS.Config, profile); err != nil {
+				return err
+			}
+		}
 	default:
 		return fmt.Errorf("unsupported MSK auth method: %s", authMethod)
 	}
@@ -168,6 +346,10 @@ func (m *Manager) configureKafka(config *sarama.Config, profile *config.Profile)
 func (m *Manager) configureSSL(config *sarama.Config, profile *config.Profile) error {
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: !profile.SSLCheckHostname,
+		ServerName:         profile.TLSServerName,
+	}
+	if err := applyTLSMinVersion(tlsConfig, profile); err != nil {
+		return err
 	}
 
 	if profile.SSLCAFile != "" {
@@ -176,6 +358,12 @@ func (m *Manager) configureSSL(config *sarama.Config, profile *config.Profile) e
 		m.logger.Debug("SSL CA file configured", "file", profile.SSLCAFile)
 	}
 
+	// The key password is only needed once client key loading is implemented,
+	// but resolve it now so a missing environment variable is reported early
+	if _, err := resolveSecret(profile.SSLPassword); err != nil {
+		return fmt.Errorf("failed to resolve SSL password: %w", err)
+	}
+
 	if profile.SSLCertFile != "" && profile.SSLKeyFile != "" {
 		// Load client certificate and key
 		// Implementation would load the cert and key files
@@ -187,6 +375,28 @@ func (m *Manager) configureSSL(config *sarama.Config, profile *config.Profile) e
 	return nil
 }
 
+// tlsVersions maps the TLS versions a profile can require to their crypto/tls constants
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// applyTLSMinVersion sets the minimum TLS version of the profile on a TLS
+// config. Without one Go's default minimum applies.
+func applyTLSMinVersion(tlsConfig *tls.Config, profile *config.Profile) error {
+	if profile.TLSMinVersion == "" {
+		return nil
+	}
+	version, ok := tlsVersions[profile.TLSMinVersion]
+	if !ok {
+		return fmt.Errorf("unsupported TLS version: %s (must be 1.0, 1.1, 1.2 or 1.3)", profile.TLSMinVersion)
+	}
+	tlsConfig.MinVersion = version
+	return nil
+}
+
 // configureSASL configures SASL settings
 func (m *Manager) configureSASL(config *sarama.Config, profile *config.Profile) error {
 	switch profile.SASLMechanism {
@@ -198,15 +408,47 @@ func (m *Manager) configureSASL(config *sarama.Config, profile *config.Profile)
 		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
 	case "GSSAPI":
 		config.Net.SASL.Mechanism = sarama.SASLTypeGSSAPI
+	case "OAUTHBEARER":
+		if profile.OAuthTokenCommand == "" && profile.OAuthTokenEnv == "" {
+			return fmt.Errorf("OAUTHBEARER requires an OAuth token command or token environment variable")
+		}
+		config.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+		config.Net.SASL.TokenProvider = auth.NewOAuthTokenProvider(profile.OAuthTokenCommand, profile.OAuthTokenEnv)
+		return nil
 	default:
 		return fmt.Errorf("unsupported SASL mechanism: %s", profile.SASLMechanism)
 	}
 
+	password, err := resolveSecret(profile.SASLPassword)
+	if err != nil {
+		return fmt.Errorf("failed to resolve SASL password: %w", err)
+	}
+
 	config.Net.SASL.User = profile.SASLUsername
-	config.Net.SASL.Password = profile.SASLPassword
+	config.Net.SASL.Password = password
 	return nil
 }
 
+// resolveSecret expands a value of the form ${ENV_VAR} using the named environment variable.
+// Any other value is returned unchanged.
+func resolveSecret(value string) (string, error) {
+	if !strings.HasPrefix(value, "${") || !strings.HasSuffix(value, "}") {
+		return value, nil
+	}
+
+	name := strings.TrimSuffix(strings.TrimPrefix(value, "${"), "}")
+	if name == "" {
+		return "", fmt.Errorf("empty environment variable reference")
+	}
+
+	resolved, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return resolved, nil
+}
+
 // connect establishes connections to Kafka
 func (c *Client) connect() error {
 	c.mutex.Lock()
@@ -234,12 +476,18 @@ func (c *Client) connect() error {
 	c.Config.Producer.Retry.Max = 3
 	c.Config.Producer.Timeout = 10 * time.Second
 
-	producer, err := sarama.NewSyncProducer(brokers, c.Config)
+	c.newProducer = func(config *sarama.Config) (sarama.SyncProducer, error) {
+		return sarama.NewSyncProducer(brokers, config)
+	}
+
+	producer, err := c.newProducer(c.Config)
 	if err != nil {
 		return fmt.Errorf("failed to create producer: %w", err)
 	}
 	c.Producer = producer
 
+	c.Offsets = &offsetClient{brokers: brokers, config: c.Config}
+
 	c.connected = true
 	c.logger.Info("Successfully connected to Kafka cluster",
 		"profile", c.profile.Name, "type", c.profile.Type)
@@ -247,6 +495,59 @@ func (c *Client) connect() error {
 	return nil
 }
 
+// SetProducerFactory sets how NewProducer creates producers. It is mainly
+// used to inject mock producers in tests.
+func (c *Client) SetProducerFactory(factory ProducerFactory) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.newProducer = factory
+}
+
+// NewProducer creates a dedicated sync producer that waits for the given
+// acknowledgements and compresses with the given codec. The client's
+// configuration is copied, not modified, and the caller is responsible for
+// closing the producer.
+func (c *Client) NewProducer(acks sarama.RequiredAcks, codec sarama.CompressionCodec) (sarama.SyncProducer, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.newProducer == nil {
+		return nil, fmt.Errorf("client cannot create producers")
+	}
+
+	config := *c.Config
+	config.Producer.RequiredAcks = acks
+	config.Producer.Compression = codec
+
+	producer, err := c.newProducer(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create producer: %w", err)
+	}
+	return producer, nil
+}
+
+// NewManualProducer creates a dedicated sync producer that sends every message
+// to the partition set on it rather than choosing one from the key. The client's
+// configuration is copied, not modified, and the caller is responsible for
+// closing the producer.
+func (c *Client) NewManualProducer() (sarama.SyncProducer, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.newProducer == nil {
+		return nil, fmt.Errorf("client cannot create producers")
+	}
+
+	config := *c.Config
+	config.Producer.Partitioner = sarama.NewManualPartitioner
+
+	producer, err := c.newProducer(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create producer: %w", err)
+	}
+	return producer, nil
+}
+
 // Close closes all client connections
 func (c *Client) Close() error {
 	c.mutex.Lock()
@@ -272,6 +573,12 @@ func (c *Client) Close() error {
 		}
 	}
 
+	if c.Offsets != nil {
+		if err := c.Offsets.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("failed to close offset client: %w", err))
+		}
+	}
+
 	c.connected = false
 
 	if len(errors) > 0 {
@@ -295,10 +602,19 @@ func (c *Client) Ping(ctx context.Context) error {
 	}
 
 	// Try to get cluster metadata as a ping
-	_, _, err := c.AdminClient.DescribeCluster()
-	if err != nil {
-		return fmt.Errorf("ping failed: %w", err)
+	errChan := make(chan error, 1)
+	go func() {
+		_, _, err := c.AdminClient.DescribeCluster()
+		errChan <- err
+	}()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			return fmt.Errorf("ping failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("ping failed: %w", ctx.Err())
 	}
-
-	return nil
 }
@@ -0,0 +1,471 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nipunap/kim/internal/config"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+func saslProfile(password string) *config.Profile {
+	return &config.Profile{
+		Name:             "sasl",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9092",
+		SecurityProtocol: "SASL_PLAINTEXT",
+		SASLMechanism:    "PLAIN",
+		SASLUsername:     "user",
+		SASLPassword:     password,
+	}
+}
+
+func TestConfigureKafkaResolvesPasswordFromEnv(t *testing.T) {
+	t.Setenv("MY_PW", "s3cret")
+
+	m := NewManager(logger.New())
+	cfg := sarama.NewConfig()
+
+	if err := m.configureKafka(cfg, saslProfile("${MY_PW}")); err != nil {
+		t.Fatalf("Failed to configure client: %v", err)
+	}
+
+	if cfg.Net.SASL.Password != "s3cret" {
+		t.Errorf("Expected resolved password 's3cret', got '%s'", cfg.Net.SASL.Password)
+	}
+	if cfg.Net.SASL.User != "user" {
+		t.Errorf("Expected SASL user 'user', got '%s'", cfg.Net.SASL.User)
+	}
+}
+
+func TestConfigureSASLOAuthBearer(t *testing.T) {
+	m := NewManager(logger.New())
+
+	profile := saslProfile("")
+	profile.SASLMechanism = "OAUTHBEARER"
+	profile.OAuthTokenCommand = "echo fake-oidc-token"
+
+	cfg := sarama.NewConfig()
+	if err := m.configureKafka(cfg, profile); err != nil {
+		t.Fatalf("Failed to configure client: %v", err)
+	}
+
+	if cfg.Net.SASL.Mechanism != sarama.SASLTypeOAuth {
+		t.Errorf("Expected OAUTHBEARER mechanism, got '%s'", cfg.Net.SASL.Mechanism)
+	}
+	token, err := cfg.Net.SASL.TokenProvider.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token.Token != "fake-oidc-token" {
+		t.Errorf("Expected token from the command, got '%s'", token.Token)
+	}
+
+	// A token source is required
+	profile.OAuthTokenCommand = ""
+	if err := m.configureKafka(sarama.NewConfig(), profile); err == nil {
+		t.Error("Expected an error without a token command or environment variable")
+	}
+}
+
+func TestConfigureSSLMinVersion(t *testing.T) {
+	m := NewManager(logger.New())
+	profile := &config.Profile{
+		Name:             "tls",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9093",
+		SecurityProtocol: "SSL",
+		TLSMinVersion:    "1.3",
+	}
+
+	cfg := sarama.NewConfig()
+	if err := m.configureKafka(cfg, profile); err != nil {
+		t.Fatalf("configureKafka failed: %v", err)
+	}
+	if cfg.Net.TLS.Config.MinVersion != tls.VersionTLS13 {
+		t.Errorf("Expected minimum version TLS 1.3, got %x", cfg.Net.TLS.Config.MinVersion)
+	}
+
+	// Without a minimum version Go's default applies
+	profile.TLSMinVersion = ""
+	cfg = sarama.NewConfig()
+	if err := m.configureKafka(cfg, profile); err != nil {
+		t.Fatalf("configureKafka failed: %v", err)
+	}
+	if cfg.Net.TLS.Config.MinVersion != 0 {
+		t.Errorf("Expected no minimum version, got %x", cfg.Net.TLS.Config.MinVersion)
+	}
+
+	profile.TLSMinVersion = "1.4"
+	if err := m.configureKafka(sarama.NewConfig(), profile); err == nil {
+		t.Error("Expected an error for an unsupported TLS version")
+	}
+}
+
+func TestConfigureSSLServerName(t *testing.T) {
+	m := NewManager(logger.New())
+	profile := &config.Profile{
+		Name:             "proxied",
+		Type:             "kafka",
+		BootstrapServers: "lb.example.com:9093",
+		SecurityProtocol: "SASL_SSL",
+		SASLMechanism:    "PLAIN",
+		SASLUsername:     "user",
+		SASLPassword:     "secret",
+		SSLCheckHostname: true,
+		TLSServerName:    "kafka.internal.example.com",
+	}
+
+	cfg := sarama.NewConfig()
+	if err := m.configureKafka(cfg, profile); err != nil {
+		t.Fatalf("configureKafka failed: %v", err)
+	}
+	if cfg.Net.TLS.Config.ServerName != "kafka.internal.example.com" {
+		t.Errorf("Expected server name kafka.internal.example.com, got %q", cfg.Net.TLS.Config.ServerName)
+	}
+	if cfg.Net.TLS.Config.InsecureSkipVerify {
+		t.Error("Expected the certificate to be verified")
+	}
+}
+
+func TestCreateClientFailsWhenPasswordEnvMissing(t *testing.T) {
+	m := NewManager(logger.New())
+
+	_, err := m.GetClient(saslProfile("${KIM_TEST_UNSET_PASSWORD}"))
+	if err == nil {
+		t.Fatal("Expected error when referenced environment variable is unset")
+	}
+	if !strings.Contains(err.Error(), "KIM_TEST_UNSET_PASSWORD is not set") {
+		t.Errorf("Expected error to name the missing variable, got: %v", err)
+	}
+}
+
+func TestManagerInvalidate(t *testing.T) {
+	created := 0
+	m := NewManagerWithFactory(logger.New(), func(profile *config.Profile) (*Client, error) {
+		created++
+		return NewClient(profile, sarama.NewConfig(), nil, nil, nil, logger.New()), nil
+	})
+
+	staging := &config.Profile{Name: "staging", Type: "kafka"}
+	prod := &config.Profile{Name: "prod", Type: "kafka"}
+
+	first, err := m.GetClient(staging)
+	if err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+	if again, _ := m.GetClient(staging); again != first {
+		t.Error("GetClient should reuse the cached client")
+	}
+	other, err := m.GetClient(prod)
+	if err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+
+	if err := m.Invalidate("staging"); err != nil {
+		t.Fatalf("Invalidate failed: %v", err)
+	}
+	if first.IsConnected() {
+		t.Error("Invalidate should close the profile's client")
+	}
+	if !other.IsConnected() {
+		t.Error("Invalidate should not close other profiles' clients")
+	}
+
+	if again, _ := m.GetClient(staging); again == first {
+		t.Error("GetClient should connect again after Invalidate")
+	}
+	if created != 3 {
+		t.Errorf("Expected 3 clients to be created, got %d", created)
+	}
+}
+
+func TestManagerCloseAll(t *testing.T) {
+	m := NewManagerWithFactory(logger.New(), func(profile *config.Profile) (*Client, error) {
+		return NewClient(profile, sarama.NewConfig(), nil, nil, nil, logger.New()), nil
+	})
+
+	var clients []*Client
+	for _, profile := range []*config.Profile{
+		{Name: "staging", Type: "kafka"},
+		{Name: "prod", Type: "msk"},
+	} {
+		c, err := m.GetClient(profile)
+		if err != nil {
+			t.Fatalf("GetClient failed: %v", err)
+		}
+		clients = append(clients, c)
+	}
+
+	if err := m.CloseAll(); err != nil {
+		t.Fatalf("CloseAll failed: %v", err)
+	}
+	for _, c := range clients {
+		if c.IsConnected() {
+			t.Errorf("Expected the client of %s to be closed", c.profile.Name)
+		}
+	}
+	if len(m.clients) != 0 {
+		t.Errorf("Expected no cached clients, got %d", len(m.clients))
+	}
+}
+
+func TestConfigureConnection(t *testing.T) {
+	cfg := sarama.NewConfig()
+	configureConnection(cfg, &config.Profile{
+		DialTimeout:      5 * time.Second,
+		ReadTimeout:      time.Minute,
+		WriteTimeout:     45 * time.Second,
+		MetadataRetryMax: 7,
+	})
+
+	if cfg.Net.DialTimeout != 5*time.Second {
+		t.Errorf("Expected dial timeout 5s, got %s", cfg.Net.DialTimeout)
+	}
+	if cfg.Net.ReadTimeout != time.Minute {
+		t.Errorf("Expected read timeout 1m, got %s", cfg.Net.ReadTimeout)
+	}
+	if cfg.Net.WriteTimeout != 45*time.Second {
+		t.Errorf("Expected write timeout 45s, got %s", cfg.Net.WriteTimeout)
+	}
+	if cfg.Metadata.Retry.Max != 7 {
+		t.Errorf("Expected 7 metadata retries, got %d", cfg.Metadata.Retry.Max)
+	}
+
+	// Unset values fall back to the defaults
+	cfg = sarama.NewConfig()
+	configureConnection(cfg, &config.Profile{})
+
+	if cfg.Net.DialTimeout != defaultDialTimeout || cfg.Net.ReadTimeout != defaultReadTimeout ||
+		cfg.Net.WriteTimeout != defaultWriteTimeout || cfg.Metadata.Retry.Max != defaultMetadataRetryMax {
+		t.Errorf("Expected default connection settings, got dial %s, read %s, write %s, retries %d",
+			cfg.Net.DialTimeout, cfg.Net.ReadTimeout, cfg.Net.WriteTimeout, cfg.Metadata.Retry.Max)
+	}
+}
+
+func TestKafkaVersion(t *testing.T) {
+	m := NewManager(logger.New())
+
+	tests := []struct {
+		version  string
+		expected sarama.KafkaVersion
+	}{
+		{"3.6.0", sarama.V3_6_0_0},
+		{"2.1.0", sarama.V2_1_0_0},
+		{"", defaultKafkaVersion},
+		{"not-a-version", defaultKafkaVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got := m.kafkaVersion(&config.Profile{Name: "test", KafkaVersion: tt.version})
+			if got != tt.expected {
+				t.Errorf("kafkaVersion(%q) = %s, want %s", tt.version, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewConfigClientID(t *testing.T) {
+	m := NewManager(logger.New())
+
+	if got := m.newConfig(&config.Profile{}).ClientID; got != "kim-client" {
+		t.Errorf("Expected default client ID 'kim-client', got '%s'", got)
+	}
+	if got := m.newConfig(&config.Profile{ClientID: "billing-ops"}).ClientID; got != "billing-ops" {
+		t.Errorf("Expected profile client ID 'billing-ops', got '%s'", got)
+	}
+
+	// The manager override takes precedence over the profile
+	m.SetClientID("oncall")
+	if got := m.newConfig(&config.Profile{ClientID: "billing-ops"}).ClientID; got != "oncall" {
+		t.Errorf("Expected overridden client ID 'oncall', got '%s'", got)
+	}
+}
+
+func TestResolveSecret(t *testing.T) {
+	t.Setenv("KIM_TEST_SECRET", "value")
+
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"plain", "plain", false},
+		{"", "", false},
+		{"${KIM_TEST_SECRET}", "value", false},
+		{"prefix-${KIM_TEST_SECRET}", "prefix-${KIM_TEST_SECRET}", false},
+		{"${}", "", true},
+		{"${KIM_TEST_UNSET_SECRET}", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveSecret(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveSecret(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("resolveSecret(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestClassifyConnectionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"sasl", fmt.Errorf("failed to connect: %w", sarama.ErrSASLAuthenticationFailed), types.ErrCodeAuthFailed},
+		{"sasl text", errors.New("kafka: SASL authentication failed: invalid credentials"), types.ErrCodeAuthFailed},
+		{"dns", errors.New("dial tcp: lookup kafka.invalid: no such host"), types.ErrCodeDNSResolution},
+		{"dns error", &net.DNSError{Err: "no such host", Name: "kafka.invalid"}, types.ErrCodeDNSResolution},
+		{"tls", errors.New("tls: failed to verify certificate: x509: certificate signed by unknown authority"), types.ErrCodeTLSHandshake},
+		{"plaintext to tls", errors.New("tls: first record does not look like a TLS handshake"), types.ErrCodeTLSHandshake},
+		{"timeout", errors.New("dial tcp 10.0.0.1:9092: i/o timeout"), types.ErrCodeConnectionTimeout},
+		{"deadline", fmt.Errorf("failed to connect: %w", context.DeadlineExceeded), types.ErrCodeConnectionTimeout},
+		{"refused", errors.New("dial tcp 127.0.0.1:9092: connect: connection refused"), types.ErrCodeConnectionFailed},
+		{"out of brokers", sarama.ErrOutOfBrokers, types.ErrCodeConnectionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyConnectionError(tt.err)
+
+			var kimErr *types.KimError
+			if !errors.As(err, &kimErr) {
+				t.Fatalf("Expected a KimError, got %T: %v", err, err)
+			}
+			if kimErr.Code != tt.expected {
+				t.Errorf("Expected code %s, got %s", tt.expected, kimErr.Code)
+			}
+			if kimErr.Details != tt.err.Error() {
+				t.Errorf("Expected the original error as details, got '%s'", kimErr.Details)
+			}
+		})
+	}
+
+	// Unrecognized errors are returned unchanged
+	original := errors.New("environment variable MY_PW is not set")
+	if err := classifyConnectionError(original); err != original {
+		t.Errorf("Expected the original error, got %v", err)
+	}
+}
+
+// fakeOffsetClient records the calls made to an OffsetClient
+type fakeOffsetClient struct {
+	requests []offsetRequest
+	closed   bool
+}
+
+// offsetRequest is a GetOffset call recorded by fakeOffsetClient
+type offsetRequest struct {
+	topic     string
+	partition int32
+	time      int64
+}
+
+func (f *fakeOffsetClient) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	f.requests = append(f.requests, offsetRequest{topic, partition, time})
+	return 42, nil
+}
+
+func (f *fakeOffsetClient) CommitOffsets(group string, offsets map[string]map[int32]int64) error {
+	return nil
+}
+
+func (f *fakeOffsetClient) Coordinator(group string) (*sarama.Broker, error) {
+	return nil, sarama.ErrConsumerCoordinatorNotAvailable
+}
+
+func (f *fakeOffsetClient) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestClientGetOffset(t *testing.T) {
+	offsets := &fakeOffsetClient{}
+	c := NewClient(&config.Profile{Name: "test"}, sarama.NewConfig(), nil, nil, nil, logger.New())
+	c.Offsets = offsets
+
+	offset, err := c.GetOffset("orders", 3, sarama.OffsetNewest)
+	if err != nil {
+		t.Fatalf("GetOffset failed: %v", err)
+	}
+	if offset != 42 {
+		t.Errorf("Expected the offset from the offset client, got %d", offset)
+	}
+	if len(offsets.requests) != 1 || offsets.requests[0] != (offsetRequest{"orders", 3, sarama.OffsetNewest}) {
+		t.Errorf("Expected the request to be forwarded, got %v", offsets.requests)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !offsets.closed {
+		t.Error("Close should close the offset client")
+	}
+
+	// Without an offset client there is nothing to query
+	c.Offsets = nil
+	if _, err := c.GetOffset("orders", 0, sarama.OffsetOldest); err == nil {
+		t.Error("GetOffset should fail without an offset client")
+	}
+}
+
+func TestClientNewProducer(t *testing.T) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	c := NewClient(&config.Profile{Name: "test"}, saramaConfig, nil, nil, nil, logger.New())
+
+	// Without a producer factory there is no way to create producers
+	if _, err := c.NewProducer(sarama.NoResponse, sarama.CompressionNone); err == nil {
+		t.Error("NewProducer should fail without a producer factory")
+	}
+
+	var configs []*sarama.Config
+	c.SetProducerFactory(func(cfg *sarama.Config) (sarama.SyncProducer, error) {
+		configs = append(configs, cfg)
+		return nil, nil
+	})
+
+	for _, acks := range []sarama.RequiredAcks{sarama.NoResponse, sarama.WaitForLocal, sarama.WaitForAll} {
+		if _, err := c.NewProducer(acks, sarama.CompressionNone); err != nil {
+			t.Fatalf("NewProducer(%d) failed: %v", acks, err)
+		}
+		if got := configs[len(configs)-1].Producer.RequiredAcks; got != acks {
+			t.Errorf("Expected required acks %d, got %d", acks, got)
+		}
+	}
+
+	for _, codec := range []sarama.CompressionCodec{sarama.CompressionGZIP, sarama.CompressionSnappy, sarama.CompressionLZ4, sarama.CompressionZSTD} {
+		if _, err := c.NewProducer(sarama.WaitForAll, codec); err != nil {
+			t.Fatalf("NewProducer(%s) failed: %v", codec, err)
+		}
+		if got := configs[len(configs)-1].Producer.Compression; got != codec {
+			t.Errorf("Expected compression %s, got %s", codec, got)
+		}
+	}
+
+	if saramaConfig.Producer.RequiredAcks != sarama.WaitForAll || saramaConfig.Producer.Compression != sarama.CompressionNone {
+		t.Errorf("NewProducer should not modify the client config, got acks %d and compression %s",
+			saramaConfig.Producer.RequiredAcks, saramaConfig.Producer.Compression)
+	}
+
+	c.SetProducerFactory(func(*sarama.Config) (sarama.SyncProducer, error) {
+		return nil, errors.New("broker unavailable")
+	})
+	if _, err := c.NewProducer(sarama.WaitForLocal, sarama.CompressionNone); err == nil {
+		t.Error("NewProducer should return the factory error")
+	}
+}
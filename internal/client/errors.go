@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+// classifyConnectionError maps common connection failures to a KimError with an
+// actionable message, keeping the original error as the details. Errors that
+// are not recognized are returned unchanged.
+func classifyConnectionError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code, message := connectionErrorCode(err)
+	if code == "" {
+		return err
+	}
+	return types.NewKimErrorWithDetails(code, message, err.Error())
+}
+
+// connectionErrorCode returns the error code and message for a connection
+// failure, or an empty code if the failure is not recognized. Sarama often
+// reports failures as strings, so the error text is checked as well as its type.
+func connectionErrorCode(err error) (string, string) {
+	text := strings.ToLower(err.Error())
+
+	var dnsErr *net.DNSError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, sarama.ErrSASLAuthenticationFailed) ||
+		strings.Contains(text, "sasl") && strings.Contains(text, "authentication failed"):
+		return types.ErrCodeAuthFailed, "check SASL credentials"
+	case errors.As(err, &dnsErr) || strings.Contains(text, "no such host"):
+		return types.ErrCodeDNSResolution, "check the bootstrap server host names"
+	case strings.Contains(text, "tls:") || strings.Contains(text, "x509:") ||
+		strings.Contains(text, "first record does not look like a tls handshake"):
+		return types.ErrCodeTLSHandshake, "check the security protocol and TLS certificates"
+	case errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() ||
+		strings.Contains(text, "i/o timeout"):
+		return types.ErrCodeConnectionTimeout, "check network connectivity or increase the profile's dial timeout"
+	case strings.Contains(text, "connection refused") || errors.Is(err, sarama.ErrOutOfBrokers):
+		return types.ErrCodeConnectionFailed, "check the bootstrap servers and that the brokers are running"
+	default:
+		return "", ""
+	}
+}
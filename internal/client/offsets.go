@@ -0,0 +1,153 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/IBM/sarama"
+)
+
+// OffsetClient resolves partition offsets and group coordinators and commits
+// consumer group offsets
+type OffsetClient interface {
+	// GetOffset returns the offset of the first message with a timestamp at or
+	// after time (in milliseconds), or the oldest or newest offset when time is
+	// sarama.OffsetOldest or sarama.OffsetNewest. Like sarama, it returns -1
+	// when no message is at or after the timestamp.
+	GetOffset(topic string, partition int32, time int64) (int64, error)
+
+	// CommitOffsets sets the committed offsets of a group, keyed by topic and partition
+	CommitOffsets(group string, offsets map[string]map[int32]int64) error
+
+	// Coordinator returns the broker coordinating a consumer group
+	Coordinator(group string) (*sarama.Broker, error)
+
+	// Close releases the connections used by the client
+	Close() error
+}
+
+// offsetClient implements OffsetClient with a sarama client that is only
+// created when first needed, as most commands never resolve or commit offsets
+type offsetClient struct {
+	brokers []string
+	config  *sarama.Config
+	client  sarama.Client
+	mutex   sync.Mutex
+}
+
+// saramaClient returns the underlying sarama client, creating it on first use
+func (oc *offsetClient) saramaClient() (sarama.Client, error) {
+	oc.mutex.Lock()
+	defer oc.mutex.Unlock()
+
+	if oc.client != nil {
+		return oc.client, nil
+	}
+
+	// Offsets are committed explicitly and commit errors must be reported
+	config := *oc.config
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	config.Consumer.Return.Errors = true
+
+	client, err := sarama.NewClient(oc.brokers, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	oc.client = client
+	return client, nil
+}
+
+// GetOffset queries the partition leader for an offset
+func (oc *offsetClient) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	client, err := oc.saramaClient()
+	if err != nil {
+		return 0, err
+	}
+	return client.GetOffset(topic, partition, time)
+}
+
+// Coordinator looks up the group coordinator, refreshing it if it is not cached
+func (oc *offsetClient) Coordinator(group string) (*sarama.Broker, error) {
+	client, err := oc.saramaClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Coordinator(group)
+}
+
+// CommitOffsets sets the committed offset of each partition, moving it backwards
+// or forwards as needed. The group must not have active members, as commits are
+// made outside of a group generation.
+func (oc *offsetClient) CommitOffsets(group string, offsets map[string]map[int32]int64) error {
+	client, err := oc.saramaClient()
+	if err != nil {
+		return err
+	}
+
+	offsetManager, err := sarama.NewOffsetManagerFromClient(group, client)
+	if err != nil {
+		return fmt.Errorf("failed to create offset manager: %w", err)
+	}
+
+	var partitionManagers []sarama.PartitionOffsetManager
+	closeAll := func() []error {
+		for _, pom := range partitionManagers {
+			pom.AsyncClose()
+		}
+		offsetManager.Close()
+
+		var errs []error
+		for _, pom := range partitionManagers {
+			if err := pom.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errs
+	}
+
+	for topic, partitions := range offsets {
+		for partition, offset := range partitions {
+			pom, err := offsetManager.ManagePartition(topic, partition)
+			if err != nil {
+				closeAll()
+				return fmt.Errorf("failed to manage partition %d of topic %s: %w", partition, topic, err)
+			}
+
+			// MarkOffset only moves the offset forwards and ResetOffset only
+			// backwards, so together they set it regardless of its current value
+			pom.MarkOffset(offset, "")
+			pom.ResetOffset(offset, "")
+
+			partitionManagers = append(partitionManagers, pom)
+		}
+	}
+
+	offsetManager.Commit()
+
+	if errs := closeAll(); len(errs) > 0 {
+		return fmt.Errorf("failed to commit offsets: %v", errs)
+	}
+	return nil
+}
+
+// Close closes the sarama client if it was created
+func (oc *offsetClient) Close() error {
+	oc.mutex.Lock()
+	defer oc.mutex.Unlock()
+
+	if oc.client == nil {
+		return nil
+	}
+	err := oc.client.Close()
+	oc.client = nil
+	return err
+}
+
+// GetOffset returns the offset of a partition at a point in time using the
+// sarama client behind Offsets. See OffsetClient.GetOffset for the semantics.
+func (c *Client) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	if c.Offsets == nil {
+		return 0, fmt.Errorf("client not connected")
+	}
+	return c.Offsets.GetOffset(topic, partition, time)
+}
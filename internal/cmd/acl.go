@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nipunap/kim/internal/config"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/internal/manager"
+	"github.com/nipunap/kim/internal/ui"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/spf13/cobra"
+)
+
+// NewACLCmd creates the acl command
+func NewACLCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "acl",
+		Short: "Manage Kafka ACLs",
+		Long:  "Commands for listing, creating and deleting Kafka access control lists.",
+	}
+
+	cmd.AddCommand(NewACLListCmd(cfg, log))
+	cmd.AddCommand(NewACLCreateCmd(cfg, log))
+	cmd.AddCommand(NewACLDeleteCmd(cfg, log))
+
+	return cmd
+}
+
+// aclFlags holds the flags describing a single ACL for acl create and acl delete
+type aclFlags struct {
+	principal       string
+	host            string
+	operation       string
+	permission      string
+	patternType     string
+	topic           string
+	group           string
+	transactionalID string
+	cluster         bool
+}
+
+// register adds the ACL flags to the command
+func (f *aclFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.principal, "principal", "", "principal the ACL applies to, e.g. User:alice (required)")
+	cmd.Flags().StringVar(&f.host, "host", "*", "host the ACL applies to")
+	cmd.Flags().StringVar(&f.operation, "operation", "", "operation (read, write, create, delete, alter, describe, all, ...) (required)")
+	cmd.Flags().StringVar(&f.permission, "permission", "allow", "permission type (allow, deny)")
+	cmd.Flags().StringVar(&f.patternType, "pattern-type", "literal", "resource pattern type (literal, prefixed)")
+	cmd.Flags().StringVar(&f.topic, "topic", "", "topic the ACL applies to")
+	cmd.Flags().StringVar(&f.group, "group", "", "consumer group the ACL applies to")
+	cmd.Flags().StringVar(&f.transactionalID, "transactional-id", "", "transactional ID the ACL applies to")
+	cmd.Flags().BoolVar(&f.cluster, "cluster", false, "apply the ACL to the cluster")
+
+	cmd.MarkFlagRequired("principal")
+	cmd.MarkFlagRequired("operation")
+}
+
+// binding builds the ACL described by the flags, which must name exactly one resource
+func (f *aclFlags) binding() (*types.ACLBinding, error) {
+	binding := &types.ACLBinding{
+		PatternType: f.patternType,
+		Principal:   f.principal,
+		Host:        f.host,
+		Operation:   f.operation,
+		Permission:  f.permission,
+	}
+
+	resources := 0
+	if f.topic != "" {
+		binding.ResourceType, binding.ResourceName = "topic", f.topic
+		resources++
+	}
+	if f.group != "" {
+		binding.ResourceType, binding.ResourceName = "group", f.group
+		resources++
+	}
+	if f.transactionalID != "" {
+		binding.ResourceType, binding.ResourceName = "transactionalid", f.transactionalID
+		resources++
+	}
+	if f.cluster {
+		binding.ResourceType = "cluster"
+		resources++
+	}
+
+	if resources != 1 {
+		return nil, fmt.Errorf("exactly one of --topic, --group, --transactional-id or --cluster is required")
+	}
+
+	return binding, nil
+}
+
+// NewACLListCmd creates the acl list command
+func NewACLListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		principal string
+		topic     string
+		group     string
+		format    string
+		tmpl      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List Kafka ACLs",
+		Long: `List ACLs, optionally filtered by principal, topic or consumer group.
+
+Filtering by topic or group also lists prefixed and wildcard ACLs that apply to it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("format") {
+				format = defaultFormat(cfg)
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create ACL manager
+			aclManager := manager.NewACLManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// List ACLs
+			filter := &types.ACLFilter{
+				Principal: principal,
+				Topic:     topic,
+				Group:     group,
+			}
+			acls, err := aclManager.ListACLs(ctx, filter)
+			if err != nil {
+				return fmt.Errorf("failed to list ACLs: %w", err)
+			}
+
+			// Display results
+			displayOpts := newDisplayOptions(format, tmpl)
+
+			return ui.DisplayACLList(acls, displayOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&principal, "principal", "", "only list ACLs for this principal, e.g. User:alice")
+	cmd.Flags().StringVar(&topic, "topic", "", "only list ACLs that apply to this topic")
+	cmd.Flags().StringVar(&group, "group", "", "only list ACLs that apply to this consumer group")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// NewACLCreateCmd creates the acl create command
+func NewACLCreateCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var flags aclFlags
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a Kafka ACL",
+		Long:  "Create an ACL allowing or denying a principal an operation on a topic, group, transactional ID or the cluster.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			binding, err := flags.binding()
+			if err != nil {
+				return err
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create ACL manager
+			aclManager := manager.NewACLManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Create ACL
+			if err := aclManager.CreateACL(ctx, binding); err != nil {
+				return fmt.Errorf("failed to create ACL: %w", err)
+			}
+
+			fmt.Printf("ACL created: %s\n", describeACL(binding))
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+
+	return cmd
+}
+
+// NewACLDeleteCmd creates the acl delete command
+func NewACLDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		flags aclFlags
+		force bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a Kafka ACL",
+		Long:  "Delete the ACL exactly matching the given principal, host, operation, permission and resource.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			binding, err := flags.binding()
+			if err != nil {
+				return err
+			}
+
+			// Confirm deletion unless --force or --yes is used
+			if !confirmAction(cmd, force, fmt.Sprintf("Are you sure you want to delete ACL %s? (y/N): ", describeACL(binding))) {
+				fmt.Println("ACL deletion cancelled")
+				return nil
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create ACL manager
+			aclManager := manager.NewACLManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Delete ACL
+			deleted, err := aclManager.DeleteACLs(ctx, binding)
+			if err != nil {
+				return fmt.Errorf("failed to delete ACL: %w", err)
+			}
+
+			fmt.Printf("Deleted %d ACL(s)\n", len(deleted))
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&force, "force", false, "skip confirmation prompt")
+
+	return cmd
+}
+
+// describeACL returns a one-line summary of an ACL for messages and prompts
+func describeACL(binding *types.ACLBinding) string {
+	resource := binding.ResourceType
+	if binding.ResourceName != "" {
+		resource += " '" + binding.ResourceName + "'"
+	}
+	return fmt.Sprintf("%s %s %s on %s from host %s",
+		binding.Permission, binding.Principal, binding.Operation, resource, binding.Host)
+}
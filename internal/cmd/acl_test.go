@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+func TestACLListFiltered(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	allowRead := func(principal string) sarama.Acl {
+		return sarama.Acl{Principal: principal, Host: "*", Operation: sarama.AclOperationRead, PermissionType: sarama.AclPermissionAllow}
+	}
+	mock.AddMockACL(sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "orders", ResourcePatternType: sarama.AclPatternLiteral}, allowRead("User:alice"))
+	mock.AddMockACL(sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "orders", ResourcePatternType: sarama.AclPatternLiteral}, allowRead("User:bob"))
+	mock.AddMockACL(sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "payments", ResourcePatternType: sarama.AclPatternLiteral}, allowRead("User:alice"))
+	useMockClient(t, mock)
+
+	output := captureStdout(func() {
+		if _, err := executeCommand(NewACLCmd(cfg, log), "list", "--principal", "User:alice", "--topic", "orders", "--format", "json"); err != nil {
+			t.Fatalf("acl list failed: %v", err)
+		}
+	})
+
+	var acls []*types.ACLBinding
+	if err := json.Unmarshal([]byte(output), &acls); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(acls) != 1 || acls[0].Principal != "User:alice" || acls[0].ResourceName != "orders" {
+		t.Errorf("Expected only alice's orders ACL, got %+v", acls)
+	}
+}
+
+func TestACLCreate(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	output := captureStdout(func() {
+		_, err := executeCommand(NewACLCmd(cfg, log), "create",
+			"--principal", "User:alice", "--operation", "write", "--topic", "orders-", "--pattern-type", "prefixed")
+		if err != nil {
+			t.Fatalf("acl create failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "ACL created") {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	acls := mock.MockACLs()
+	if len(acls) != 1 {
+		t.Fatalf("Expected 1 ACL, got %d", len(acls))
+	}
+	if acls[0].Resource.ResourceName != "orders-" || acls[0].Resource.ResourcePatternType != sarama.AclPatternPrefixed ||
+		acls[0].Acl.Operation != sarama.AclOperationWrite || acls[0].Acl.PermissionType != sarama.AclPermissionAllow {
+		t.Errorf("Unexpected ACL: %+v", acls[0])
+	}
+}
+
+func TestACLCreateRequiresOneResource(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewACLCmd(cfg, log), "create",
+		"--principal", "User:alice", "--operation", "read", "--topic", "orders", "--group", "orders-service")
+	if err == nil || !strings.Contains(err.Error(), "exactly one of") {
+		t.Errorf("Expected a resource error, got %v", err)
+	}
+	if len(mock.MockACLs()) != 0 {
+		t.Error("No ACL should be created")
+	}
+}
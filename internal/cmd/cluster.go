@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/nipunap/kim/internal/config"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/internal/manager"
+	"github.com/nipunap/kim/internal/ui"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/spf13/cobra"
+)
+
+// NewClusterCmd creates the cluster command
+func NewClusterCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Inspect the Kafka cluster",
+		Long:  "Commands for inspecting the Kafka cluster including brokers, broker configuration, the controller and overall health, and for electing preferred leaders.",
+	}
+
+	cmd.AddCommand(NewClusterDescribeCmd(cfg, log))
+	cmd.AddCommand(NewClusterBrokerConfigCmd(cfg, log))
+	cmd.AddCommand(NewClusterHealthCmd(cfg, log))
+	cmd.AddCommand(NewClusterElectLeadersCmd(cfg, log))
+
+	return cmd
+}
+
+// NewClusterDescribeCmd creates the cluster describe command
+func NewClusterDescribeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		format string
+		tmpl   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Describe the Kafka cluster",
+		Long:  "Show the cluster ID, the controller and all brokers in the cluster.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create cluster manager
+			clusterManager := manager.NewClusterManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Describe cluster
+			info, err := clusterManager.DescribeCluster(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to describe cluster: %w", err)
+			}
+
+			// Display results
+			displayOpts := newDisplayOptions(format, tmpl)
+
+			return ui.DisplayClusterInfo(info, displayOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// NewClusterBrokerConfigCmd creates the cluster broker-config command
+func NewClusterBrokerConfigCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		format string
+		tmpl   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "broker-config BROKER_ID",
+		Short: "Show the configuration of a broker",
+		Long:  "Show all configuration entries of a broker and whether each value is a default or has been overridden.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			brokerID, err := strconv.ParseInt(args[0], 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid broker ID: %s", args[0])
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create cluster manager
+			clusterManager := manager.NewClusterManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Describe broker config
+			brokerConfig, err := clusterManager.DescribeBrokerConfig(ctx, int32(brokerID))
+			if err != nil {
+				return fmt.Errorf("failed to describe broker config: %w", err)
+			}
+
+			// Display results
+			displayOpts := newDisplayOptions(format, tmpl)
+
+			return ui.DisplayBrokerConfig(brokerConfig, displayOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// NewClusterHealthCmd creates the cluster health command
+func NewClusterHealthCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		format string
+		tmpl   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Check the health of the Kafka cluster",
+		Long: `Check the brokers, the controller and the partitions of all topics and report
+a pass, warn or fail status.
+
+The status is "warn" when partitions are under-replicated and "fail" when
+partitions are offline or there is no controller. The command exits with a
+non-zero status unless the check passes.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create cluster manager
+			clusterManager := manager.NewClusterManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Check health
+			health, err := clusterManager.Health(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to check cluster health: %w", err)
+			}
+
+			// Display results
+			displayOpts := newDisplayOptions(format, tmpl)
+			if err := ui.DisplayClusterHealth(health, displayOpts); err != nil {
+				return err
+			}
+
+			if health.Status != types.HealthPass {
+				// The report explains the problem, so skip the usage text
+				cmd.SilenceUsage = true
+				return types.NewKimError(types.ErrCodeClusterUnhealthy, fmt.Sprintf("cluster health is %s", health.Status))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// NewClusterElectLeadersCmd creates the cluster elect-leaders command
+func NewClusterElectLeadersCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		topic      string
+		partitions []int32
+		format     string
+		tmpl       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "elect-leaders",
+		Short: "Move partition leadership back to the preferred replicas",
+		Long: `Run a preferred leader election, moving the leadership of each partition back
+to the first replica in its replica list. This rebalances leadership after
+broker restarts have skewed it.
+
+Every partition in the cluster is elected unless --topic is given. Use
+--partition with --topic to elect only some partitions of the topic. The
+partitions whose leader changed are reported; partitions already led by their
+preferred replica are left alone.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(partitions) > 0 && topic == "" {
+				return fmt.Errorf("--partition requires --topic")
+			}
+
+			topicPartitions := make(map[string][]int32)
+			if topic != "" {
+				topicPartitions[topic] = partitions
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create cluster manager
+			clusterManager := manager.NewClusterManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Elect leaders
+			election, err := clusterManager.ElectLeaders(ctx, topicPartitions)
+			if err != nil {
+				return fmt.Errorf("failed to elect leaders: %w", err)
+			}
+
+			// Display results
+			displayOpts := newDisplayOptions(format, tmpl)
+			if err := ui.DisplayLeaderElection(election, displayOpts); err != nil {
+				return err
+			}
+
+			if len(election.Failed) > 0 {
+				// The report lists the failed partitions, so skip the usage text
+				cmd.SilenceUsage = true
+				return fmt.Errorf("leader election failed for %d partition(s)", len(election.Failed))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&topic, "topic", "", "only elect leaders for this topic")
+	cmd.Flags().Int32SliceVar(&partitions, "partition", nil, "only elect leaders for these partitions of --topic")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
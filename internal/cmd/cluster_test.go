@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+)
+
+func TestClusterHealthUnderReplicated(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockBroker(0, "broker-0:9092")
+	mock.AddMockBroker(1, "broker-1:9092")
+	mock.AddMockTopic("orders", 2, 2)
+	orders, _ := mock.MockTopic("orders")
+	orders.Partitions[0].Isr = []int32{0}
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewClusterCmd(cfg, log), "health")
+	})
+
+	if !strings.Contains(output, "WARN") || !strings.Contains(output, "orders") {
+		t.Errorf("Expected a warn report naming orders, got:\n%s", output)
+	}
+	if code := ExitCode(err); code != 2 {
+		t.Errorf("Expected exit code 2, got %d (%v)", code, err)
+	}
+}
+
+func TestClusterElectLeaders(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 2, 2)
+	mock.AddMockTopic("payments", 1, 2)
+	orders, _ := mock.MockTopic("orders")
+	orders.Partitions[1].Leader = 1
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewClusterCmd(cfg, log), "elect-leaders", "--topic", "orders", "--partition", "1")
+	})
+	if err != nil {
+		t.Fatalf("elect-leaders failed: %v", err)
+	}
+
+	if !strings.Contains(output, "Elected preferred leaders for 1 of 1 partition(s)") {
+		t.Errorf("Expected an election summary, got:\n%s", output)
+	}
+	elections := mock.Elections()
+	if len(elections) != 1 || len(elections[0].Partitions) != 1 || elections[0].Partitions["orders"][0] != 1 {
+		t.Errorf("Expected only orders partition 1 to be targeted, got %+v", elections)
+	}
+
+	if _, err := executeCommand(NewClusterCmd(cfg, log), "elect-leaders", "--partition", "1"); err == nil {
+		t.Error("Expected --partition without --topic to fail")
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nipunap/kim/internal/config"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/internal/manager"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/spf13/cobra"
+)
+
+// topicCacheTTL is how long the topic names used for completion are reused
+const topicCacheTTL = 5 * time.Minute
+
+// NewCompletionCmd creates the completion command
+func NewCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a shell completion script for kim.
+
+Bash:
+  source <(kim completion bash)
+
+Zsh:
+  kim completion zsh > "${fpath[1]}/_kim"
+
+Fish:
+  kim completion fish > ~/.config/fish/completions/kim.fish
+
+PowerShell:
+  kim completion powershell | Out-String | Invoke-Expression`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			default:
+				return fmt.Errorf("unsupported shell: %s", args[0])
+			}
+		},
+	}
+
+	return cmd
+}
+
+// completeProfileNames completes the first argument with configured profile names
+func completeProfileNames(cfg *config.Config) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names := cfg.ListProfiles()
+		sort.Strings(names)
+
+		return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeTopicNames completes the first argument with topic names of the active
+// profile. Names are cached on disk for topicCacheTTL so repeated completions do
+// not reconnect; any failure simply yields no suggestions.
+func completeTopicNames(cfg *config.Config, log *logger.Logger) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		profile, err := cfg.GetActiveProfile()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		cachePath := ""
+		if dir := cfg.Dir(); dir != "" {
+			cachePath = filepath.Join(dir, "cache", "topics-"+profile.Name)
+			if names, ok := readTopicCache(cachePath); ok {
+				return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+
+		// Completion output is read by the shell, so keep informational logs out of it
+		log.SetLevel("error")
+
+		names, err := fetchTopicNames(profile, log)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if cachePath != "" {
+			writeTopicCache(cachePath, names)
+		}
+
+		return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// fetchTopicNames lists the names of all topics in the profile's cluster
+func fetchTopicNames(profile *config.Profile, log *logger.Logger) ([]string, error) {
+	clientManager := newClientManager(log)
+	kafkaClient, err := clientManager.GetClient(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer kafkaClient.Close()
+
+	topicManager := manager.NewTopicManager(kafkaClient, log)
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	topicList, err := topicManager.ListTopics(ctx, &types.ListOptions{
+		Page:     1,
+		PageSize: 10000,
+		SortBy:   "name",
+		Order:    "asc",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(topicList.Topics))
+	for _, topic := range topicList.Topics {
+		names = append(names, topic.Name)
+	}
+	return names, nil
+}
+
+// readTopicCache returns the cached topic names if the cache is still fresh
+func readTopicCache(path string) ([]string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > topicCacheTTL {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	return strings.Fields(string(data)), true
+}
+
+// writeTopicCache stores topic names for later completions, ignoring failures
+func writeTopicCache(path string, names []string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	os.WriteFile(path, []byte(strings.Join(names, "\n")), 0644)
+}
+
+// filterPrefix returns the values starting with prefix
+func filterPrefix(values []string, prefix string) []string {
+	var matches []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			matches = append(matches, value)
+		}
+	}
+	return matches
+}
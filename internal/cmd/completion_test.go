@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nipunap/kim/internal/testutil"
+)
+
+func TestCompletionBash(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	output, err := executeCommand(NewRootCmd(cfg, log), "completion", "bash")
+	if err != nil {
+		t.Fatalf("completion bash failed: %v", err)
+	}
+	if output == "" {
+		t.Fatal("completion bash should produce a script")
+	}
+	if !strings.Contains(output, "__start_kim") {
+		t.Errorf("Expected a bash completion script for kim, got:\n%s", output)
+	}
+}
+
+func TestCompletionInvalidShell(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	if _, err := executeCommand(NewRootCmd(cfg, log), "completion", "tcsh"); err == nil {
+		t.Error("completion should reject unsupported shells")
+	}
+}
+
+func TestProfileNameCompletion(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	output, err := executeCommand(NewRootCmd(cfg, log), "__complete", "profile", "use", "test-")
+	if err != nil {
+		t.Fatalf("__complete failed: %v", err)
+	}
+
+	lines := strings.Split(output, "\n")
+	if len(lines) < 2 || lines[0] != "test-kafka" || lines[1] != "test-msk" {
+		t.Errorf("Expected sorted profile names, got:\n%s", output)
+	}
+}
+
+func TestTopicNameCompletion(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockTopic("orders-dlq", 1, 1)
+	mock.AddMockTopic("payments", 1, 1)
+	useMockClient(t, mock)
+
+	output, err := executeCommand(NewRootCmd(cfg, log), "__complete", "topic", "describe", "ord")
+	if err != nil {
+		t.Fatalf("__complete failed: %v", err)
+	}
+
+	if !strings.Contains(output, "orders\norders-dlq\n") {
+		t.Errorf("Expected matching topic names, got:\n%s", output)
+	}
+	if strings.Contains(output, "payments") {
+		t.Errorf("Non-matching topics should not be suggested, got:\n%s", output)
+	}
+}
+
+func TestTopicCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "topics-test")
+
+	if _, ok := readTopicCache(path); ok {
+		t.Fatal("Missing cache should not be used")
+	}
+
+	writeTopicCache(path, []string{"a", "b"})
+
+	names, ok := readTopicCache(path)
+	if !ok || strings.Join(names, ",") != "a,b" {
+		t.Errorf("Expected cached names [a b], got %v (fresh: %v)", names, ok)
+	}
+
+	// Stale caches are ignored
+	stale := time.Now().Add(-2 * topicCacheTTL)
+	if err := os.Chtimes(path, stale, stale); err != nil {
+		t.Fatalf("Failed to age cache: %v", err)
+	}
+	if _, ok := readTopicCache(path); ok {
+		t.Error("Stale cache should not be used")
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nipunap/kim/internal/config"
+	"github.com/nipunap/kim/internal/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// NewConfigCmd creates the config command
+func NewConfigCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage application settings",
+		Long:  "Commands for reading and changing application settings such as the page size and default output format.",
+	}
+
+	cmd.AddCommand(NewConfigSetCmd(cfg, log))
+	cmd.AddCommand(NewConfigGetCmd(cfg, log))
+
+	return cmd
+}
+
+// NewConfigSetCmd creates the config set command
+func NewConfigSetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Change a setting",
+		Long: `Change a setting and save the configuration.
+
+Available settings:
+  page_size          Number of items per page (positive number)
+  refresh_interval   Refresh interval in seconds (positive number)
+  default_format     Default output format (table, json, yaml)
+  color_scheme       Color scheme name
+  vim_mode           Enable vim key bindings (true, false)`,
+		Args:      cobra.ExactArgs(2),
+		ValidArgs: config.SettingKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+
+			if cfg.Settings == nil {
+				cfg.Settings = &config.Settings{}
+			}
+
+			if err := cfg.Settings.Set(key, value); err != nil {
+				return err
+			}
+
+			// Save configuration
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save configuration: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Set %s = %s\n", key, value)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// NewConfigGetCmd creates the config get command
+func NewConfigGetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "get [KEY]",
+		Short:     "Show settings",
+		Long:      "Show the value of a setting, or of all settings if no key is given.",
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: config.SettingKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Settings == nil {
+				cfg.Settings = &config.Settings{}
+			}
+
+			if len(args) == 1 {
+				value, err := cfg.Settings.Get(args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), value)
+				return nil
+			}
+
+			for _, key := range config.SettingKeys {
+				value, err := cfg.Settings.Get(key)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-18s %s\n", key, value)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// defaultPageSize returns the page size from the settings, used by list
+// commands when --page-size is not given. It is resolved when the command runs
+// so that a --config file is honored.
+func defaultPageSize(cfg *config.Config) int {
+	if cfg.Settings != nil && cfg.Settings.PageSize > 0 {
+		return cfg.Settings.PageSize
+	}
+	return 20
+}
+
+// defaultFormat returns the output format from the settings, used by list
+// commands when --format is not given
+func defaultFormat(cfg *config.Config) string {
+	if cfg.Settings != nil && cfg.Settings.DefaultFormat != "" {
+		return cfg.Settings.DefaultFormat
+	}
+	return "table"
+}
+
+// defaultRefreshInterval returns the refresh interval from the settings, used
+// by --watch when --interval is not given
+func defaultRefreshInterval(cfg *config.Config) time.Duration {
+	if cfg.Settings != nil && cfg.Settings.RefreshInterval > 0 {
+		return time.Duration(cfg.Settings.RefreshInterval) * time.Second
+	}
+	return 10 * time.Second
+}
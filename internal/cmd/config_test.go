@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+)
+
+func TestConfigSetCommand(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	_, err := executeCommand(NewConfigCmd(cfg, log), "set", "page_size", "50")
+	if cfg.Settings.PageSize != 50 {
+		t.Fatalf("page_size was not updated. Got: %d, Error: %v", cfg.Settings.PageSize, err)
+	}
+
+	_, err = executeCommand(NewConfigCmd(cfg, log), "set", "default_format", "json")
+	if cfg.Settings.DefaultFormat != "json" {
+		t.Fatalf("default_format was not updated. Got: %s, Error: %v", cfg.Settings.DefaultFormat, err)
+	}
+}
+
+func TestConfigSetCommandInvalidValue(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{"page_size", "0"},
+		{"page_size", "many"},
+		{"default_format", "xml"},
+		{"vim_mode", "maybe"},
+		{"unknown_key", "1"},
+	}
+
+	for _, tt := range tests {
+		if _, err := executeCommand(NewConfigCmd(cfg, log), "set", tt.key, tt.value); err == nil {
+			t.Errorf("Setting %s to %q should fail", tt.key, tt.value)
+		}
+	}
+
+	// Invalid values leave the settings untouched
+	if cfg.Settings.PageSize != 20 || cfg.Settings.DefaultFormat != "table" || cfg.Settings.VimMode {
+		t.Errorf("Settings should be unchanged, got %+v", cfg.Settings)
+	}
+}
+
+func TestConfigGetCommand(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	output, err := executeCommand(NewConfigCmd(cfg, log), "get", "page_size")
+	if err != nil {
+		t.Fatalf("config get failed: %v", err)
+	}
+	if strings.TrimSpace(output) != "20" {
+		t.Errorf("Expected 20, got %q", output)
+	}
+
+	output, err = executeCommand(NewConfigCmd(cfg, log), "get")
+	if err != nil {
+		t.Fatalf("config get failed: %v", err)
+	}
+	for _, key := range []string{"page_size", "refresh_interval", "default_format", "color_scheme", "vim_mode"} {
+		if !strings.Contains(output, key) {
+			t.Errorf("Expected setting %s in output, got:\n%s", key, output)
+		}
+	}
+
+	if _, err := executeCommand(NewConfigCmd(cfg, log), "get", "unknown_key"); err == nil {
+		t.Error("Getting an unknown setting should fail")
+	}
+}
+
+func TestListCommandsUseSettingsDefaults(t *testing.T) {
+	cfg := testutil.TestConfig()
+	cfg.Settings.DefaultFormat = "json"
+	cfg.Settings.PageSize = 1
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockTopic("payments", 1, 1)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "list")
+	})
+	if err != nil {
+		t.Fatalf("topic list failed: %v", err)
+	}
+
+	var topicList types.TopicList
+	if err := json.Unmarshal([]byte(output), &topicList); err != nil {
+		t.Fatalf("Expected JSON output from settings default, got %q: %v", output, err)
+	}
+	if len(topicList.Topics) != 1 || topicList.Pagination.PageSize != 1 {
+		t.Errorf("Expected page size 1 from settings, got %d topics (page size %d)",
+			len(topicList.Topics), topicList.Pagination.PageSize)
+	}
+
+	// Flags still override the settings
+	output = captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "list", "--format", "table", "--page-size", "10")
+	})
+	if err != nil {
+		t.Fatalf("topic list failed: %v", err)
+	}
+	if !strings.Contains(output, "orders") || !strings.Contains(output, "payments") || strings.HasPrefix(output, "{") {
+		t.Errorf("Expected table output with both topics, got:\n%s", output)
+	}
+}
+
+func TestListCommandsUseSettingsFromConfigFlag(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Cleanup(func() { cfgFile = "" })
+
+	configPath := filepath.Join(t.TempDir(), "kim.yaml")
+	if err := os.WriteFile(configPath, []byte(`active_profile: from-file
+profiles:
+  from-file:
+    name: from-file
+    type: kafka
+    bootstrap_servers: localhost:9092
+settings:
+  page_size: 1
+  default_format: json
+`), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockTopic("payments", 1, 1)
+	useMockClient(t, mock)
+
+	// The settings are only known once --config has been loaded
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewRootCmd(cfg, log), "--config", configPath, "topic", "list")
+	})
+	if err != nil {
+		t.Fatalf("topic list failed: %v", err)
+	}
+
+	var topicList types.TopicList
+	if err := json.Unmarshal([]byte(output), &topicList); err != nil {
+		t.Fatalf("Expected JSON output from the --config settings, got %q: %v", output, err)
+	}
+	if len(topicList.Topics) != 1 || topicList.Pagination.PageSize != 1 {
+		t.Errorf("Expected page size 1 from the --config settings, got %d topics (page size %d)",
+			len(topicList.Topics), topicList.Pagination.PageSize)
+	}
+}
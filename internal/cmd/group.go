@@ -2,10 +2,16 @@ package cmd
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/config"
 	"github.com/nipunap/kim/internal/logger"
 	"github.com/nipunap/kim/internal/manager"
@@ -25,8 +31,10 @@ func NewGroupCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 
 	cmd.AddCommand(NewGroupListCmd(cfg, log))
 	cmd.AddCommand(NewGroupDescribeCmd(cfg, log))
+	cmd.AddCommand(NewGroupMembersCmd(cfg, log))
 	cmd.AddCommand(NewGroupDeleteCmd(cfg, log))
 	cmd.AddCommand(NewGroupResetCmd(cfg, log))
+	cmd.AddCommand(NewGroupOffsetsCmd(cfg, log))
 
 	return cmd
 }
@@ -35,11 +43,15 @@ func NewGroupCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 func NewGroupListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	var (
 		pattern  string
+		exclude  []string
 		page     int
 		pageSize int
 		sortBy   string
 		order    string
 		format   string
+		tmpl     string
+		states   []string
+		watch    watchFlags
 	)
 
 	cmd := &cobra.Command{
@@ -47,6 +59,13 @@ func NewGroupListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 		Short: "List Kafka consumer groups",
 		Long:  "List all Kafka consumer groups with optional filtering and pagination.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("format") {
+				format = defaultFormat(cfg)
+			}
+			if !cmd.Flags().Changed("page-size") {
+				pageSize = defaultPageSize(cfg)
+			}
+
 			// Get active profile
 			profile, err := cfg.GetActiveProfile()
 			if err != nil {
@@ -54,7 +73,7 @@ func NewGroupListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -69,43 +88,54 @@ func NewGroupListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 				Page:     page,
 				PageSize: pageSize,
 				Pattern:  pattern,
+				Exclude:  exclude,
 				SortBy:   sortBy,
 				Order:    order,
+				States:   states,
 			}
 
-			groupList, err := groupManager.ListGroups(context.Background(), opts)
-			if err != nil {
-				return fmt.Errorf("failed to list consumer groups: %w", err)
-			}
+			displayOpts := newDisplayOptions(format, tmpl)
 
-			// Display results
-			displayOpts := &types.DisplayOptions{
-				Format: format,
-			}
-
-			return ui.DisplayGroupList(groupList, displayOpts)
+			return watch.run(cfg, func(ctx context.Context) error {
+				groupList, err := groupManager.ListGroups(ctx, opts)
+				if err != nil {
+					return fmt.Errorf("failed to list consumer groups: %w", err)
+				}
+				return ui.DisplayGroupList(groupList, displayOpts)
+			})
 		},
 	}
 
 	cmd.Flags().StringVar(&pattern, "pattern", "", "filter groups by pattern (supports wildcards)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "hide groups matching this pattern (repeatable)")
+	cmd.Flags().StringSliceVar(&states, "state", nil, "only list groups in these states (Stable, Empty, Rebalancing, Dead); repeatable")
+	watch.register(cmd)
 	cmd.Flags().IntVar(&page, "page", 1, "page number")
 	cmd.Flags().IntVar(&pageSize, "page-size", 20, "number of groups per page")
-	cmd.Flags().StringVar(&sortBy, "sort-by", "group_id", "sort by field (group_id, state, protocol_type)")
+	cmd.Flags().StringVar(&sortBy, "sort-by", "group_id", "sort by field (group_id, state, protocol_type, lag)")
 	cmd.Flags().StringVar(&order, "order", "asc", "sort order (asc, desc)")
-	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml)")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
 
 	return cmd
 }
 
 // NewGroupDescribeCmd creates the group describe command
 func NewGroupDescribeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
-	var format string
+	var (
+		format       string
+		tmpl         string
+		lagThreshold int64
+	)
 
 	cmd := &cobra.Command{
 		Use:   "describe GROUP_ID",
 		Short: "Describe a Kafka consumer group",
-		Long:  "Show detailed information about a specific Kafka consumer group including members and lag information.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show detailed information about a specific Kafka consumer group including members and lag information.
+
+With --lag-threshold N, partitions whose lag exceeds N are highlighted and the command
+exits with a non-zero status, for use in monitoring scripts.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			groupID := args[0]
 
@@ -116,7 +146,7 @@ func NewGroupDescribeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -126,47 +156,133 @@ func NewGroupDescribeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 			// Create group manager
 			groupManager := manager.NewGroupManager(kafkaClient, log)
 
+			ctx, cancel := commandContext()
+			defer cancel()
+
 			// Describe group
-			groupDetails, err := groupManager.DescribeGroup(context.Background(), groupID)
+			groupDetails, err := groupManager.DescribeGroup(ctx, groupID)
 			if err != nil {
 				return fmt.Errorf("failed to describe consumer group: %w", err)
 			}
 
 			// Display results
-			displayOpts := &types.DisplayOptions{
-				Format: format,
+			displayOpts := newDisplayOptions(format, tmpl)
+			displayOpts.LagThreshold = lagThreshold
+			if err := ui.DisplayGroupDetails(groupDetails, displayOpts); err != nil {
+				return err
 			}
 
-			return ui.DisplayGroupDetails(groupDetails, displayOpts)
+			if lagThreshold > 0 {
+				// Check every committed offset rather than only the member
+				// assignments, so a group whose consumers are gone still fails
+				offsets, err := groupManager.ExportOffsets(ctx, groupID)
+				if err != nil {
+					return fmt.Errorf("failed to check consumer lag: %w", err)
+				}
+				if exceeded := lagAboveThreshold(offsets, lagThreshold); len(exceeded) > 0 {
+					// The report shows the lag, so skip the usage text
+					cmd.SilenceUsage = true
+					return types.NewKimError(types.ErrCodeLagThresholdExceeded,
+						fmt.Sprintf("lag exceeds %d on %s", lagThreshold, strings.Join(exceeded, ", ")))
+				}
+			}
+			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml)")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+	cmd.Flags().Int64Var(&lagThreshold, "lag-threshold", 0, "fail if any partition's lag exceeds this value (0 = no check)")
 
 	return cmd
 }
 
-// NewGroupDeleteCmd creates the group delete command
-func NewGroupDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
-	var force bool
+// lagAboveThreshold returns the partitions of a group, as topic-partition, whose
+// committed offset lags by more than threshold
+func lagAboveThreshold(offsets *types.GroupOffsets, threshold int64) []string {
+	var exceeded []string
+	for _, offset := range offsets.Offsets {
+		if offset.Lag > threshold {
+			exceeded = append(exceeded, fmt.Sprintf("%s-%d (lag %d)", offset.Topic, offset.Partition, offset.Lag))
+		}
+	}
+	return exceeded
+}
+
+// NewGroupMembersCmd creates the group members command
+func NewGroupMembersCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		format string
+		tmpl   string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "delete GROUP_ID",
-		Short: "Delete a Kafka consumer group",
-		Long:  "Delete an existing Kafka consumer group. The group must be empty (no active consumers).",
+		Use:   "members GROUP_ID",
+		Short: "List the members of a Kafka consumer group",
+		Long:  "List the members of a consumer group with their client ID, host and number of assigned partitions.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			groupID := args[0]
 
-			// Confirm deletion unless force flag is used
-			if !force {
-				fmt.Printf("Are you sure you want to delete consumer group '%s'? (y/N): ", groupID)
-				var response string
-				fmt.Scanln(&response)
-				if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-					fmt.Println("Consumer group deletion cancelled")
-					return nil
-				}
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create group manager
+			groupManager := manager.NewGroupManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Describe group
+			groupDetails, err := groupManager.DescribeGroup(ctx, groupID)
+			if err != nil {
+				return fmt.Errorf("failed to describe consumer group: %w", err)
+			}
+
+			// Display results
+			displayOpts := newDisplayOptions(format, tmpl)
+
+			return ui.DisplayGroupMembers(groupDetails.Members, displayOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// NewGroupDeleteCmd creates the group delete command
+func NewGroupDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "delete GROUP_ID...",
+		Short: "Delete Kafka consumer groups",
+		Long: `Delete one or more existing Kafka consumer groups. The groups must be empty (no active consumers).
+
+A failure to delete one group does not stop the others; a summary is printed at the end.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Confirm deletion unless --force or --yes is used
+			prompt := fmt.Sprintf("Are you sure you want to delete consumer group '%s'? (y/N): ", args[0])
+			if len(args) > 1 {
+				prompt = fmt.Sprintf("Are you sure you want to delete %d consumer groups (%s)? (y/N): ", len(args), strings.Join(args, ", "))
+			}
+			if !confirmAction(cmd, force, prompt) {
+				fmt.Println("Consumer group deletion cancelled")
+				return nil
 			}
 
 			// Get active profile
@@ -176,7 +292,7 @@ func NewGroupDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -186,12 +302,38 @@ func NewGroupDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			// Create group manager
 			groupManager := manager.NewGroupManager(kafkaClient, log)
 
-			// Delete group
-			if err := groupManager.DeleteGroup(context.Background(), groupID); err != nil {
-				return fmt.Errorf("failed to delete consumer group: %w", err)
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Delete a single group
+			if len(args) == 1 {
+				if err := groupManager.DeleteGroup(ctx, args[0]); err != nil {
+					return fmt.Errorf("failed to delete consumer group: %w", err)
+				}
+
+				fmt.Printf("Consumer group '%s' deleted successfully\n", args[0])
+				return nil
 			}
 
-			fmt.Printf("Consumer group '%s' deleted successfully\n", groupID)
+			// Delete several groups and summarize the results
+			results := groupManager.DeleteGroups(ctx, args)
+
+			failed := 0
+			fmt.Printf("%-40s %s\n", "GROUP ID", "RESULT")
+			fmt.Println(strings.Repeat("-", 80))
+			for _, result := range results {
+				if result.Error != "" {
+					failed++
+					fmt.Printf("%-40s failed: %s\n", result.GroupID, result.Error)
+				} else {
+					fmt.Printf("%-40s deleted\n", result.GroupID)
+				}
+			}
+			fmt.Printf("\nDeleted %d of %d consumer groups\n", len(results)-failed, len(results))
+
+			if failed > 0 {
+				return fmt.Errorf("failed to delete %d of %d consumer groups", failed, len(results))
+			}
 			return nil
 		},
 	}
@@ -208,14 +350,16 @@ func NewGroupResetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 		toEarliest bool
 		toLatest   bool
 		toOffset   int64
+		toDateTime string
 		force      bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "reset GROUP_ID",
 		Short: "Reset consumer group offsets",
-		Long:  "Reset consumer group offsets to earliest, latest, or a specific offset.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Reset consumer group offsets to earliest, latest, a specific offset, or the first
+message at or after a point in time. The group must have no active members.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			groupID := args[0]
 
@@ -230,23 +374,30 @@ func NewGroupResetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			if cmd.Flags().Changed("to-offset") {
 				resetOptions++
 			}
+			if toDateTime != "" {
+				resetOptions++
+			}
 
 			if resetOptions == 0 {
-				return fmt.Errorf("must specify one of: --to-earliest, --to-latest, or --to-offset")
+				return fmt.Errorf("must specify one of: --to-earliest, --to-latest, --to-offset, or --to-datetime")
 			}
 			if resetOptions > 1 {
 				return fmt.Errorf("can only specify one reset option")
 			}
 
-			// Confirm reset unless force flag is used
-			if !force {
-				fmt.Printf("Are you sure you want to reset offsets for consumer group '%s'? (y/N): ", groupID)
-				var response string
-				fmt.Scanln(&response)
-				if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-					fmt.Println("Offset reset cancelled")
-					return nil
+			var resetTime *time.Time
+			if toDateTime != "" {
+				t, err := time.Parse(time.RFC3339, toDateTime)
+				if err != nil {
+					return fmt.Errorf("invalid --to-datetime %s (expected RFC3339, e.g. 2024-01-02T15:04:05Z)", toDateTime)
 				}
+				resetTime = &t
+			}
+
+			// Confirm reset unless --force or --yes is used
+			if !confirmAction(cmd, force, fmt.Sprintf("Are you sure you want to reset offsets for consumer group '%s'? (y/N): ", groupID)) {
+				fmt.Println("Offset reset cancelled")
+				return nil
 			}
 
 			// Get active profile
@@ -256,7 +407,7 @@ func NewGroupResetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -266,12 +417,16 @@ func NewGroupResetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			// Create group manager
 			groupManager := manager.NewGroupManager(kafkaClient, log)
 
+			ctx, cancel := commandContext()
+			defer cancel()
+
 			// Build reset request
 			req := &types.ResetOffsetsRequest{
 				GroupID:    groupID,
 				Topics:     topics,
 				ToEarliest: toEarliest,
 				ToLatest:   toLatest,
+				ToDateTime: resetTime,
 			}
 
 			if cmd.Flags().Changed("to-offset") {
@@ -279,7 +434,7 @@ func NewGroupResetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			}
 
 			// Reset offsets
-			if err := groupManager.ResetGroupOffsets(context.Background(), req); err != nil {
+			if err := groupManager.ResetGroupOffsets(ctx, req); err != nil {
 				return fmt.Errorf("failed to reset consumer group offsets: %w", err)
 			}
 
@@ -292,7 +447,288 @@ func NewGroupResetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	cmd.Flags().BoolVar(&toEarliest, "to-earliest", false, "reset to earliest offset")
 	cmd.Flags().BoolVar(&toLatest, "to-latest", false, "reset to latest offset")
 	cmd.Flags().Int64Var(&toOffset, "to-offset", 0, "reset to specific offset")
+	cmd.Flags().StringVar(&toDateTime, "to-datetime", "", "reset to the first offset at or after a time (RFC3339)")
 	cmd.Flags().BoolVar(&force, "force", false, "skip confirmation prompt")
 
 	return cmd
 }
+
+// NewGroupOffsetsCmd creates the group offsets command
+func NewGroupOffsetsCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "offsets",
+		Short: "Manage consumer group offsets",
+		Long:  "Commands for working with the committed offsets of a consumer group.",
+	}
+
+	cmd.AddCommand(NewGroupOffsetsExportCmd(cfg, log))
+	cmd.AddCommand(NewGroupOffsetsImportCmd(cfg, log))
+
+	return cmd
+}
+
+// NewGroupOffsetsExportCmd creates the group offsets export command
+func NewGroupOffsetsExportCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		output string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export GROUP_ID",
+		Short: "Export committed offsets of a consumer group",
+		Long: `Export the committed offset, log end offset and lag of every topic partition
+a consumer group tracks, e.g. as a backup before resetting offsets.
+
+The format defaults to csv for --output files ending in .csv and json otherwise.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			groupID := args[0]
+
+			if format == "" {
+				format = "json"
+				if strings.EqualFold(filepath.Ext(output), ".csv") {
+					format = "csv"
+				}
+			}
+			if format != "json" && format != "csv" {
+				return fmt.Errorf("invalid format: %s (must be json or csv)", format)
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create group manager
+			groupManager := manager.NewGroupManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Export offsets
+			offsets, err := groupManager.ExportOffsets(ctx, groupID)
+			if err != nil {
+				return fmt.Errorf("failed to export offsets: %w", err)
+			}
+
+			if output == "" {
+				return writeGroupOffsets(cmd.OutOrStdout(), offsets, format)
+			}
+
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			if err := writeGroupOffsets(file, offsets, format); err != nil {
+				file.Close()
+				return fmt.Errorf("failed to write offsets: %w", err)
+			}
+			if err := file.Close(); err != nil {
+				return fmt.Errorf("failed to write offsets: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d offsets of group '%s' to %s\n", len(offsets.Offsets), groupID, output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write the offsets to (default is standard output)")
+	cmd.Flags().StringVar(&format, "format", "", "output format (json, csv)")
+
+	return cmd
+}
+
+// NewGroupOffsetsImportCmd creates the group offsets import command
+func NewGroupOffsetsImportCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		input  string
+		format string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import GROUP_ID",
+		Short: "Commit consumer group offsets from a file",
+		Long: `Commit the offsets saved by 'group offsets export' for a consumer group.
+
+The group must have no active members and every topic partition in the file must
+exist. The format defaults to csv for --input files ending in .csv and json otherwise.
+Use --dry-run to show the planned changes without committing them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			groupID := args[0]
+
+			if input == "" {
+				return fmt.Errorf("--input is required")
+			}
+			if format == "" {
+				format = "json"
+				if strings.EqualFold(filepath.Ext(input), ".csv") {
+					format = "csv"
+				}
+			}
+			if format != "json" && format != "csv" {
+				return fmt.Errorf("invalid format: %s (must be json or csv)", format)
+			}
+
+			file, err := os.Open(input)
+			if err != nil {
+				return fmt.Errorf("failed to open input file: %w", err)
+			}
+			defer file.Close()
+
+			offsets, err := readGroupOffsets(file, format)
+			if err != nil {
+				return fmt.Errorf("invalid offsets file: %w", err)
+			}
+			if len(offsets.Offsets) == 0 {
+				return fmt.Errorf("no offsets found in %s", input)
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create group manager
+			groupManager := manager.NewGroupManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			out := cmd.OutOrStdout()
+
+			if dryRun {
+				if err := groupManager.ValidateOffsets(ctx, groupID, offsets.Offsets); err != nil {
+					return err
+				}
+
+				current, err := groupManager.ExportOffsets(ctx, groupID)
+				if err != nil {
+					return fmt.Errorf("failed to fetch current offsets: %w", err)
+				}
+				committed := make(map[string]int64, len(current.Offsets))
+				for _, offset := range current.Offsets {
+					committed[fmt.Sprintf("%s/%d", offset.Topic, offset.Partition)] = offset.CurrentOffset
+				}
+
+				fmt.Fprintf(out, "%-40s %-10s %-15s %-15s\n", "TOPIC", "PARTITION", "CURRENT OFFSET", "NEW OFFSET")
+				fmt.Fprintln(out, strings.Repeat("-", 83))
+				for _, offset := range offsets.Offsets {
+					from := "-"
+					if value, ok := committed[fmt.Sprintf("%s/%d", offset.Topic, offset.Partition)]; ok {
+						from = strconv.FormatInt(value, 10)
+					}
+					fmt.Fprintf(out, "%-40s %-10d %-15s %-15d\n", offset.Topic, offset.Partition, from, offset.CurrentOffset)
+				}
+				fmt.Fprintf(out, "\nDry run: %d offsets would be committed for group '%s'\n", len(offsets.Offsets), groupID)
+				return nil
+			}
+
+			if err := groupManager.ImportOffsets(ctx, groupID, offsets.Offsets); err != nil {
+				return fmt.Errorf("failed to import offsets: %w", err)
+			}
+
+			fmt.Fprintf(out, "Imported %d offsets for group '%s'\n", len(offsets.Offsets), groupID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&input, "input", "f", "", "file with the offsets to commit")
+	cmd.Flags().StringVar(&format, "format", "", "input format (json, csv)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the planned changes without committing them")
+
+	return cmd
+}
+
+// readGroupOffsets reads offsets written by writeGroupOffsets. CSV files are read by
+// header name and only need the topic, partition and current_offset columns.
+func readGroupOffsets(r io.Reader, format string) (*types.GroupOffsets, error) {
+	if format == "json" {
+		var offsets types.GroupOffsets
+		if err := json.NewDecoder(r).Decode(&offsets); err != nil {
+			return nil, err
+		}
+		return &offsets, nil
+	}
+
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return &types.GroupOffsets{}, nil
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	for _, name := range []string{"topic", "partition", "current_offset"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %s", name)
+		}
+	}
+
+	offsets := &types.GroupOffsets{}
+	for i, record := range records[1:] {
+		partition, err := strconv.ParseInt(record[columns["partition"]], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid partition: %s", i+2, record[columns["partition"]])
+		}
+		offset, err := strconv.ParseInt(record[columns["current_offset"]], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid offset: %s", i+2, record[columns["current_offset"]])
+		}
+
+		offsets.Offsets = append(offsets.Offsets, &types.PartitionAssignment{
+			Topic:         record[columns["topic"]],
+			Partition:     int32(partition),
+			CurrentOffset: offset,
+		})
+	}
+	return offsets, nil
+}
+
+// writeGroupOffsets writes exported offsets as JSON or as CSV with one row per partition
+func writeGroupOffsets(w io.Writer, offsets *types.GroupOffsets, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(offsets)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"group_id", "topic", "partition", "current_offset", "log_end_offset", "lag"})
+	for _, offset := range offsets.Offsets {
+		writer.Write([]string{
+			offsets.GroupID,
+			offset.Topic,
+			strconv.Itoa(int(offset.Partition)),
+			strconv.FormatInt(offset.CurrentOffset, 10),
+			strconv.FormatInt(offset.LogEndOffset, 10),
+			strconv.FormatInt(offset.Lag, 10),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+)
+
+// newOffsetsMock creates a mock with committed offsets for orders-service
+func newOffsetsMock(t *testing.T) *testutil.MockClient {
+	t.Helper()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	mock.AddMockGroupOffset("orders-service", "orders", 0, 2)
+	mock.AddMockGroupOffset("orders-service", "payments", 0, 7)
+
+	pc := mock.Consumer().AddMockPartition("orders", 0)
+	for i := 0; i < 5; i++ {
+		pc.SendMockMessage("", "value")
+	}
+	pc = mock.Consumer().AddMockPartition("payments", 0)
+	for i := 0; i < 7; i++ {
+		pc.SendMockMessage("", "value")
+	}
+
+	useMockClient(t, mock)
+	return mock
+}
+
+func TestGroupOffsetsExportJSON(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+	newOffsetsMock(t)
+
+	path := filepath.Join(t.TempDir(), "offsets.json")
+	output, err := executeCommand(NewGroupCmd(cfg, log), "offsets", "export", "orders-service", "--output", path)
+	if err != nil {
+		t.Fatalf("group offsets export failed: %v", err)
+	}
+	if !strings.Contains(output, "Exported 2 offsets") {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+
+	var offsets types.GroupOffsets
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		t.Fatalf("Export is not valid JSON: %v\n%s", err, data)
+	}
+	if offsets.GroupID != "orders-service" || len(offsets.Offsets) != 2 || offsets.TotalLag != 3 {
+		t.Errorf("Unexpected export: %s", data)
+	}
+	if first := offsets.Offsets[0]; first.Topic != "orders" || first.CurrentOffset != 2 || first.LogEndOffset != 5 || first.Lag != 3 {
+		t.Errorf("Unexpected first offset: %+v", first)
+	}
+}
+
+func TestGroupOffsetsExportCSV(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+	newOffsetsMock(t)
+
+	path := filepath.Join(t.TempDir(), "offsets.csv")
+	if _, err := executeCommand(NewGroupCmd(cfg, log), "offsets", "export", "orders-service", "-o", path); err != nil {
+		t.Fatalf("group offsets export failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+
+	want := "group_id,topic,partition,current_offset,log_end_offset,lag\n" +
+		"orders-service,orders,0,2,5,3\n" +
+		"orders-service,payments,0,7,7,0\n"
+	if string(data) != want {
+		t.Errorf("Unexpected CSV export:\n%s", data)
+	}
+}
+
+// writeOffsetsFile writes an offsets file for import
+func writeOffsetsFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write offsets file: %v", err)
+	}
+	return path
+}
+
+func TestGroupOffsetsImport(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 2, 1)
+	useMockClient(t, mock)
+
+	path := writeOffsetsFile(t, "offsets.csv", "group_id,topic,partition,current_offset,log_end_offset,lag\n"+
+		"orders-service,orders,0,2,5,3\n"+
+		"orders-service,orders,1,4,4,0\n")
+
+	output, err := executeCommand(NewGroupCmd(cfg, log), "offsets", "import", "orders-service", "--input", path)
+	if err != nil {
+		t.Fatalf("group offsets import failed: %v", err)
+	}
+	if !strings.Contains(output, "Imported 2 offsets") {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	commits := mock.Commits()
+	if len(commits) != 1 || commits[0].Offsets["orders"][0] != 2 || commits[0].Offsets["orders"][1] != 4 {
+		t.Errorf("Unexpected commits: %+v", commits)
+	}
+}
+
+func TestGroupOffsetsImportDryRun(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockGroupOffset("orders-service", "orders", 0, 9)
+	mock.Consumer().AddMockPartition("orders", 0)
+	useMockClient(t, mock)
+
+	path := writeOffsetsFile(t, "offsets.json", `{"group_id": "orders-service", "offsets": [{"topic": "orders", "partition": 0, "current_offset": 3}]}`)
+
+	output, err := executeCommand(NewGroupCmd(cfg, log), "offsets", "import", "orders-service", "--input", path, "--dry-run")
+	if err != nil {
+		t.Fatalf("group offsets import --dry-run failed: %v", err)
+	}
+	if len(mock.Commits()) != 0 {
+		t.Error("Dry run should not commit offsets")
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "orders ") {
+			if strings.Join(strings.Fields(line), " ") != "orders 0 9 3" {
+				t.Errorf("Unexpected planned change: %q", line)
+			}
+			return
+		}
+	}
+	t.Errorf("No planned change in output:\n%s", output)
+}
+
+func TestGroupResetToDateTime(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockGroupOffset("orders-service", "orders", 0, 0)
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	pc := mock.Consumer().AddMockPartition("orders", 0)
+	for i := 0; i < 4; i++ {
+		pc.SendMockMessageAt("", "value", start.Add(time.Duration(i)*time.Hour))
+	}
+	useMockClient(t, mock)
+
+	var err error
+	captureStdout(func() {
+		_, err = executeCommand(NewGroupCmd(cfg, log), "reset", "orders-service", "--to-datetime", "2024-01-02T02:00:00Z", "--force")
+	})
+	if err != nil {
+		t.Fatalf("group reset failed: %v", err)
+	}
+
+	commits := mock.Commits()
+	if len(commits) != 1 || commits[0].Offsets["orders"][0] != 2 {
+		t.Errorf("Expected offset 2 for the parsed time to be committed, got %+v", commits)
+	}
+}
+
+func TestGroupResetToDateTimeValidation(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewGroupCmd(cfg, log), "reset", "orders-service", "--to-datetime", "2024-01-02T02:00:00Z", "--to-earliest", "--force")
+	if err == nil || !strings.Contains(err.Error(), "only specify one reset option") {
+		t.Errorf("Expected an error combining --to-datetime with --to-earliest, got %v", err)
+	}
+
+	_, err = executeCommand(NewGroupCmd(cfg, log), "reset", "orders-service", "--to-datetime", "yesterday", "--force")
+	if err == nil || !strings.Contains(err.Error(), "invalid --to-datetime") {
+		t.Errorf("Expected an error for an invalid time, got %v", err)
+	}
+
+	if len(mock.Commits()) != 0 {
+		t.Error("No offsets should be committed for invalid options")
+	}
+}
+
+func TestGroupDeleteMultiple(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockGroup("idle-service", "Empty", "consumer", 0)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewGroupCmd(cfg, log), "delete", "missing-service", "idle-service", "--force")
+	})
+	if err == nil {
+		t.Error("Expected an error reporting the failed deletion")
+	}
+
+	if _, exists := mock.MockGroup("idle-service"); exists {
+		t.Error("idle-service should have been deleted")
+	}
+	if !strings.Contains(output, "missing-service") || !strings.Contains(output, "failed:") {
+		t.Errorf("Expected the failure to be reported, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Deleted 1 of 2 consumer groups") {
+		t.Errorf("Expected a summary, got:\n%s", output)
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockGroup("orders-service", "Stable", "consumer", 2)
+	mock.SetMockMemberAssignment("orders-service", "member-0", map[string][]int32{"orders": {0, 1}})
+	mock.SetMockMemberAssignment("orders-service", "member-1", map[string][]int32{"orders": {2}})
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewGroupCmd(cfg, log), "members", "orders-service")
+	})
+	if err != nil {
+		t.Fatalf("group members failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	var rows []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "member-") {
+			rows = append(rows, strings.Join(strings.Fields(line), " "))
+		}
+	}
+	expected := []string{"member-0 client-0 host-0 2", "member-1 client-1 host-1 1"}
+	if strings.Join(rows, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected member rows %q, got %q\n%s", expected, rows, output)
+	}
+
+	output = captureStdout(func() {
+		_, err = executeCommand(NewGroupCmd(cfg, log), "members", "orders-service", "--format", "json")
+	})
+	if err != nil {
+		t.Fatalf("group members --format json failed: %v", err)
+	}
+	var members []types.MemberInfo
+	if err := json.Unmarshal([]byte(output), &members); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(members) != 2 || members[0].Host != "host-0" || len(members[0].AssignedPartitions) != 2 {
+		t.Errorf("Unexpected members: %+v", members)
+	}
+}
+
+func TestGroupDescribeLagThreshold(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockGroup("orders-service", "Stable", "consumer", 2)
+	mock.SetMockMemberAssignment("orders-service", "member-0", map[string][]int32{"orders": {0}})
+	mock.SetMockMemberAssignment("orders-service", "member-1", map[string][]int32{"payments": {0}})
+	for _, tp := range []struct {
+		topic    string
+		messages int
+	}{{"orders", 10}, {"payments", 8}} {
+		pc := mock.Consumer().AddMockPartition(tp.topic, 0)
+		for i := 0; i < tp.messages; i++ {
+			pc.SendMockMessage("", "value")
+		}
+	}
+	// orders-0 lags by 3, payments-0 by 5
+	mock.AddMockGroupOffset("orders-service", "orders", 0, 7)
+	mock.AddMockGroupOffset("orders-service", "payments", 0, 3)
+	useMockClient(t, mock)
+
+	var err error
+	captureStdout(func() {
+		_, err = executeCommand(NewGroupCmd(cfg, log), "describe", "orders-service", "--lag-threshold", "5")
+	})
+	if err != nil {
+		t.Errorf("Lag at the threshold should pass: %v", err)
+	}
+
+	captureStdout(func() {
+		_, err = executeCommand(NewGroupCmd(cfg, log), "describe", "orders-service", "--lag-threshold", "4")
+	})
+	if err == nil {
+		t.Fatal("Lag above the threshold should fail")
+	}
+	if ExitCode(err) != 7 {
+		t.Errorf("Expected exit code 7, got %d (%v)", ExitCode(err), err)
+	}
+	if !strings.Contains(err.Error(), "payments-0 (lag 5)") || strings.Contains(err.Error(), "orders-0") {
+		t.Errorf("Expected only payments-0 to be reported, got %v", err)
+	}
+}
+
+func TestGroupDescribeLagThresholdEmptyGroup(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	// Every consumer has left, but the committed offset of orders-1 lags by 6
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockGroup("orders-service", "Empty", "consumer", 0)
+	pc := mock.Consumer().AddMockPartition("orders", 1)
+	for i := 0; i < 10; i++ {
+		pc.SendMockMessage("", "value")
+	}
+	mock.AddMockGroupOffset("orders-service", "orders", 1, 4)
+	useMockClient(t, mock)
+
+	var err error
+	captureStdout(func() {
+		_, err = executeCommand(NewGroupCmd(cfg, log), "describe", "orders-service", "--lag-threshold", "5")
+	})
+	if ExitCode(err) != 7 {
+		t.Fatalf("Expected exit code 7 for an empty group that lags, got %d (%v)", ExitCode(err), err)
+	}
+	if !strings.Contains(err.Error(), "orders-1 (lag 6)") {
+		t.Errorf("Expected orders-1 to be reported, got %v", err)
+	}
+}
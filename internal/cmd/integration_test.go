@@ -23,6 +23,7 @@ func setupTestEnvironment(t *testing.T) (string, func()) {
 	// Set HOME to temp directory
 	oldHome := os.Getenv("HOME")
 	os.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
 
 	// Cleanup function
 	cleanup := func() {
@@ -185,14 +186,22 @@ func TestProfileAddSSL(t *testing.T) {
 
 	profileCmd := NewProfileCmd(cfg, log)
 
+	// SSL files must exist when the profile is added
+	dir := t.TempDir()
+	for _, name := range []string{"ca.pem", "cert.pem", "key.pem"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("pem"), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
 	// Test SSL profile add
 	output, err := executeCommand(profileCmd, "add", "test-ssl",
 		"--type", "kafka",
 		"--bootstrap-servers", "localhost:9093",
 		"--security-protocol", "SSL",
-		"--ssl-ca-file", "/path/to/ca.pem",
-		"--ssl-cert-file", "/path/to/cert.pem",
-		"--ssl-key-file", "/path/to/key.pem")
+		"--ssl-ca-file", filepath.Join(dir, "ca.pem"),
+		"--ssl-cert-file", filepath.Join(dir, "cert.pem"),
+		"--ssl-key-file", filepath.Join(dir, "key.pem"))
 	// Check if the SSL profile was actually added
 	if _, exists := cfg.Profiles["test-ssl"]; !exists {
 		t.Errorf("SSL profile 'test-ssl' was not added to config. Error: %v, Output: %s", err, output)
@@ -1,24 +1,41 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"regexp"
 	"strings"
 	"syscall"
 	"time"
 
-	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/config"
 	"github.com/nipunap/kim/internal/logger"
 	"github.com/nipunap/kim/internal/manager"
+	"github.com/nipunap/kim/internal/metrics"
+	"github.com/nipunap/kim/internal/serde"
 	"github.com/nipunap/kim/internal/ui"
 	"github.com/nipunap/kim/pkg/types"
 
 	"github.com/spf13/cobra"
 )
 
+// maxBatchLineSize is the longest line accepted in a batch file
+const maxBatchLineSize = 10 * 1024 * 1024
+
+// notifyInterrupt returns a channel receiving SIGINT/SIGTERM and a function to stop
+// notifications. Tests replace it to simulate Ctrl+C.
+var notifyInterrupt = func() (<-chan os.Signal, func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	return sigChan, func() { signal.Stop(sigChan) }
+}
+
 // NewMessageCmd creates the message command
 func NewMessageCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	cmd := &cobra.Command{
@@ -29,6 +46,10 @@ func NewMessageCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 
 	cmd.AddCommand(NewMessageProduceCmd(cfg, log))
 	cmd.AddCommand(NewMessageConsumeCmd(cfg, log))
+	cmd.AddCommand(NewMessageGetCmd(cfg, log))
+	cmd.AddCommand(NewMessageTailCmd(cfg, log))
+	cmd.AddCommand(NewMessageWhichPartitionCmd(cfg, log))
+	cmd.AddCommand(NewMessageReplayCmd(cfg, log))
 
 	return cmd
 }
@@ -36,33 +57,145 @@ func NewMessageCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 // NewMessageProduceCmd creates the message produce command
 func NewMessageProduceCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	var (
-		key       string
-		value     string
-		partition int32
-		headers   []string
-		format    string
+		key        string
+		value      string
+		valueFile  string
+		batchFile  string
+		readStdin  bool
+		tombstone  bool
+		delimiter  string
+		partition  int32
+		headers    []string
+		headerFile string
+		format     string
+		encoding   string
+		acks       string
+		codec      string
+		schemaFile string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "produce TOPIC",
 		Short: "Produce a message to a Kafka topic",
-		Long:  "Produce a message to a Kafka topic with optional key, partition, and headers.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Produce a message to a Kafka topic with optional key, partition, and headers.
+
+The value can be given with --value, read from a file with --value-file, or read from
+standard input with --stdin. Input from --stdin is split into one message per --delimiter
+(newline by default); a value file is sent as a single message unless --delimiter is set.
+
+With --batch-file each line of the file is a JSON object describing one message, e.g.
+{"key": "k1", "value": "v1", "headers": {"source": "app"}, "partition": 0}
+Messages are produced in file order, which makes replaying captured messages easy.
+
+Headers are given as --header key=value and may be repeated. A value of @path reads the
+header value from a file, which allows binary values. With --header-file each line of the
+file is a key=value header; blank lines and lines starting with # are skipped. Headers
+given with --header take precedence over the header file.
+
+Use --tombstone with --key to produce a record with a null value, which deletes the key
+from a compacted topic.
+
+Use --value-encoding base64 or hex to produce binary values; the value is decoded before
+it is sent. Lines in a batch file may set their own "value_encoding".
+
+Use --acks to choose how many acknowledgements to wait for: none (fire and forget),
+leader (the partition leader only) or all (every in-sync replica, the default).
+Use --compression to compress large payloads with gzip, snappy, lz4 or zstd.
+
+Use --schema with a JSON Schema file to validate every value before anything is sent;
+invalid payloads are rejected with the location of the offending field.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			topic := args[0]
 
-			if value == "" {
-				return fmt.Errorf("message value is required (use --value flag)")
+			sources := 0
+			for _, set := range []bool{value != "", valueFile != "", batchFile != "", readStdin} {
+				if set {
+					sources++
+				}
+			}
+			if tombstone {
+				if sources > 0 {
+					return fmt.Errorf("--tombstone cannot be used with a message value")
+				}
+				if key == "" {
+					return fmt.Errorf("message key is required for a tombstone (use --key flag)")
+				}
+			} else if sources == 0 {
+				return fmt.Errorf("message value is required (use --value, --value-file, --batch-file or --stdin flag)")
+			}
+			if sources > 1 {
+				return fmt.Errorf("only one of --value, --value-file, --batch-file or --stdin can be used")
+			}
+			if batchFile != "" && (key != "" || len(headers) > 0 || headerFile != "" || cmd.Flags().Changed("partition")) {
+				return fmt.Errorf("--key, --partition, --header and --header-file cannot be used with --batch-file")
 			}
 
-			// Parse headers
-			headerMap := make(map[string]string)
-			for _, header := range headers {
-				parts := strings.SplitN(header, "=", 2)
-				if len(parts) != 2 {
-					return fmt.Errorf("invalid header format: %s (expected key=value)", header)
+			headerMap, err := parseHeaders(headers, headerFile)
+			if err != nil {
+				return err
+			}
+
+			// Build produce requests
+			var reqs []types.ProduceRequest
+			if batchFile != "" {
+				file, err := os.Open(batchFile)
+				if err != nil {
+					return fmt.Errorf("failed to open batch file: %w", err)
+				}
+				defer file.Close()
+
+				reqs, err = parseBatchFile(file, topic)
+				if err != nil {
+					return fmt.Errorf("invalid batch file: %w", err)
+				}
+			} else {
+				values := []string{""}
+				if !tombstone {
+					var err error
+					values, err = readValues(cmd, value, valueFile, readStdin, delimiter)
+					if err != nil {
+						return err
+					}
+				}
+
+				for _, v := range values {
+					req := types.ProduceRequest{
+						Topic:     topic,
+						Key:       key,
+						Value:     v,
+						Headers:   headerMap,
+						Tombstone: tombstone,
+					}
+
+					if cmd.Flags().Changed("partition") {
+						req.Partition = &partition
+					}
+
+					reqs = append(reqs, req)
+				}
+			}
+
+			if len(reqs) == 0 {
+				return fmt.Errorf("no messages to produce")
+			}
+
+			for i := range reqs {
+				if reqs[i].ValueEncoding == "" {
+					reqs[i].ValueEncoding = encoding
+				}
+				if reqs[i].Acks == "" {
+					reqs[i].Acks = acks
+				}
+				if reqs[i].Compression == "" {
+					reqs[i].Compression = codec
+				}
+			}
+
+			if schemaFile != "" {
+				if err := validateValues(reqs, schemaFile); err != nil {
+					return err
 				}
-				headerMap[parts[0]] = parts[1]
 			}
 
 			// Get active profile
@@ -72,7 +205,7 @@ func NewMessageProduceCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -82,20 +215,20 @@ func NewMessageProduceCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 			// Create message manager
 			messageManager := manager.NewMessageManager(kafkaClient, log)
 
-			// Build produce request
-			req := &types.ProduceRequest{
-				Topic:   topic,
-				Key:     key,
-				Value:   value,
-				Headers: headerMap,
-			}
+			ctx, cancel := commandContext()
+			defer cancel()
 
-			if cmd.Flags().Changed("partition") {
-				req.Partition = &partition
+			if len(reqs) > 1 {
+				produced, err := messageManager.ProduceBatch(ctx, topic, reqs)
+				fmt.Printf("Produced %d of %d messages to topic '%s'\n", produced, len(reqs), topic)
+				if err != nil {
+					return fmt.Errorf("failed to produce messages: %w", err)
+				}
+				return nil
 			}
 
 			// Produce message
-			response, err := messageManager.ProduceMessage(context.Background(), req)
+			response, err := messageManager.ProduceMessage(ctx, &reqs[0])
 			if err != nil {
 				return fmt.Errorf("failed to produce message: %w", err)
 			}
@@ -110,38 +243,270 @@ func NewMessageProduceCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 	}
 
 	cmd.Flags().StringVar(&key, "key", "", "message key")
-	cmd.Flags().StringVar(&value, "value", "", "message value (required)")
+	cmd.Flags().StringVar(&value, "value", "", "message value")
+	cmd.Flags().StringVar(&valueFile, "value-file", "", "read the message value from a file")
+	cmd.Flags().StringVar(&batchFile, "batch-file", "", "produce messages from a file with one JSON object per line")
+	cmd.Flags().BoolVar(&readStdin, "stdin", false, "read message values from standard input")
+	cmd.Flags().BoolVar(&tombstone, "tombstone", false, "produce a tombstone (null value) for --key")
+	cmd.Flags().StringVar(&delimiter, "delimiter", "\n", "delimiter between messages read from --stdin or --value-file")
 	cmd.Flags().Int32Var(&partition, "partition", -1, "specific partition to produce to")
-	cmd.Flags().StringSliceVar(&headers, "header", nil, "message headers (key=value)")
+	cmd.Flags().StringArrayVar(&headers, "header", nil, "message header as key=value, or key=@file to read the value from a file (repeatable)")
+	cmd.Flags().StringVar(&headerFile, "header-file", "", "read message headers from a file with one key=value per line")
 	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml)")
-
-	cmd.MarkFlagRequired("value")
+	cmd.Flags().StringVar(&encoding, "value-encoding", manager.ValueEncodingRaw, "encoding of the message value (raw, base64, hex)")
+	cmd.Flags().StringVar(&acks, "acks", manager.AcksAll, "acknowledgements to wait for (none, leader, all)")
+	cmd.Flags().StringVar(&codec, "compression", manager.CompressionNone, "compression codec (none, gzip, snappy, lz4, zstd)")
+	cmd.Flags().StringVar(&schemaFile, "schema", "", "validate message values against a JSON Schema file before producing")
 
 	return cmd
 }
 
+// parseHeaders builds message headers from a header file and key=value flags.
+// A value starting with @ is read from the named file; flags override the file.
+func parseHeaders(headers []string, headerFile string) (map[string]string, error) {
+	headerMap := make(map[string]string)
+
+	if headerFile != "" {
+		data, err := os.ReadFile(headerFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read header file: %w", err)
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, value, err := parseHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("header file line %d: %w", i+1, err)
+			}
+			headerMap[key] = value
+		}
+	}
+
+	for _, header := range headers {
+		key, value, err := parseHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		headerMap[key] = value
+	}
+
+	return headerMap, nil
+}
+
+// parseHeader parses a single key=value header, reading key=@file values from the file
+func parseHeader(header string) (string, string, error) {
+	key, value, ok := strings.Cut(header, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid header format: %s (expected key=value)", header)
+	}
+
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read value of header %s: %w", key, err)
+		}
+		value = string(data)
+	}
+
+	return key, value, nil
+}
+
+// validateValues checks every non-tombstone message value against the JSON Schema
+// in schemaFile. Messages are numbered from 1 in errors when there is more than one.
+func validateValues(reqs []types.ProduceRequest, schemaFile string) error {
+	validator, err := serde.NewJSONSchemaValidator(schemaFile)
+	if err != nil {
+		return err
+	}
+
+	for i, req := range reqs {
+		if req.Tombstone {
+			continue
+		}
+		if err := validator.Validate(req.Value); err != nil {
+			if len(reqs) > 1 {
+				return fmt.Errorf("message %d: %w", i+1, err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readValues collects message values from --value, --value-file or --stdin
+func readValues(cmd *cobra.Command, value, valueFile string, readStdin bool, delimiter string) ([]string, error) {
+	switch {
+	case valueFile != "":
+		data, err := os.ReadFile(valueFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read value file: %w", err)
+		}
+		if cmd.Flags().Changed("delimiter") {
+			return splitRecords(data, unescapeDelimiter(delimiter)), nil
+		}
+		return []string{string(data)}, nil
+	case readStdin:
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return splitRecords(data, unescapeDelimiter(delimiter)), nil
+	default:
+		return []string{value}, nil
+	}
+}
+
+// parseBatchFile parses one JSON produce request per line. Blank lines are skipped
+// and the line number is reported for lines that fail to parse.
+func parseBatchFile(r io.Reader, topic string) ([]types.ProduceRequest, error) {
+	var reqs []types.ProduceRequest
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLineSize)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var req types.ProduceRequest
+		decoder := json.NewDecoder(strings.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		req.Topic = topic
+		reqs = append(reqs, req)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNum+1, err)
+	}
+
+	return reqs, nil
+}
+
+// splitRecords splits input into non-empty records separated by delimiter
+func splitRecords(data []byte, delimiter string) []string {
+	var records []string
+	for _, record := range strings.Split(string(data), delimiter) {
+		if delimiter == "\n" {
+			record = strings.TrimSuffix(record, "\r")
+		}
+		if record == "" {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// unescapeDelimiter turns escape sequences typed on the command line into their characters
+func unescapeDelimiter(delimiter string) string {
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\r`, "\r", `\0`, "\x00").Replace(delimiter)
+}
+
+// outputClosed reports whether err, returned by a write to out, means nothing
+// reads the output any more. Any write error to a running pager means the user
+// quit it.
+func outputClosed(out io.Writer, err error) bool {
+	if pager, ok := out.(*ui.Pager); ok && pager.Enabled() {
+		return true
+	}
+	return errors.Is(err, syscall.EPIPE)
+}
+
 // NewMessageConsumeCmd creates the message consume command
 func NewMessageConsumeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	var (
 		groupID       string
-		partition     int32
+		partitions    []int32
+		allPartitions bool
 		fromBeginning bool
 		maxMessages   int
 		timeout       time.Duration
 		format        string
+		filterKey     string
+		filterValue   string
+		filterRegex   string
+		deserialize   deserializerOptions
+		keyFormat     string
+		encoding      string
+		metricsAddr   string
+		since         string
+		until         string
+		dlq           string
+		usePager      bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "consume TOPIC",
 		Short: "Consume messages from a Kafka topic",
-		Long:  "Consume messages from a Kafka topic with real-time streaming or batch processing.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Consume messages from a Kafka topic with real-time streaming or batch processing.
+
+Messages are consumed from all partitions unless --partition is given.
+
+Use --filter-key, --filter-value and --filter-regex to only show matching messages.
+Messages that are filtered out do not count towards --max-messages.
+
+Use --deserialize avro to decode Avro values with schemas from the schema
+registry configured on the active profile, or --deserialize protobuf with
+--proto-descriptor and --proto-message to decode Protobuf values using a
+compiled FileDescriptorSet.
+
+Keys are decoded separately with --key-deserializer: string (the default),
+int32 or int64 for big-endian integer keys, or avro for keys framed with a
+schema registry ID.
+
+Use --value-encoding base64 or hex to show binary values without mangling them.
+
+Use --dlq to produce messages whose key or value cannot be deserialized to a
+dead letter topic instead of showing them. The original bytes and headers are
+kept and kim.dlq.error, kim.dlq.topic, kim.dlq.partition and kim.dlq.offset
+headers describe the failure.
+
+Use --pager to page table output through $PAGER (less by default) when stdout
+is a terminal.
+
+Use --since and --until (RFC3339) to extract a time window of messages. Each
+partition starts at its first message at or after --since and stops at its
+first message after --until. When --until has already passed, a partition also
+stops at the end it had when consuming started, so the consumer exits once every
+partition is done. With --until in the future, it waits for a later message.
+
+Use --metrics-addr to serve Prometheus metrics (messages_consumed_total,
+bytes_consumed_total and consumer_lag per topic and partition) on /metrics.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			topic := args[0]
 
 			if groupID == "" {
 				return fmt.Errorf("consumer group ID is required (use --group-id flag)")
 			}
+			if allPartitions && len(partitions) > 0 {
+				return fmt.Errorf("--all-partitions cannot be used with --partition")
+			}
+			if dlq != "" && dlq == topic {
+				return fmt.Errorf("--dlq must be a different topic than the one consumed")
+			}
+
+			sinceTime, untilTime, err := parseTimeWindow(since, until)
+			if err != nil {
+				return err
+			}
+
+			filter, err := newMessageFilter(filterKey, filterValue, filterRegex)
+			if err != nil {
+				return err
+			}
 
 			// Get active profile
 			profile, err := cfg.GetActiveProfile()
@@ -149,8 +514,18 @@ func NewMessageConsumeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 				return fmt.Errorf("no active profile: %w", err)
 			}
 
+			deserializer, err := newDeserializer(deserialize, profile)
+			if err != nil {
+				return err
+			}
+
+			keyDeserializer, err := newKeyDeserializer(keyFormat, profile)
+			if err != nil {
+				return err
+			}
+
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -159,13 +534,30 @@ func NewMessageConsumeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 
 			// Create message manager
 			messageManager := manager.NewMessageManager(kafkaClient, log)
+			if deserializer != nil {
+				messageManager.SetDeserializer(deserializer)
+			}
+			if keyDeserializer != nil {
+				messageManager.SetKeyDeserializer(keyDeserializer)
+			}
+			messageManager.SetDeadLetterTopic(dlq)
+
+			stopMetrics, err := startMetrics(metricsAddr, messageManager, log)
+			if err != nil {
+				return err
+			}
+			defer stopMetrics()
 
 			// Build consume request
 			req := &types.ConsumeRequest{
 				Topic:         topic,
-				Partition:     partition,
+				Partitions:    partitions,
+				AllPartitions: allPartitions || len(partitions) == 0,
 				GroupID:       groupID,
 				FromBeginning: fromBeginning,
+				ValueEncoding: encoding,
+				Since:         sinceTime,
+				Until:         untilTime,
 			}
 
 			// Start consumer
@@ -175,8 +567,8 @@ func NewMessageConsumeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 			}
 
 			// Setup signal handling for graceful shutdown
-			sigChan := make(chan os.Signal, 1)
-			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+			sigChan, stopSignals := notifyInterrupt()
+			defer stopSignals()
 
 			// Setup timeout if specified
 			var timeoutChan <-chan time.Time
@@ -184,7 +576,11 @@ func NewMessageConsumeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 				timeoutChan = time.After(timeout)
 			}
 
-			fmt.Printf("Started consuming from topic '%s' (partition %d, group '%s')\n", topic, partition, groupID)
+			partitionDesc := "all partitions"
+			if len(partitions) > 0 {
+				partitionDesc = fmt.Sprintf("partitions %v", partitions)
+			}
+			fmt.Printf("Started consuming from topic '%s' (%s, group '%s')\n", topic, partitionDesc, groupID)
 			fmt.Println("Press Ctrl+C to stop consuming...")
 
 			messageCount := 0
@@ -192,23 +588,44 @@ func NewMessageConsumeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 				Format: format,
 			}
 
+			var out io.Writer = os.Stdout
+			if usePager && (format == "table" || format == "") {
+				pager := ui.NewPager(os.Stdout)
+				defer func() {
+					if err := pager.Close(); err != nil {
+						log.Warn("Pager exited with an error", "error", err)
+					}
+				}()
+				out = pager
+			}
+
 			// Consume messages
 			for {
 				select {
 				case message := <-messages:
 					if message == nil {
-						fmt.Println("Consumer closed")
+						fmt.Fprintln(out, "Consumer closed")
 						return nil
 					}
 
-					if err := ui.DisplayMessage(message, displayOpts); err != nil {
+					if !filter.matches(message) {
+						continue
+					}
+
+					if err := ui.DisplayMessageTo(out, message, displayOpts); err != nil {
+						if outputClosed(out, err) {
+							// Nothing is reading the output any more, e.g. the
+							// user quit the pager
+							log.Debug("Output closed, stopping consumer", "error", err)
+							return messageManager.StopAllConsumers()
+						}
 						log.Error("Failed to display message", "error", err)
 					}
 
 					messageCount++
 					if maxMessages > 0 && messageCount >= maxMessages {
-						fmt.Printf("Reached maximum message count (%d), stopping consumer\n", maxMessages)
-						return messageManager.StopConsumer(topic, groupID, partition)
+						fmt.Fprintf(out, "Reached maximum message count (%d), stopping consumer\n", maxMessages)
+						return messageManager.StopAllConsumers()
 					}
 
 				case err := <-errors:
@@ -217,25 +634,542 @@ func NewMessageConsumeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 					}
 
 				case <-sigChan:
-					fmt.Println("\nReceived interrupt signal, stopping consumer...")
-					return messageManager.StopConsumer(topic, groupID, partition)
+					fmt.Fprintln(out, "\nReceived interrupt signal, stopping consumer...")
+					return messageManager.StopAllConsumers()
 
 				case <-timeoutChan:
-					fmt.Printf("Timeout reached (%v), stopping consumer\n", timeout)
-					return messageManager.StopConsumer(topic, groupID, partition)
+					fmt.Fprintf(out, "Timeout reached (%v), stopping consumer\n", timeout)
+					return messageManager.StopAllConsumers()
 				}
 			}
 		},
 	}
 
 	cmd.Flags().StringVar(&groupID, "group-id", "", "consumer group ID (required)")
-	cmd.Flags().Int32Var(&partition, "partition", 0, "partition to consume from")
+	cmd.Flags().Int32SliceVar(&partitions, "partition", nil, "partitions to consume from (default all partitions)")
+	cmd.Flags().BoolVar(&allPartitions, "all-partitions", false, "consume from all partitions of the topic, the default without --partition")
 	cmd.Flags().BoolVar(&fromBeginning, "from-beginning", false, "consume from the beginning of the topic")
 	cmd.Flags().IntVar(&maxMessages, "max-messages", 0, "maximum number of messages to consume (0 = unlimited)")
 	cmd.Flags().DurationVar(&timeout, "timeout", 0, "timeout for consuming messages (0 = no timeout)")
-	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml)")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, jsonl, yaml)")
+	cmd.Flags().StringVar(&filterKey, "filter-key", "", "only show messages whose key contains this substring")
+	cmd.Flags().StringVar(&filterValue, "filter-value", "", "only show messages whose value contains this substring")
+	cmd.Flags().StringVar(&filterRegex, "filter-regex", "", "only show messages whose value matches this regular expression")
+	cmd.Flags().StringVar(&encoding, "value-encoding", manager.ValueEncodingRaw, "how message values are rendered (raw, base64, hex)")
+	cmd.Flags().StringVar(&deserialize.format, "deserialize", "", "decode message values (avro, protobuf)")
+	cmd.Flags().StringVar(&deserialize.protoDescriptor, "proto-descriptor", "", "FileDescriptorSet used to decode protobuf values")
+	cmd.Flags().StringVar(&deserialize.protoMessage, "proto-message", "", "fully qualified protobuf message name (e.g. example.User)")
+	cmd.Flags().StringVar(&keyFormat, "key-deserializer", "string", "decode message keys (string, int32, int64, avro)")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "serve Prometheus metrics on this address (e.g. :9100)")
+	cmd.Flags().StringVar(&since, "since", "", "start at the first message at or after this time (RFC3339)")
+	cmd.Flags().StringVar(&until, "until", "", "stop at the first message after this time (RFC3339)")
+	cmd.Flags().StringVar(&dlq, "dlq", "", "produce messages that cannot be deserialized to this topic")
+	cmd.Flags().BoolVar(&usePager, "pager", false, "page table output through $PAGER (or less) when stdout is a terminal")
 
 	cmd.MarkFlagRequired("group-id")
 
 	return cmd
 }
+
+// parseTimeWindow parses the --since and --until flags. Empty flags give nil times.
+func parseTimeWindow(since, until string) (*time.Time, *time.Time, error) {
+	var sinceTime, untilTime *time.Time
+
+	if since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --since %s (expected RFC3339, e.g. 2024-01-02T15:04:05Z)", since)
+		}
+		sinceTime = &t
+	}
+	if until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --until %s (expected RFC3339, e.g. 2024-01-02T15:04:05Z)", until)
+		}
+		untilTime = &t
+	}
+
+	if sinceTime != nil && untilTime != nil && untilTime.Before(*sinceTime) {
+		return nil, nil, fmt.Errorf("--until must not be before --since")
+	}
+
+	return sinceTime, untilTime, nil
+}
+
+// startMetrics serves consumer metrics on addr and records the messages consumed
+// by messageManager in them. It does nothing if addr is empty. The returned
+// function stops the metrics server.
+func startMetrics(addr string, messageManager *manager.MessageManager, log *logger.Logger) (func(), error) {
+	if addr == "" {
+		return func() {}, nil
+	}
+
+	consumerMetrics := metrics.NewConsumerMetrics()
+	if err := consumerMetrics.Serve(addr, log); err != nil {
+		return nil, fmt.Errorf("failed to start metrics server: %w", err)
+	}
+	messageManager.SetMetrics(consumerMetrics)
+
+	return func() {
+		if err := consumerMetrics.Shutdown(); err != nil {
+			log.Warn("Failed to stop metrics server", "error", err)
+		}
+	}, nil
+}
+
+// deserializerOptions holds the flags selecting how consumed message values are decoded
+type deserializerOptions struct {
+	format          string
+	protoDescriptor string
+	protoMessage    string
+}
+
+// newDeserializer creates the deserializer selected by the --deserialize flag.
+// It returns nil when no deserializer is requested.
+func newDeserializer(opts deserializerOptions, profile *config.Profile) (serde.Deserializer, error) {
+	switch opts.format {
+	case "":
+		return nil, nil
+	case "avro":
+		if profile.SchemaRegistryURL == "" {
+			return nil, fmt.Errorf("profile '%s' has no schema registry URL (use 'kim profile edit --schema-registry-url')", profile.Name)
+		}
+		return serde.NewAvroDeserializer(profile.SchemaRegistryURL), nil
+	case "protobuf":
+		if opts.protoDescriptor == "" || opts.protoMessage == "" {
+			return nil, fmt.Errorf("--proto-descriptor and --proto-message are required for protobuf")
+		}
+		return serde.NewProtobufDeserializer(opts.protoDescriptor, opts.protoMessage)
+	default:
+		return nil, fmt.Errorf("unsupported deserializer: %s (must be 'avro' or 'protobuf')", opts.format)
+	}
+}
+
+// newKeyDeserializer creates the deserializer selected by the --key-deserializer flag.
+// It returns nil for string keys, which are shown as-is.
+func newKeyDeserializer(format string, profile *config.Profile) (serde.Deserializer, error) {
+	switch format {
+	case "", "string":
+		return nil, nil
+	case "int32":
+		return serde.Int32Deserializer{}, nil
+	case "int64":
+		return serde.Int64Deserializer{}, nil
+	case "avro":
+		if profile.SchemaRegistryURL == "" {
+			return nil, fmt.Errorf("profile '%s' has no schema registry URL (use 'kim profile edit --schema-registry-url')", profile.Name)
+		}
+		return serde.NewAvroDeserializer(profile.SchemaRegistryURL), nil
+	default:
+		return nil, fmt.Errorf("unsupported key deserializer: %s (must be 'string', 'int32', 'int64' or 'avro')", format)
+	}
+}
+
+// messageFilter selects consumed messages by key and value. Empty criteria match everything.
+type messageFilter struct {
+	key   string
+	value string
+	regex *regexp.Regexp
+}
+
+// newMessageFilter creates a message filter, compiling the value regex if given
+func newMessageFilter(key, value, pattern string) (*messageFilter, error) {
+	filter := &messageFilter{
+		key:   key,
+		value: value,
+	}
+
+	if pattern != "" {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter regex: %w", err)
+		}
+		filter.regex = regex
+	}
+
+	return filter, nil
+}
+
+// matches reports whether a message satisfies all filter criteria
+func (f *messageFilter) matches(message *types.Message) bool {
+	if f.key != "" && !strings.Contains(message.Key, f.key) {
+		return false
+	}
+	if f.value != "" && !strings.Contains(message.Value, f.value) {
+		return false
+	}
+	if f.regex != nil && !f.regex.MatchString(message.Value) {
+		return false
+	}
+	return true
+}
+
+// NewMessageGetCmd creates the message get command
+func NewMessageGetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		partition int32
+		offset    int64
+		limit     int
+		format    string
+		since     string
+		until     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get TOPIC",
+		Short: "Get a page of messages from a topic partition",
+		Long: `Read up to --limit messages from a topic partition starting at --offset.
+
+Without --offset reading starts at the oldest available message. The offset to pass
+for the next page is printed after the messages.
+
+Use --since instead of --offset to start at the first message at or after a time,
+and --until to stop at the first message after a time (both RFC3339).`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topic := args[0]
+
+			if limit <= 0 {
+				return fmt.Errorf("limit must be greater than 0")
+			}
+			if since != "" && cmd.Flags().Changed("offset") {
+				return fmt.Errorf("--since cannot be used with --offset")
+			}
+
+			sinceTime, untilTime, err := parseTimeWindow(since, until)
+			if err != nil {
+				return err
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create message manager
+			messageManager := manager.NewMessageManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Build get request
+			req := &types.GetMessagesRequest{
+				Topic:         topic,
+				Partition:     partition,
+				FromBeginning: true,
+				Limit:         limit,
+				Since:         sinceTime,
+				Until:         untilTime,
+			}
+
+			if cmd.Flags().Changed("offset") {
+				req.Offset = &offset
+			}
+
+			messageList, err := messageManager.GetTopicMessages(ctx, req)
+			if err != nil {
+				return fmt.Errorf("failed to get messages: %w", err)
+			}
+
+			// Display results
+			displayOpts := &types.DisplayOptions{
+				Format: format,
+			}
+
+			return ui.DisplayMessageList(messageList, displayOpts)
+		},
+	}
+
+	cmd.Flags().Int32Var(&partition, "partition", 0, "partition to read from")
+	cmd.Flags().Int64Var(&offset, "offset", 0, "offset to start reading from (default oldest)")
+	cmd.Flags().IntVar(&limit, "limit", 20, "maximum number of messages to read")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, jsonl, yaml)")
+	cmd.Flags().StringVar(&since, "since", "", "start at the first message at or after this time (RFC3339)")
+	cmd.Flags().StringVar(&until, "until", "", "stop at the first message after this time (RFC3339)")
+
+	return cmd
+}
+
+// NewMessageTailCmd creates the message tail command
+func NewMessageTailCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		rate        float64
+		count       int
+		format      string
+		metricsAddr string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tail TOPIC",
+		Short: "Follow new messages on a Kafka topic",
+		Long: `Print new messages from all partitions of a topic as they arrive, like tail -f.
+
+Runs until Ctrl+C or until --count messages have been printed. Use --rate to limit
+how many messages are printed per second, and --metrics-addr to serve Prometheus
+metrics on /metrics.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topic := args[0]
+
+			if rate < 0 {
+				return fmt.Errorf("rate cannot be negative")
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create message manager
+			messageManager := manager.NewMessageManager(kafkaClient, log)
+
+			stopMetrics, err := startMetrics(metricsAddr, messageManager, log)
+			if err != nil {
+				return err
+			}
+			defer stopMetrics()
+
+			// Follow all partitions from the newest offset
+			req := &types.ConsumeRequest{
+				Topic:         topic,
+				AllPartitions: true,
+			}
+
+			messages, errors, err := messageManager.StartConsumer(context.Background(), req)
+			if err != nil {
+				return fmt.Errorf("failed to start consumer: %w", err)
+			}
+
+			sigChan, stopSignals := notifyInterrupt()
+			defer stopSignals()
+
+			var throttle <-chan time.Time
+			if rate > 0 {
+				ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+				defer ticker.Stop()
+				throttle = ticker.C
+			}
+
+			fmt.Printf("Tailing topic '%s' (press Ctrl+C to stop)...\n", topic)
+
+			messageCount := 0
+			displayOpts := &types.DisplayOptions{
+				Format: format,
+			}
+
+			for {
+				select {
+				case message, ok := <-messages:
+					if !ok {
+						fmt.Println("Consumer closed")
+						return nil
+					}
+
+					if throttle != nil {
+						select {
+						case <-throttle:
+						case <-sigChan:
+							return messageManager.StopAllConsumers()
+						}
+					}
+
+					if err := ui.DisplayMessage(message, displayOpts); err != nil {
+						log.Error("Failed to display message", "error", err)
+					}
+
+					messageCount++
+					if count > 0 && messageCount >= count {
+						return messageManager.StopAllConsumers()
+					}
+
+				case err := <-errors:
+					if err != nil {
+						log.Error("Consumer error", "error", err)
+					}
+
+				case <-sigChan:
+					fmt.Println("\nReceived interrupt signal, stopping...")
+					return messageManager.StopAllConsumers()
+				}
+			}
+		},
+	}
+
+	cmd.Flags().Float64Var(&rate, "rate", 0, "maximum messages printed per second (0 = unlimited)")
+	cmd.Flags().IntVar(&count, "count", 0, "stop after printing this many messages (0 = unlimited)")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, jsonl, yaml)")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "serve Prometheus metrics on this address (e.g. :9100)")
+
+	return cmd
+}
+
+// NewMessageWhichPartitionCmd creates the message which-partition command
+func NewMessageWhichPartitionCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var key string
+
+	cmd := &cobra.Command{
+		Use:   "which-partition TOPIC",
+		Short: "Show the partition a message key is produced to",
+		Long: `Show the partition a message with the given key would be produced to, without
+producing anything. The partition is computed with the producer's default hash
+partitioner over the topic's current partition count.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topic := args[0]
+
+			if key == "" {
+				return fmt.Errorf("message key is required (use --key flag)")
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create message manager
+			messageManager := manager.NewMessageManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			partition, err := messageManager.PartitionForKey(ctx, topic, key)
+			if err != nil {
+				return fmt.Errorf("failed to compute partition: %w", err)
+			}
+
+			fmt.Printf("Key '%s' maps to partition %d of topic '%s'\n", key, partition, topic)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&key, "key", "", "message key")
+
+	return cmd
+}
+
+// NewMessageReplayCmd creates the message replay command
+func NewMessageReplayCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		from               string
+		to                 string
+		fromBeginning      bool
+		maxMessages        int
+		preservePartitions bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Copy messages from one topic to another",
+		Long: `Consume messages from the --from topic and produce them to the --to topic with
+their keys, values, headers and timestamps unchanged, e.g. to migrate a topic or
+reprocess its messages.
+
+With --from-beginning the messages in the source topic when the replay starts
+are copied and the command exits once all of them have been. Otherwise new
+messages are copied until --max messages have been or Ctrl+C is pressed.
+
+Messages are partitioned by key in the destination topic unless
+--preserve-partitions is given, which produces each message to the partition
+it was read from.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" || to == "" {
+				return fmt.Errorf("source and destination topics are required (use --from and --to flags)")
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create message manager
+			messageManager := manager.NewMessageManager(kafkaClient, log)
+
+			// Like consume, a replay is not bound by --timeout and only Ctrl+C
+			// stops it early
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// Ctrl+C ends the replay with the messages copied so far
+			sigChan, stopSignals := notifyInterrupt()
+			defer stopSignals()
+			go func() {
+				select {
+				case <-sigChan:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			req := &types.ReplayRequest{
+				Source:             from,
+				Destination:        to,
+				FromBeginning:      fromBeginning,
+				Max:                maxMessages,
+				PreservePartitions: preservePartitions,
+			}
+
+			if !fromBeginning {
+				fmt.Printf("Replaying new messages from '%s' to '%s'\n", from, to)
+				fmt.Println("Press Ctrl+C to stop replaying...")
+			}
+
+			result, err := messageManager.Replay(ctx, req)
+			if result != nil {
+				fmt.Printf("Replayed %d message(s) from '%s' to '%s'\n", result.Replayed, from, to)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to replay messages: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "topic to read messages from (required)")
+	cmd.Flags().StringVar(&to, "to", "", "topic to produce messages to (required)")
+	cmd.Flags().BoolVar(&fromBeginning, "from-beginning", false, "replay the messages already in the source topic")
+	cmd.Flags().IntVar(&maxMessages, "max", 0, "maximum number of messages to replay (0 = unlimited)")
+	cmd.Flags().BoolVar(&preservePartitions, "preserve-partitions", false, "produce each message to the partition it was read from")
+
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
@@ -0,0 +1,738 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+	"github.com/spf13/cobra"
+)
+
+func TestMessageProduceFromStdin(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	cmd := NewMessageCmd(cfg, log)
+	cmd.SetIn(strings.NewReader("first\nsecond\nthird\n"))
+
+	_, err := executeCommand(cmd, "produce", "test-topic", "--stdin", "--key", "k")
+	if err != nil {
+		t.Fatalf("Produce from stdin failed: %v", err)
+	}
+
+	messages := mock.Producer().Messages()
+	if len(messages) != 3 {
+		t.Fatalf("Expected 3 SendMessage calls, got %d", len(messages))
+	}
+
+	for i, expected := range []string{"first", "second", "third"} {
+		value, _ := messages[i].Value.Encode()
+		if string(value) != expected {
+			t.Errorf("Message %d: expected value %q, got %q", i, expected, value)
+		}
+		if messages[i].Topic != "test-topic" {
+			t.Errorf("Message %d: expected topic test-topic, got %s", i, messages[i].Topic)
+		}
+	}
+}
+
+func TestMessageProduceFromValueFile(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	path := filepath.Join(tempDir, "payload.txt")
+	if err := os.WriteFile(path, []byte("line one\nline two\n"), 0644); err != nil {
+		t.Fatalf("Failed to write value file: %v", err)
+	}
+
+	// Without --delimiter the whole file is a single message
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "test-topic", "--value-file", path)
+	if err != nil {
+		t.Fatalf("Produce from value file failed: %v", err)
+	}
+	if len(mock.Producer().Messages()) != 1 {
+		t.Errorf("Expected a single message, got %d", len(mock.Producer().Messages()))
+	}
+
+	// With --delimiter the file is split
+	mock = testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	_, err = executeCommand(NewMessageCmd(cfg, log), "produce", "test-topic", "--value-file", path, "--delimiter", `\n`)
+	if err != nil {
+		t.Fatalf("Produce from value file failed: %v", err)
+	}
+	if len(mock.Producer().Messages()) != 2 {
+		t.Errorf("Expected 2 messages, got %d", len(mock.Producer().Messages()))
+	}
+}
+
+func TestMessageProduceValueSources(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "test-topic")
+	if err == nil {
+		t.Error("Produce without a value should fail")
+	}
+
+	_, err = executeCommand(NewMessageCmd(cfg, log), "produce", "test-topic", "--value", "v", "--stdin")
+	if err == nil {
+		t.Error("Produce with more than one value source should fail")
+	}
+}
+
+func TestParseBatchFile(t *testing.T) {
+	input := `{"key": "k1", "value": "v1", "headers": {"source": "app"}}
+
+{"key": "k2", "value": "v2", "partition": 3}
+`
+
+	reqs, err := parseBatchFile(strings.NewReader(input), "test-topic")
+	if err != nil {
+		t.Fatalf("Failed to parse batch file: %v", err)
+	}
+
+	if len(reqs) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(reqs))
+	}
+	if reqs[0].Key != "k1" || reqs[0].Value != "v1" || reqs[0].Headers["source"] != "app" {
+		t.Errorf("Unexpected first request: %+v", reqs[0])
+	}
+	if reqs[1].Partition == nil || *reqs[1].Partition != 3 {
+		t.Errorf("Expected second request to target partition 3, got %v", reqs[1].Partition)
+	}
+	for _, req := range reqs {
+		if req.Topic != "test-topic" {
+			t.Errorf("Expected topic test-topic, got %s", req.Topic)
+		}
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	dir := t.TempDir()
+
+	binary := []byte{0x00, 0xff, 0x10}
+	binaryFile := filepath.Join(dir, "trace.bin")
+	if err := os.WriteFile(binaryFile, binary, 0644); err != nil {
+		t.Fatalf("Failed to write header value file: %v", err)
+	}
+
+	headerFile := filepath.Join(dir, "headers.txt")
+	content := "# bulk headers\nsource=file\n\nversion=1.0\n"
+	if err := os.WriteFile(headerFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write header file: %v", err)
+	}
+
+	headers, err := parseHeaders([]string{
+		"source=app1",
+		"query=a=b,c",
+		"empty=",
+		"trace=@" + binaryFile,
+	}, headerFile)
+	if err != nil {
+		t.Fatalf("parseHeaders failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"source":  "app1", // flags override the header file
+		"version": "1.0",
+		"query":   "a=b,c", // only the first = separates key and value
+		"empty":   "",
+		"trace":   string(binary),
+	}
+	if !reflect.DeepEqual(headers, expected) {
+		t.Errorf("Expected headers %q, got %q", expected, headers)
+	}
+
+	for _, header := range []string{"source:app1", "=value", "trace=@" + filepath.Join(dir, "missing")} {
+		if _, err := parseHeaders([]string{header}, ""); err == nil {
+			t.Errorf("parseHeaders(%q) should fail", header)
+		}
+	}
+}
+
+func TestMessageProduceHeaders(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "orders", "--value", "v1",
+		"--header", "source=app1", "--header", "tags=a,b")
+	if err != nil {
+		t.Fatalf("Produce failed: %v", err)
+	}
+
+	messages := mock.Producer().Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	headers := make(map[string]string)
+	for _, header := range messages[0].Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	expected := map[string]string{"source": "app1", "tags": "a,b"}
+	if !reflect.DeepEqual(headers, expected) {
+		t.Errorf("Expected headers %v, got %v", expected, headers)
+	}
+}
+
+func TestMessageProduceBatchFileMalformedLine(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	path := filepath.Join(tempDir, "batch.jsonl")
+	content := `{"key": "k1", "value": "v1"}
+{"key": "k2", "value": "v2"}
+{"key": "k3", "value":
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write batch file: %v", err)
+	}
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "test-topic", "--batch-file", path)
+	if err == nil {
+		t.Fatal("Produce should fail for a malformed batch file")
+	}
+	if !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Error should include the line number, got: %v", err)
+	}
+	if len(mock.Producer().Messages()) != 0 {
+		t.Errorf("Nothing should be produced from an invalid batch file, got %d messages", len(mock.Producer().Messages()))
+	}
+}
+
+func TestMessageProduceTombstone(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "compacted-topic", "--key", "user123", "--tombstone")
+	if err != nil {
+		t.Fatalf("Produce tombstone failed: %v", err)
+	}
+
+	messages := mock.Producer().Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	if messages[0].Value != nil {
+		t.Errorf("Tombstone should have a nil value, got %v", messages[0].Value)
+	}
+	key, _ := messages[0].Key.Encode()
+	if string(key) != "user123" {
+		t.Errorf("Expected key user123, got %s", key)
+	}
+
+	// A tombstone without a key is meaningless
+	_, err = executeCommand(NewMessageCmd(cfg, log), "produce", "compacted-topic", "--tombstone")
+	if err == nil {
+		t.Error("Tombstone without a key should fail")
+	}
+}
+
+func TestMessageProduceSchemaValidation(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	schemaFile := filepath.Join(tempDir, "order.json")
+	schema := `{"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}`
+	if err := os.WriteFile(schemaFile, []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "orders", "--value", `{"id": 1}`, "--schema", schemaFile)
+	if err != nil {
+		t.Fatalf("Produce of a valid value failed: %v", err)
+	}
+
+	_, err = executeCommand(NewMessageCmd(cfg, log), "produce", "orders", "--value", `{"id": "one"}`, "--schema", schemaFile)
+	if err == nil || !strings.Contains(err.Error(), "/id") {
+		t.Errorf("Expected a validation error naming /id, got %v", err)
+	}
+
+	// Only the valid value is sent
+	if messages := mock.Producer().Messages(); len(messages) != 1 {
+		t.Errorf("Expected 1 message, got %d", len(messages))
+	}
+}
+
+func TestMessageProduceAcks(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	tests := []struct {
+		acks     string
+		expected []sarama.RequiredAcks
+	}{
+		{acks: "none", expected: []sarama.RequiredAcks{sarama.NoResponse}},
+		{acks: "leader", expected: []sarama.RequiredAcks{sarama.WaitForLocal}},
+		// The client's producer already waits for all replicas
+		{acks: "all", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acks, func(t *testing.T) {
+			mock := testutil.NewMockClient(testutil.TestProfile(), log)
+			useMockClient(t, mock)
+
+			_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "orders", "--value", "v1", "--acks", tt.acks)
+			if err != nil {
+				t.Fatalf("Produce failed: %v", err)
+			}
+
+			var acks []sarama.RequiredAcks
+			for _, config := range mock.ProducerConfigs() {
+				acks = append(acks, config.Producer.RequiredAcks)
+			}
+			if !reflect.DeepEqual(acks, tt.expected) {
+				t.Errorf("Expected producers with acks %v, got %v", tt.expected, acks)
+			}
+			if len(mock.Producer().Messages()) != 1 {
+				t.Errorf("Expected 1 message, got %d", len(mock.Producer().Messages()))
+			}
+		})
+	}
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+	_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "orders", "--value", "v1", "--acks", "some")
+	if err == nil || !strings.Contains(err.Error(), "unsupported acks") {
+		t.Errorf("Expected an unsupported acks error, got %v", err)
+	}
+}
+
+func TestMessageProduceCompression(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	codecs := map[string]sarama.CompressionCodec{
+		"gzip":   sarama.CompressionGZIP,
+		"snappy": sarama.CompressionSnappy,
+		"lz4":    sarama.CompressionLZ4,
+		"zstd":   sarama.CompressionZSTD,
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			mock := testutil.NewMockClient(testutil.TestProfile(), log)
+			useMockClient(t, mock)
+
+			_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "orders", "--value", "v1", "--compression", name)
+			if err != nil {
+				t.Fatalf("Produce failed: %v", err)
+			}
+
+			configs := mock.ProducerConfigs()
+			if len(configs) != 1 {
+				t.Fatalf("Expected a dedicated producer, got %d", len(configs))
+			}
+			if configs[0].Producer.Compression != codec {
+				t.Errorf("Expected compression %s, got %s", codec, configs[0].Producer.Compression)
+			}
+			if configs[0].Producer.RequiredAcks != sarama.WaitForAll {
+				t.Errorf("Compression should keep the default acks, got %d", configs[0].Producer.RequiredAcks)
+			}
+		})
+	}
+
+	// Batches share one producer for all messages with the same settings
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	batchFile := filepath.Join(t.TempDir(), "batch.jsonl")
+	content := `{"value": "v1"}
+{"value": "v2"}
+{"value": "v3", "compression": "lz4"}
+`
+	if err := os.WriteFile(batchFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write batch file: %v", err)
+	}
+
+	_, err := executeCommand(NewMessageCmd(cfg, log), "produce", "orders", "--batch-file", batchFile, "--compression", "gzip")
+	if err != nil {
+		t.Fatalf("Produce batch failed: %v", err)
+	}
+
+	var used []sarama.CompressionCodec
+	for _, config := range mock.ProducerConfigs() {
+		used = append(used, config.Producer.Compression)
+	}
+	expected := []sarama.CompressionCodec{sarama.CompressionGZIP, sarama.CompressionLZ4}
+	if !reflect.DeepEqual(used, expected) {
+		t.Errorf("Expected producers with compression %v, got %v", expected, used)
+	}
+	if len(mock.Producer().Messages()) != 3 {
+		t.Errorf("Expected 3 messages, got %d", len(mock.Producer().Messages()))
+	}
+
+	_, err = executeCommand(NewMessageCmd(cfg, log), "produce", "orders", "--value", "v1", "--compression", "brotli")
+	if err == nil || !strings.Contains(err.Error(), "unsupported compression") {
+		t.Errorf("Expected an unsupported compression error, got %v", err)
+	}
+}
+
+func TestMessageWhichPartition(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 6, 1)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewMessageCmd(cfg, log), "which-partition", "orders", "--key", "user123")
+	})
+	if err != nil {
+		t.Fatalf("which-partition failed: %v", err)
+	}
+	if !strings.Contains(output, "partition 4") {
+		t.Errorf("Expected partition 4 in output, got %q", output)
+	}
+
+	if _, err := executeCommand(NewMessageCmd(cfg, log), "which-partition", "orders"); err == nil {
+		t.Error("which-partition without a key should fail")
+	}
+}
+
+func TestMessageReplay(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	partition := mock.Consumer().AddMockPartition("orders", 0)
+	partition.SendMockMessage("user1", "created")
+	partition.SendMockMessage("user2", "paid")
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewMessageCmd(cfg, log), "replay", "--from", "orders", "--to", "orders-v2", "--from-beginning")
+	})
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if !strings.Contains(output, "Replayed 2 message(s) from 'orders' to 'orders-v2'") {
+		t.Errorf("Unexpected output: %q", output)
+	}
+	if sent := mock.Producer().Messages(); len(sent) != 2 || sent[0].Topic != "orders-v2" {
+		t.Errorf("Expected 2 messages produced to orders-v2, got %d", len(sent))
+	}
+
+	if _, err := executeCommand(NewMessageCmd(cfg, log), "replay", "--from", "orders"); err == nil {
+		t.Error("replay without a destination should fail")
+	}
+}
+
+// useInterrupt replaces signal handling with a channel the test can send on
+func useInterrupt(t *testing.T) chan os.Signal {
+	sigChan := make(chan os.Signal, 1)
+	old := notifyInterrupt
+	notifyInterrupt = func() (<-chan os.Signal, func()) {
+		return sigChan, func() {}
+	}
+	t.Cleanup(func() {
+		notifyInterrupt = old
+	})
+	return sigChan
+}
+
+// waitForStart blocks until all partitions have been opened by a consumer
+func waitForStart(t *testing.T, partitions ...*testutil.MockPartitionConsumer) {
+	deadline := time.Now().Add(2 * time.Second)
+	for _, pc := range partitions {
+		for !pc.Started() {
+			if time.Now().After(deadline) {
+				t.Fatal("Timed out waiting for consumer to start")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+}
+
+// runAsync executes a command in the background and returns a channel with its result
+func runAsync(cmd *cobra.Command, args ...string) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := executeCommand(cmd, args...)
+		done <- err
+	}()
+	return done
+}
+
+func TestMessageTailDeliversNewMessages(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	p0 := mock.Consumer().AddMockPartition("test-topic", 0)
+	p1 := mock.Consumer().AddMockPartition("test-topic", 1)
+	useMockClient(t, mock)
+	useInterrupt(t)
+
+	// Messages written before the tail starts are not shown
+	p0.SendMockMessage("", "old")
+
+	done := runAsync(NewMessageCmd(cfg, log), "tail", "test-topic", "--count", "2")
+	waitForStart(t, p0, p1)
+
+	p0.SendMockMessage("", "new-0")
+	p1.SendMockMessage("", "new-1")
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Tail failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Tail did not stop after --count messages")
+	}
+
+	if p0.StartOffset() != 1 {
+		t.Errorf("Tail should start from the newest offset, started at %d", p0.StartOffset())
+	}
+	if !p0.Closed() || !p1.Closed() {
+		t.Error("All partition consumers should be closed after tail stops")
+	}
+}
+
+func TestMessageTailStopsOnInterrupt(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	p0 := mock.Consumer().AddMockPartition("test-topic", 0)
+	p1 := mock.Consumer().AddMockPartition("test-topic", 1)
+	useMockClient(t, mock)
+	sigChan := useInterrupt(t)
+
+	done := runAsync(NewMessageCmd(cfg, log), "tail", "test-topic")
+	waitForStart(t, p0, p1)
+
+	sigChan <- os.Interrupt
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Tail should stop cleanly on interrupt: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Tail did not stop on interrupt")
+	}
+
+	if !p0.Closed() || !p1.Closed() {
+		t.Error("All partition consumers should be closed after interrupt")
+	}
+}
+
+// captureStdout captures what a function prints to stdout
+func captureStdout(f func()) string {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	f()
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+func TestMessageFilter(t *testing.T) {
+	filter, err := newMessageFilter("user", "", `"status":\s*"failed"`)
+	if err != nil {
+		t.Fatalf("Failed to create filter: %v", err)
+	}
+
+	tests := []struct {
+		message  *types.Message
+		expected bool
+	}{
+		{&types.Message{Key: "user-1", Value: `{"status": "failed"}`}, true},
+		{&types.Message{Key: "user-1", Value: `{"status": "ok"}`}, false},
+		{&types.Message{Key: "order-1", Value: `{"status": "failed"}`}, false},
+	}
+
+	for _, tt := range tests {
+		if got := filter.matches(tt.message); got != tt.expected {
+			t.Errorf("matches(%s=%s) = %v, want %v", tt.message.Key, tt.message.Value, got, tt.expected)
+		}
+	}
+
+	if _, err := newMessageFilter("", "", "("); err == nil {
+		t.Error("Invalid regex should be rejected")
+	}
+}
+
+func TestMessageConsumeFilter(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+	for _, value := range []string{"skip-a", "keep-1", "skip-b", "keep-2", "keep-3"} {
+		partition.SendMockMessage("", value)
+	}
+	useMockClient(t, mock)
+	useInterrupt(t)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewMessageCmd(cfg, log), "consume", "test-topic",
+			"--group-id", "test-group", "--partition", "0", "--from-beginning",
+			"--filter-value", "keep", "--max-messages", "2", "--timeout", "2s")
+	})
+	if err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+
+	if strings.Contains(output, "skip-") {
+		t.Error("Filtered out messages should not be displayed")
+	}
+	if !strings.Contains(output, "keep-1") || !strings.Contains(output, "keep-2") {
+		t.Error("Matching messages should be displayed")
+	}
+	if strings.Contains(output, "keep-3") {
+		t.Error("Only matching messages should count towards --max-messages")
+	}
+	if !strings.Contains(output, "Reached maximum message count") {
+		t.Error("Consumer should stop after the maximum number of matching messages")
+	}
+}
+
+func TestMessageConsumeAllPartitions(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.Consumer().AddMockPartition("test-topic", 0).SendMockMessage("", "from-0")
+	mock.Consumer().AddMockPartition("test-topic", 1).SendMockMessage("", "from-1")
+	useMockClient(t, mock)
+	useInterrupt(t)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewMessageCmd(cfg, log), "consume", "test-topic",
+			"--group-id", "test-group", "--all-partitions", "--from-beginning",
+			"--max-messages", "2", "--timeout", "2s")
+	})
+	if err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+
+	if !strings.Contains(output, "from-0") || !strings.Contains(output, "from-1") {
+		t.Errorf("Expected messages from both partitions, got %q", output)
+	}
+
+	if _, err := executeCommand(NewMessageCmd(cfg, log), "consume", "test-topic",
+		"--group-id", "test-group", "--all-partitions", "--partition", "0"); err == nil {
+		t.Error("Expected an error when --all-partitions and --partition are combined")
+	}
+}
+
+func TestParseTimeWindow(t *testing.T) {
+	since, until, err := parseTimeWindow("2024-01-02T00:00:00Z", "2024-01-02T03:00:00Z")
+	if err != nil {
+		t.Fatalf("parseTimeWindow failed: %v", err)
+	}
+	if until.Sub(*since) != 3*time.Hour {
+		t.Errorf("Expected a 3h window, got %v to %v", since, until)
+	}
+
+	if since, until, err := parseTimeWindow("", ""); err != nil || since != nil || until != nil {
+		t.Errorf("Empty flags should give no window, got %v, %v, %v", since, until, err)
+	}
+
+	for _, window := range [][2]string{
+		{"yesterday", ""},
+		{"", "2024-01-02"},
+		{"2024-01-02T03:00:00Z", "2024-01-02T00:00:00Z"},
+	} {
+		if _, _, err := parseTimeWindow(window[0], window[1]); err == nil {
+			t.Errorf("parseTimeWindow(%q, %q) should fail", window[0], window[1])
+		}
+	}
+}
+
+func TestOutputClosed(t *testing.T) {
+	var buf bytes.Buffer
+	if !outputClosed(&buf, fmt.Errorf("write failed: %w", syscall.EPIPE)) {
+		t.Error("Expected a broken pipe to close the output")
+	}
+	if outputClosed(&buf, io.ErrShortWrite) {
+		t.Error("Expected other write errors to leave the output open")
+	}
+}
@@ -1,8 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/nipunap/kim/internal/config"
 	"github.com/nipunap/kim/internal/logger"
@@ -21,73 +22,363 @@ func NewProfileCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	}
 
 	cmd.AddCommand(NewProfileListCmd(cfg, log))
+	cmd.AddCommand(NewProfileActiveCmd(cfg, log))
+	cmd.AddCommand(NewProfileShowCmd(cfg, log))
 	cmd.AddCommand(NewProfileAddCmd(cfg, log))
+	cmd.AddCommand(NewProfileEditCmd(cfg, log))
 	cmd.AddCommand(NewProfileUseCmd(cfg, log))
+	cmd.AddCommand(NewProfileRenameCmd(cfg, log))
 	cmd.AddCommand(NewProfileDeleteCmd(cfg, log))
+	cmd.AddCommand(NewProfileTestCmd(cfg, log))
 
 	return cmd
 }
 
 // NewProfileListCmd creates the profile list command
 func NewProfileListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
-	var format string
+	var (
+		format     string
+		tmpl       string
+		activeOnly bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all profiles",
-		Long:  "List all configured Kafka cluster profiles.",
+		Long:  "List all configured Kafka cluster profiles, or only the active one with --active-only.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			profiles := make([]*types.ProfileInfo, 0, len(cfg.Profiles))
+			profiles := newProfileInfos(cfg, activeOnly)
 
-			for name, profile := range cfg.Profiles {
-				profileInfo := &types.ProfileInfo{
-					Name:   name,
-					Type:   profile.Type,
-					Active: name == cfg.ActiveProfile,
-				}
+			displayOpts := newDisplayOptions(format, tmpl)
 
-				// Add connection details based on type
-				switch profile.Type {
-				case "msk":
-					profileInfo.Details = fmt.Sprintf("Region: %s", profile.Region)
-				case "kafka":
-					profileInfo.Details = fmt.Sprintf("Servers: %s", profile.BootstrapServers)
-				}
+			return ui.DisplayProfileList(profiles, displayOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+	cmd.Flags().BoolVar(&activeOnly, "active-only", false, "only list the active profile")
 
-				profiles = append(profiles, profileInfo)
+	return cmd
+}
+
+// newProfileInfos converts the configured profiles for listing. With activeOnly
+// only the active profile is included.
+func newProfileInfos(cfg *config.Config, activeOnly bool) []*types.ProfileInfo {
+	profiles := make([]*types.ProfileInfo, 0, len(cfg.Profiles))
+
+	for name, profile := range cfg.Profiles {
+		profileInfo := &types.ProfileInfo{
+			Name:   name,
+			Type:   profile.Type,
+			Active: name == cfg.ActiveProfile,
+		}
+		if activeOnly && !profileInfo.Active {
+			continue
+		}
+
+		// Add connection details based on type
+		switch profile.Type {
+		case "msk":
+			profileInfo.Details = fmt.Sprintf("Region: %s", profile.Region)
+		case "kafka":
+			profileInfo.Details = fmt.Sprintf("Servers: %s", profile.BootstrapServers)
+		}
+
+		profiles = append(profiles, profileInfo)
+	}
+
+	return profiles
+}
+
+// NewProfileActiveCmd creates the profile active command
+func NewProfileActiveCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "active",
+		Short: "Print the name of the active profile",
+		Long: `Print the name of the active profile, e.g. for shell prompts. The command
+prints nothing and exits with status 1 if no profile is active.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := cfg.GetActiveProfile(); err != nil {
+				// The exit status is the answer, so print nothing else
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return err
 			}
 
-			displayOpts := &types.DisplayOptions{
-				Format: format,
+			fmt.Println(cfg.ActiveProfile)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// redactedSecret replaces secret profile values in output
+const redactedSecret = "********"
+
+// NewProfileShowCmd creates the profile show command
+func NewProfileShowCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		format      string
+		tmpl        string
+		showSecrets bool
+	)
+
+	cmd := &cobra.Command{
+		Use:               "show NAME",
+		Short:             "Show a profile's details",
+		Long:              "Show every setting of a profile. Passwords are redacted unless --show-secrets is set.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProfileNames(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			profile, err := cfg.GetProfile(name)
+			if err != nil {
+				return err
 			}
 
-			return ui.DisplayProfileList(profiles, displayOpts)
+			details := newProfileDetails(name, profile, name == cfg.ActiveProfile, showSecrets)
+
+			return ui.DisplayProfileDetails(details, newDisplayOptions(format, tmpl))
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml)")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "show passwords instead of redacting them")
 
 	return cmd
 }
 
+// newProfileDetails converts a profile for display, redacting its passwords
+// unless showSecrets is set
+func newProfileDetails(name string, profile *config.Profile, active, showSecrets bool) *types.ProfileDetails {
+	secret := func(value string) string {
+		if value == "" || showSecrets {
+			return value
+		}
+		return redactedSecret
+	}
+	duration := func(d time.Duration) string {
+		if d == 0 {
+			return ""
+		}
+		return d.String()
+	}
+
+	return &types.ProfileDetails{
+		Name:              name,
+		Type:              profile.Type,
+		Active:            active,
+		BootstrapServers:  profile.BootstrapServers,
+		Region:            profile.Region,
+		ClusterARN:        profile.ClusterARN,
+		AuthMethod:        profile.AuthMethod,
+		AWSProfile:        profile.AWSProfile,
+		AWSRoleARN:        profile.AWSRoleARN,
+		SecurityProtocol:  profile.SecurityProtocol,
+		SASLMechanism:     profile.SASLMechanism,
+		SASLUsername:      profile.SASLUsername,
+		SASLPassword:      secret(profile.SASLPassword),
+		OAuthTokenCommand: profile.OAuthTokenCommand,
+		OAuthTokenEnv:     profile.OAuthTokenEnv,
+		SSLCAFile:         profile.SSLCAFile,
+		SSLCertFile:       profile.SSLCertFile,
+		SSLKeyFile:        profile.SSLKeyFile,
+		SSLPassword:       secret(profile.SSLPassword),
+		SSLCheckHostname:  profile.SSLCheckHostname,
+		TLSMinVersion:     profile.TLSMinVersion,
+		TLSServerName:     profile.TLSServerName,
+		SchemaRegistryURL: profile.SchemaRegistryURL,
+		KafkaVersion:      profile.KafkaVersion,
+		ClientID:          profile.ClientID,
+		DialTimeout:       duration(profile.DialTimeout),
+		ReadTimeout:       duration(profile.ReadTimeout),
+		WriteTimeout:      duration(profile.WriteTimeout),
+		MetadataRetryMax:  profile.MetadataRetryMax,
+		Extra:             profile.Extra,
+	}
+}
+
+// profileFlags holds the connection flags shared by the profile add and edit commands
+type profileFlags struct {
+	profileType      string
+	bootstrapServers string
+	region           string
+	clusterARN       string
+	authMethod       string
+	awsProfile       string
+	awsRoleARN       string
+	securityProtocol string
+	saslMechanism    string
+	saslUsername     string
+	saslPassword     string
+	oauthTokenCmd    string
+	oauthTokenEnv    string
+	sslCAFile        string
+	sslCertFile      string
+	sslKeyFile       string
+	sslPassword      string
+	sslCheckHostname bool
+	tlsMinVersion    string
+	tlsServerName    string
+	schemaRegistry   string
+	kafkaVersion     string
+	clientID         string
+	dialTimeout      time.Duration
+	readTimeout      time.Duration
+	writeTimeout     time.Duration
+	metadataRetryMax int
+	skipFileCheck    bool
+}
+
+// register adds the profile flags to the command
+func (f *profileFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.profileType, "type", "", "profile type (kafka or msk)")
+	cmd.Flags().StringVar(&f.bootstrapServers, "bootstrap-servers", "", "Kafka bootstrap servers (comma-separated)")
+	cmd.Flags().StringVar(&f.region, "region", "", "AWS region for MSK")
+	cmd.Flags().StringVar(&f.clusterARN, "cluster-arn", "", "MSK cluster ARN")
+	cmd.Flags().StringVar(&f.authMethod, "auth-method", "IAM", "MSK authentication method (IAM or SASL_SCRAM)")
+	cmd.Flags().StringVar(&f.awsProfile, "aws-profile", "", "AWS shared config profile for MSK (default credential chain if empty)")
+	cmd.Flags().StringVar(&f.awsRoleARN, "assume-role-arn", "", "ARN of an AWS role to assume for MSK")
+	cmd.Flags().StringVar(&f.securityProtocol, "security-protocol", "PLAINTEXT", "security protocol (PLAINTEXT, SSL, SASL_PLAINTEXT, SASL_SSL)")
+	cmd.Flags().StringVar(&f.saslMechanism, "sasl-mechanism", "", "SASL mechanism (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512, GSSAPI, OAUTHBEARER)")
+	cmd.Flags().StringVar(&f.saslUsername, "sasl-username", "", "SASL username")
+	cmd.Flags().StringVar(&f.saslPassword, "sasl-password", "", "SASL password")
+	cmd.Flags().StringVar(&f.oauthTokenCmd, "oauth-token-command", "", "command printing an OAuth token for OAUTHBEARER")
+	cmd.Flags().StringVar(&f.oauthTokenEnv, "oauth-token-env", "", "environment variable holding a static OAuth token for OAUTHBEARER")
+	cmd.Flags().StringVar(&f.sslCAFile, "ssl-ca-file", "", "SSL CA certificate file")
+	cmd.Flags().StringVar(&f.sslCertFile, "ssl-cert-file", "", "SSL client certificate file")
+	cmd.Flags().StringVar(&f.sslKeyFile, "ssl-key-file", "", "SSL client key file")
+	cmd.Flags().StringVar(&f.sslPassword, "ssl-password", "", "SSL key password")
+	cmd.Flags().BoolVar(&f.sslCheckHostname, "ssl-check-hostname", false, "enable SSL hostname verification")
+	cmd.Flags().StringVar(&f.tlsMinVersion, "tls-min-version", "", "minimum TLS version (1.0, 1.1, 1.2 or 1.3; default Go's minimum)")
+	cmd.Flags().StringVar(&f.tlsServerName, "tls-server-name", "", "server name sent for SNI and verified against the broker certificate (default the broker host)")
+	cmd.Flags().StringVar(&f.schemaRegistry, "schema-registry-url", "", "schema registry URL used to deserialize messages")
+	cmd.Flags().StringVar(&f.kafkaVersion, "kafka-version", "", "Kafka protocol version to use, e.g. 3.6.0 (default 2.8.1)")
+	cmd.Flags().StringVar(&f.clientID, "client-id", "", "client ID sent to the brokers (default kim-client)")
+	cmd.Flags().DurationVar(&f.dialTimeout, "dial-timeout", 0, "timeout for connecting to brokers (default 10s)")
+	cmd.Flags().DurationVar(&f.readTimeout, "read-timeout", 0, "timeout for broker responses (default 30s)")
+	cmd.Flags().DurationVar(&f.writeTimeout, "write-timeout", 0, "timeout for broker requests (default 30s)")
+	cmd.Flags().IntVar(&f.metadataRetryMax, "metadata-retry-max", 0, "number of times to retry metadata requests (default 3)")
+	cmd.Flags().BoolVar(&f.skipFileCheck, "skip-file-check", false, "do not check that the SSL files exist, e.g. for templated configs")
+}
+
+// checkFiles checks the profile's SSL files unless --skip-file-check is set
+func (f *profileFlags) checkFiles(profile *config.Profile) error {
+	if f.skipFileCheck {
+		return nil
+	}
+	if err := config.CheckProfileFiles(profile); err != nil {
+		return fmt.Errorf("%w (use --skip-file-check to save it anyway)", err)
+	}
+	return nil
+}
+
+// applyChanged copies the flags explicitly set by the user onto the profile,
+// leaving every other field untouched. It reports whether any flag was set;
+// global flags such as --debug do not count.
+func (f *profileFlags) applyChanged(cmd *cobra.Command, profile *config.Profile) bool {
+	applied := false
+	changed := func(name string) bool {
+		if cmd.Flags().Changed(name) {
+			applied = true
+			return true
+		}
+		return false
+	}
+
+	if changed("type") {
+		profile.Type = f.profileType
+	}
+	if changed("bootstrap-servers") {
+		profile.BootstrapServers = f.bootstrapServers
+	}
+	if changed("region") {
+		profile.Region = f.region
+	}
+	if changed("cluster-arn") {
+		profile.ClusterARN = f.clusterARN
+	}
+	if changed("auth-method") {
+		profile.AuthMethod = f.authMethod
+	}
+	if changed("aws-profile") {
+		profile.AWSProfile = f.awsProfile
+	}
+	if changed("assume-role-arn") {
+		profile.AWSRoleARN = f.awsRoleARN
+	}
+	if changed("security-protocol") {
+		profile.SecurityProtocol = f.securityProtocol
+	}
+	if changed("sasl-mechanism") {
+		profile.SASLMechanism = f.saslMechanism
+	}
+	if changed("sasl-username") {
+		profile.SASLUsername = f.saslUsername
+	}
+	if changed("sasl-password") {
+		profile.SASLPassword = f.saslPassword
+	}
+	if changed("oauth-token-command") {
+		profile.OAuthTokenCommand = f.oauthTokenCmd
+	}
+	if changed("oauth-token-env") {
+		profile.OAuthTokenEnv = f.oauthTokenEnv
+	}
+	if changed("ssl-ca-file") {
+		profile.SSLCAFile = f.sslCAFile
+	}
+	if changed("ssl-cert-file") {
+		profile.SSLCertFile = f.sslCertFile
+	}
+	if changed("ssl-key-file") {
+		profile.SSLKeyFile = f.sslKeyFile
+	}
+	if changed("ssl-password") {
+		profile.SSLPassword = f.sslPassword
+	}
+	if changed("ssl-check-hostname") {
+		profile.SSLCheckHostname = f.sslCheckHostname
+	}
+	if changed("tls-min-version") {
+		profile.TLSMinVersion = f.tlsMinVersion
+	}
+	if changed("tls-server-name") {
+		profile.TLSServerName = f.tlsServerName
+	}
+	if changed("schema-registry-url") {
+		profile.SchemaRegistryURL = f.schemaRegistry
+	}
+	if changed("kafka-version") {
+		profile.KafkaVersion = f.kafkaVersion
+	}
+	if changed("client-id") {
+		profile.ClientID = f.clientID
+	}
+	if changed("dial-timeout") {
+		profile.DialTimeout = f.dialTimeout
+	}
+	if changed("read-timeout") {
+		profile.ReadTimeout = f.readTimeout
+	}
+	if changed("write-timeout") {
+		profile.WriteTimeout = f.writeTimeout
+	}
+	if changed("metadata-retry-max") {
+		profile.MetadataRetryMax = f.metadataRetryMax
+	}
+
+	return applied
+}
+
 // NewProfileAddCmd creates the profile add command
 func NewProfileAddCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
-	var (
-		profileType      string
-		bootstrapServers string
-		region           string
-		clusterARN       string
-		authMethod       string
-		securityProtocol string
-		saslMechanism    string
-		saslUsername     string
-		saslPassword     string
-		sslCAFile        string
-		sslCertFile      string
-		sslKeyFile       string
-		sslPassword      string
-		sslCheckHostname bool
-	)
+	var flags profileFlags
 
 	cmd := &cobra.Command{
 		Use:   "add NAME",
@@ -105,43 +396,61 @@ func NewProfileAddCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			// Create profile based on type
 			profile := &config.Profile{
 				Name: name,
-				Type: profileType,
+				Type: flags.profileType,
 			}
 
-			switch profileType {
+			switch flags.profileType {
 			case "msk":
-				if region == "" {
+				if flags.region == "" {
 					return fmt.Errorf("region is required for MSK profiles")
 				}
-				if clusterARN == "" {
+				if flags.clusterARN == "" {
 					return fmt.Errorf("cluster-arn is required for MSK profiles")
 				}
 
-				profile.Region = region
-				profile.ClusterARN = clusterARN
-				profile.AuthMethod = authMethod
+				profile.Region = flags.region
+				profile.ClusterARN = flags.clusterARN
+				profile.AuthMethod = flags.authMethod
 				if profile.AuthMethod == "" {
 					profile.AuthMethod = "IAM" // Default to IAM
 				}
+				profile.AWSProfile = flags.awsProfile
+				profile.AWSRoleARN = flags.awsRoleARN
 
 			case "kafka":
-				if bootstrapServers == "" {
+				if flags.bootstrapServers == "" {
 					return fmt.Errorf("bootstrap-servers is required for Kafka profiles")
 				}
 
-				profile.BootstrapServers = bootstrapServers
-				profile.SecurityProtocol = securityProtocol
-				profile.SASLMechanism = saslMechanism
-				profile.SASLUsername = saslUsername
-				profile.SASLPassword = saslPassword
-				profile.SSLCAFile = sslCAFile
-				profile.SSLCertFile = sslCertFile
-				profile.SSLKeyFile = sslKeyFile
-				profile.SSLPassword = sslPassword
-				profile.SSLCheckHostname = sslCheckHostname
+				profile.BootstrapServers = flags.bootstrapServers
+				profile.SecurityProtocol = flags.securityProtocol
+				profile.SASLMechanism = flags.saslMechanism
+				profile.SASLUsername = flags.saslUsername
+				profile.SASLPassword = flags.saslPassword
+				profile.OAuthTokenCommand = flags.oauthTokenCmd
+				profile.OAuthTokenEnv = flags.oauthTokenEnv
+				profile.SSLCAFile = flags.sslCAFile
+				profile.SSLCertFile = flags.sslCertFile
+				profile.SSLKeyFile = flags.sslKeyFile
+				profile.SSLPassword = flags.sslPassword
+				profile.SSLCheckHostname = flags.sslCheckHostname
+				profile.TLSServerName = flags.tlsServerName
 
 			default:
-				return fmt.Errorf("invalid profile type: %s (must be 'kafka' or 'msk')", profileType)
+				return fmt.Errorf("invalid profile type: %s (must be 'kafka' or 'msk')", flags.profileType)
+			}
+
+			profile.TLSMinVersion = flags.tlsMinVersion
+			profile.SchemaRegistryURL = flags.schemaRegistry
+			profile.KafkaVersion = flags.kafkaVersion
+			profile.ClientID = flags.clientID
+			profile.DialTimeout = flags.dialTimeout
+			profile.ReadTimeout = flags.readTimeout
+			profile.WriteTimeout = flags.writeTimeout
+			profile.MetadataRetryMax = flags.metadataRetryMax
+
+			if err := flags.checkFiles(profile); err != nil {
+				return err
 			}
 
 			// Add profile
@@ -154,33 +463,62 @@ func NewProfileAddCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&profileType, "type", "", "profile type (kafka or msk)")
-	cmd.Flags().StringVar(&bootstrapServers, "bootstrap-servers", "", "Kafka bootstrap servers (comma-separated)")
-	cmd.Flags().StringVar(&region, "region", "", "AWS region for MSK")
-	cmd.Flags().StringVar(&clusterARN, "cluster-arn", "", "MSK cluster ARN")
-	cmd.Flags().StringVar(&authMethod, "auth-method", "IAM", "MSK authentication method (IAM or SASL_SCRAM)")
-	cmd.Flags().StringVar(&securityProtocol, "security-protocol", "PLAINTEXT", "security protocol (PLAINTEXT, SSL, SASL_PLAINTEXT, SASL_SSL)")
-	cmd.Flags().StringVar(&saslMechanism, "sasl-mechanism", "", "SASL mechanism (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512, GSSAPI)")
-	cmd.Flags().StringVar(&saslUsername, "sasl-username", "", "SASL username")
-	cmd.Flags().StringVar(&saslPassword, "sasl-password", "", "SASL password")
-	cmd.Flags().StringVar(&sslCAFile, "ssl-ca-file", "", "SSL CA certificate file")
-	cmd.Flags().StringVar(&sslCertFile, "ssl-cert-file", "", "SSL client certificate file")
-	cmd.Flags().StringVar(&sslKeyFile, "ssl-key-file", "", "SSL client key file")
-	cmd.Flags().StringVar(&sslPassword, "ssl-password", "", "SSL key password")
-	cmd.Flags().BoolVar(&sslCheckHostname, "ssl-check-hostname", false, "enable SSL hostname verification")
+	flags.register(cmd)
 
 	cmd.MarkFlagRequired("type")
 
 	return cmd
 }
 
+// NewProfileEditCmd creates the profile edit command
+func NewProfileEditCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var flags profileFlags
+
+	cmd := &cobra.Command{
+		Use:   "edit NAME",
+		Short: "Edit an existing profile",
+		Long:  "Modify an existing profile in place. Only the flags that are specified are changed.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			existing, err := cfg.GetProfile(name)
+			if err != nil {
+				return err
+			}
+
+			// Work on a copy so a validation failure leaves the profile untouched
+			profile := *existing
+			if !flags.applyChanged(cmd, &profile) {
+				return fmt.Errorf("no changes specified")
+			}
+
+			if err := flags.checkFiles(&profile); err != nil {
+				return err
+			}
+
+			if err := cfg.UpdateProfile(&profile); err != nil {
+				return fmt.Errorf("failed to update profile: %w", err)
+			}
+
+			fmt.Printf("Profile '%s' updated successfully\n", name)
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+
+	return cmd
+}
+
 // NewProfileUseCmd creates the profile use command
 func NewProfileUseCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "use NAME",
-		Short: "Switch to a profile",
-		Long:  "Switch to the specified profile as the active profile.",
-		Args:  cobra.ExactArgs(1),
+		Use:               "use NAME",
+		Short:             "Switch to a profile",
+		Long:              "Switch to the specified profile as the active profile.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProfileNames(cfg),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
@@ -202,15 +540,38 @@ func NewProfileUseCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	return cmd
 }
 
+// NewProfileRenameCmd creates the profile rename command
+func NewProfileRenameCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename OLD NEW",
+		Short: "Rename a profile",
+		Long:  "Rename an existing profile. The active profile selection follows the renamed profile.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldName, newName := args[0], args[1]
+
+			if err := cfg.RenameProfile(oldName, newName); err != nil {
+				return fmt.Errorf("failed to rename profile: %w", err)
+			}
+
+			fmt.Printf("Profile '%s' renamed to '%s'\n", oldName, newName)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
 // NewProfileDeleteCmd creates the profile delete command
 func NewProfileDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	var force bool
 
 	cmd := &cobra.Command{
-		Use:   "delete NAME",
-		Short: "Delete a profile",
-		Long:  "Delete the specified profile from the configuration.",
-		Args:  cobra.ExactArgs(1),
+		Use:               "delete NAME",
+		Short:             "Delete a profile",
+		Long:              "Delete the specified profile from the configuration.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProfileNames(cfg),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
@@ -220,14 +581,10 @@ func NewProfileDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 			}
 
 			// Prevent deletion of active profile without confirmation
-			if name == cfg.ActiveProfile && !force {
-				fmt.Printf("Profile '%s' is currently active. Are you sure you want to delete it? (y/N): ", name)
-				var response string
-				fmt.Scanln(&response)
-				if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-					fmt.Println("Profile deletion cancelled")
-					return nil
-				}
+			if name == cfg.ActiveProfile &&
+				!confirmAction(cmd, force, fmt.Sprintf("Profile '%s' is currently active. Are you sure you want to delete it? (y/N): ", name)) {
+				fmt.Println("Profile deletion cancelled")
+				return nil
 			}
 
 			// Delete profile
@@ -252,3 +609,63 @@ func NewProfileDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 
 	return cmd
 }
+
+// NewProfileTestCmd creates the profile test command
+func NewProfileTestCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "test NAME",
+		Short: "Test connectivity for a profile",
+		Long:  "Connect to the cluster described by the specified profile and verify that it responds.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			profile, err := cfg.GetProfile(name)
+			if err != nil {
+				return err
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// For MSK the bootstrap brokers are resolved while building the client
+			if profile.Type == "msk" {
+				fmt.Printf("Resolved MSK bootstrap brokers: %s\n", profile.BootstrapServers)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			if err := kafkaClient.Ping(ctx); err != nil {
+				return fmt.Errorf("profile '%s' failed connectivity test: %w", name, err)
+			}
+
+			brokers, controllerID, err := kafkaClient.AdminClient.DescribeCluster()
+			if err != nil {
+				return fmt.Errorf("failed to describe cluster: %w", err)
+			}
+
+			fmt.Printf("Profile '%s' connected successfully\n", name)
+			fmt.Printf("Brokers: %d\n", len(brokers))
+			fmt.Printf("Controller: %d\n", controllerID)
+			for _, broker := range brokers {
+				if broker.ID() == controllerID {
+					fmt.Printf("Controller Address: %s\n", broker.Addr())
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second, "timeout for the connectivity test")
+
+	return cmd
+}
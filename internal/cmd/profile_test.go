@@ -0,0 +1,364 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nipunap/kim/internal/client"
+	"github.com/nipunap/kim/internal/config"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/internal/testutil"
+)
+
+// useMockClient makes commands connect through the given mock for the duration of a test
+func useMockClient(t *testing.T, mock *testutil.MockClient) {
+	old := newClientManager
+	newClientManager = func(log *logger.Logger) *client.Manager {
+		return mock.ClientManager()
+	}
+	t.Cleanup(func() {
+		newClientManager = old
+	})
+}
+
+func TestProfileTestCommand(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockBroker(1, "broker-1:9092")
+	mock.AddMockBroker(2, "broker-2:9092")
+	mock.SetControllerID(1)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewProfileCmd(cfg, log), "test", "test-kafka")
+	if err != nil {
+		t.Errorf("Profile test should succeed against a healthy cluster: %v", err)
+	}
+}
+
+func TestProfileTestCommandPingFailure(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.SetShouldFailPing(true)
+	useMockClient(t, mock)
+
+	_, err := executeCommand(NewProfileCmd(cfg, log), "test", "test-kafka", "--timeout", "1s")
+	if err == nil {
+		t.Fatal("Profile test should fail when ping fails")
+	}
+	if !strings.Contains(err.Error(), "mock ping failed") {
+		t.Errorf("Expected underlying ping error to propagate, got: %v", err)
+	}
+}
+
+func TestProfileTestCommandUnknownProfile(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	_, err := executeCommand(NewProfileCmd(cfg, log), "test", "does-not-exist")
+	if err == nil {
+		t.Error("Profile test should fail for an unknown profile")
+	}
+}
+
+func TestProfileEditPreservesUnspecifiedFields(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	cfg.Profiles["sasl"] = &config.Profile{
+		Name:             "sasl",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9092",
+		SecurityProtocol: "SASL_PLAINTEXT",
+		SASLMechanism:    "PLAIN",
+		SASLUsername:     "testuser",
+		SASLPassword:     "testpass",
+	}
+
+	_, err := executeCommand(NewProfileCmd(cfg, log), "edit", "sasl", "--bootstrap-servers", "broker:9094")
+
+	profile := cfg.Profiles["sasl"]
+	if profile.BootstrapServers != "broker:9094" {
+		t.Fatalf("Bootstrap servers were not updated. Got: %s, Error: %v", profile.BootstrapServers, err)
+	}
+	if profile.SecurityProtocol != "SASL_PLAINTEXT" {
+		t.Errorf("Security protocol should be preserved, got: %s", profile.SecurityProtocol)
+	}
+	if profile.SASLMechanism != "PLAIN" {
+		t.Errorf("SASL mechanism should be preserved, got: %s", profile.SASLMechanism)
+	}
+	if profile.SASLUsername != "testuser" || profile.SASLPassword != "testpass" {
+		t.Errorf("SASL credentials should be preserved, got: %s/%s", profile.SASLUsername, profile.SASLPassword)
+	}
+}
+
+func TestProfileAddConnectionSettings(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	_, err := executeCommand(NewProfileCmd(cfg, log), "add", "vpn", "--type", "kafka",
+		"--bootstrap-servers", "broker:9092", "--dial-timeout", "1m", "--read-timeout", "2m",
+		"--write-timeout", "90s", "--metadata-retry-max", "10")
+
+	// Saving fails without a config file, so check the profile in memory
+	profile, exists := cfg.Profiles["vpn"]
+	if !exists {
+		t.Fatalf("Profile 'vpn' was not added. Error: %v", err)
+	}
+	if profile.DialTimeout != time.Minute || profile.ReadTimeout != 2*time.Minute ||
+		profile.WriteTimeout != 90*time.Second || profile.MetadataRetryMax != 10 {
+		t.Errorf("Connection settings were not stored: %+v", profile)
+	}
+
+	_, err = executeCommand(NewProfileCmd(cfg, log), "add", "bad", "--type", "kafka",
+		"--bootstrap-servers", "broker:9092", "--metadata-retry-max", "-1")
+	if _, exists := cfg.Profiles["bad"]; exists || err == nil {
+		t.Error("Profile add should reject negative metadata retries")
+	}
+}
+
+func TestProfileEditValidation(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	// Unknown profile
+	_, err := executeCommand(NewProfileCmd(cfg, log), "edit", "does-not-exist", "--bootstrap-servers", "broker:9094")
+	if err == nil {
+		t.Error("Editing an unknown profile should fail")
+	}
+
+	// Invalid change must leave the profile untouched
+	_, err = executeCommand(NewProfileCmd(cfg, log), "edit", "test-kafka", "--security-protocol", "INVALID")
+	if err == nil {
+		t.Error("Editing with an invalid security protocol should fail")
+	}
+	if cfg.Profiles["test-kafka"].SecurityProtocol != "PLAINTEXT" {
+		t.Errorf("Failed edit should not modify the profile, got: %s", cfg.Profiles["test-kafka"].SecurityProtocol)
+	}
+}
+
+func TestProfileEditWithOnlyGlobalFlags(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Cleanup(func() { assumeYes = false })
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	// Global flags are not changes to the profile
+	_, err := executeCommand(NewRootCmd(cfg, log), "--yes", "profile", "edit", "test-kafka")
+	if err == nil || !strings.Contains(err.Error(), "no changes specified") {
+		t.Errorf("Expected 'no changes specified', got %v", err)
+	}
+}
+
+func TestProfileRenameCommand(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	_, err := executeCommand(NewProfileCmd(cfg, log), "rename", "test-kafka", "local")
+
+	if _, exists := cfg.Profiles["local"]; !exists {
+		t.Fatalf("Profile should be renamed to 'local'. Error: %v", err)
+	}
+	if cfg.ActiveProfile != "local" {
+		t.Errorf("Active profile should follow the rename, got: %s", cfg.ActiveProfile)
+	}
+
+	_, err = executeCommand(NewProfileCmd(cfg, log), "rename", "local", "test-msk")
+	if err == nil {
+		t.Error("Renaming to an existing profile should fail")
+	}
+}
+
+func TestProfileShowRedactsSecrets(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	cfg.Profiles["sasl"] = &config.Profile{
+		Name:             "sasl",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9092",
+		SecurityProtocol: "SASL_SSL",
+		SASLMechanism:    "PLAIN",
+		SASLUsername:     "testuser",
+		SASLPassword:     "sasl-secret",
+		SSLPassword:      "ssl-secret",
+		DialTimeout:      5 * time.Second,
+	}
+
+	for _, format := range []string{"table", "json", "yaml"} {
+		var err error
+		output := captureStdout(func() {
+			_, err = executeCommand(NewProfileCmd(cfg, log), "show", "sasl", "--format", format)
+		})
+		if err != nil {
+			t.Fatalf("profile show --format %s failed: %v", format, err)
+		}
+		if strings.Contains(output, "sasl-secret") || strings.Contains(output, "ssl-secret") {
+			t.Errorf("Passwords should be redacted in %s output, got:\n%s", format, output)
+		}
+		if !strings.Contains(output, redactedSecret) {
+			t.Errorf("Expected redacted passwords in %s output, got:\n%s", format, output)
+		}
+		if !strings.Contains(output, "testuser") || !strings.Contains(output, "5s") {
+			t.Errorf("Expected profile settings in %s output, got:\n%s", format, output)
+		}
+	}
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewProfileCmd(cfg, log), "show", "sasl", "--show-secrets")
+	})
+	if err != nil {
+		t.Fatalf("profile show --show-secrets failed: %v", err)
+	}
+	if !strings.Contains(output, "sasl-secret") || !strings.Contains(output, "ssl-secret") {
+		t.Errorf("Passwords should be shown with --show-secrets, got:\n%s", output)
+	}
+
+	if _, err := executeCommand(NewProfileCmd(cfg, log), "show", "does-not-exist"); err == nil {
+		t.Error("profile show should fail for an unknown profile")
+	}
+}
+
+func TestProfileListActiveOnly(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewProfileCmd(cfg, log), "list", "--active-only", "--format", "json")
+	})
+	if err != nil {
+		t.Fatalf("profile list --active-only failed: %v", err)
+	}
+	if !strings.Contains(output, "test-kafka") || strings.Contains(output, "test-msk") {
+		t.Errorf("Expected only the active profile, got:\n%s", output)
+	}
+
+	// Without an active profile nothing is listed
+	cfg.ActiveProfile = ""
+	output = captureStdout(func() {
+		_, err = executeCommand(NewProfileCmd(cfg, log), "list", "--active-only", "--format", "json")
+	})
+	if err != nil {
+		t.Fatalf("profile list --active-only failed: %v", err)
+	}
+	if strings.Contains(output, "test-kafka") || strings.Contains(output, "test-msk") {
+		t.Errorf("Expected no profiles, got:\n%s", output)
+	}
+}
+
+func TestProfileActive(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewProfileCmd(cfg, log), "active")
+	})
+	if err != nil {
+		t.Fatalf("profile active failed: %v", err)
+	}
+	if output != "test-kafka\n" {
+		t.Errorf("Expected the active profile name, got %q", output)
+	}
+
+	// Without an active profile the command prints nothing and fails
+	cfg.ActiveProfile = ""
+	var cmdOutput string
+	output = captureStdout(func() {
+		cmdOutput, err = executeCommand(NewProfileCmd(cfg, log), "active")
+	})
+	if err == nil {
+		t.Error("profile active should fail without an active profile")
+	}
+	if output != "" || cmdOutput != "" {
+		t.Errorf("profile active should print nothing without an active profile, got %q and %q", output, cmdOutput)
+	}
+}
+
+func TestProfileAddChecksSSLFiles(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, []byte("pem"), 0600); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+	missing := filepath.Join(dir, "nope.pem")
+
+	_, err := executeCommand(NewProfileCmd(cfg, log), "add", "missing-ca",
+		"--type", "kafka", "--bootstrap-servers", "localhost:9093",
+		"--security-protocol", "SSL", "--ssl-ca-file", missing)
+	if err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("Expected an error naming the missing CA file, got: %v", err)
+	}
+	if _, exists := cfg.Profiles["missing-ca"]; exists {
+		t.Error("A profile with a missing CA file should not be added")
+	}
+
+	_, err = executeCommand(NewProfileCmd(cfg, log), "add", "present-ca",
+		"--type", "kafka", "--bootstrap-servers", "localhost:9093",
+		"--security-protocol", "SSL", "--ssl-ca-file", caFile)
+	if _, exists := cfg.Profiles["present-ca"]; !exists {
+		t.Fatalf("A profile with an existing CA file should be added. Error: %v", err)
+	}
+
+	_, err = executeCommand(NewProfileCmd(cfg, log), "add", "templated",
+		"--type", "kafka", "--bootstrap-servers", "localhost:9093",
+		"--security-protocol", "SSL", "--ssl-ca-file", missing, "--skip-file-check")
+	if _, exists := cfg.Profiles["templated"]; !exists {
+		t.Errorf("--skip-file-check should allow missing files. Error: %v", err)
+	}
+
+	// Editing checks the files too
+	_, err = executeCommand(NewProfileCmd(cfg, log), "edit", "present-ca", "--ssl-cert-file", missing)
+	if err == nil {
+		t.Error("Editing a profile to a missing cert file should fail")
+	}
+	if cfg.Profiles["present-ca"].SSLCertFile != "" {
+		t.Error("A failed edit should leave the profile untouched")
+	}
+}
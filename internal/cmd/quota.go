@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nipunap/kim/internal/config"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/internal/manager"
+	"github.com/nipunap/kim/internal/ui"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/spf13/cobra"
+)
+
+// NewQuotaCmd creates the quota command
+func NewQuotaCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Manage Kafka client quotas",
+		Long: `Commands for listing, setting and deleting user and client ID quotas.
+
+Use "` + manager.DefaultQuotaEntity + `" as the user or client ID to manage the default quota.`,
+	}
+
+	cmd.AddCommand(NewQuotaListCmd(cfg, log))
+	cmd.AddCommand(NewQuotaSetCmd(cfg, log))
+	cmd.AddCommand(NewQuotaDeleteCmd(cfg, log))
+
+	return cmd
+}
+
+// NewQuotaListCmd creates the quota list command
+func NewQuotaListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		entity types.QuotaEntity
+		format string
+		tmpl   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List client quotas",
+		Long:  "List the quotas of all users and client IDs, optionally filtered by user or client ID.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("format") {
+				format = defaultFormat(cfg)
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create quota manager
+			quotaManager := manager.NewQuotaManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// List quotas
+			quotas, err := quotaManager.ListQuotas(ctx, &entity)
+			if err != nil {
+				return fmt.Errorf("failed to list quotas: %w", err)
+			}
+
+			// Display results
+			displayOpts := newDisplayOptions(format, tmpl)
+
+			return ui.DisplayQuotaList(quotas, displayOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&entity.User, "user", "", "only list quotas for this user")
+	cmd.Flags().StringVar(&entity.ClientID, "client-id", "", "only list quotas for this client ID")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// NewQuotaSetCmd creates the quota set command
+func NewQuotaSetCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		entity            types.QuotaEntity
+		producerByteRate  float64
+		consumerByteRate  float64
+		requestPercentage float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set client quotas",
+		Long: `Set quotas on a user, a client ID, or a user and client ID pair. Quotas that
+are not given are left unchanged.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			values := make(map[string]float64)
+			if cmd.Flags().Changed("producer-byte-rate") {
+				values["producer_byte_rate"] = producerByteRate
+			}
+			if cmd.Flags().Changed("consumer-byte-rate") {
+				values["consumer_byte_rate"] = consumerByteRate
+			}
+			if cmd.Flags().Changed("request-percentage") {
+				values["request_percentage"] = requestPercentage
+			}
+			if len(values) == 0 {
+				return fmt.Errorf("at least one of --producer-byte-rate, --consumer-byte-rate or --request-percentage is required")
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create quota manager
+			quotaManager := manager.NewQuotaManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Set quotas
+			if err := quotaManager.SetQuotas(ctx, &entity, values); err != nil {
+				return fmt.Errorf("failed to set quotas: %w", err)
+			}
+
+			fmt.Printf("Set %d quota(s)\n", len(values))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&entity.User, "user", "", "user the quotas apply to")
+	cmd.Flags().StringVar(&entity.ClientID, "client-id", "", "client ID the quotas apply to")
+	cmd.Flags().Float64Var(&producerByteRate, "producer-byte-rate", 0, "maximum bytes per second a producer may publish")
+	cmd.Flags().Float64Var(&consumerByteRate, "consumer-byte-rate", 0, "maximum bytes per second a consumer may fetch")
+	cmd.Flags().Float64Var(&requestPercentage, "request-percentage", 0, "maximum percentage of broker request handler and network thread time")
+
+	return cmd
+}
+
+// NewQuotaDeleteCmd creates the quota delete command
+func NewQuotaDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		entity types.QuotaEntity
+		keys   []string
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete client quotas",
+		Long: fmt.Sprintf(`Delete quotas from a user, a client ID, or a user and client ID pair.
+
+By default all quotas of the entity are deleted; use --quota to delete only some
+(%s).`, strings.Join(manager.QuotaKeys, ", ")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Confirm deletion unless --force or --yes is used
+			if !confirmAction(cmd, force, "Are you sure you want to delete these quotas? (y/N): ") {
+				fmt.Println("Quota deletion cancelled")
+				return nil
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create quota manager
+			quotaManager := manager.NewQuotaManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Delete quotas
+			if err := quotaManager.DeleteQuotas(ctx, &entity, keys); err != nil {
+				return fmt.Errorf("failed to delete quotas: %w", err)
+			}
+
+			fmt.Println("Quotas deleted successfully")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&entity.User, "user", "", "user the quotas apply to")
+	cmd.Flags().StringVar(&entity.ClientID, "client-id", "", "client ID the quotas apply to")
+	cmd.Flags().StringSliceVar(&keys, "quota", nil, "quota types to delete (default all)")
+	cmd.Flags().BoolVar(&force, "force", false, "skip confirmation prompt")
+
+	return cmd
+}
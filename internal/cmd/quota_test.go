@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+)
+
+func TestQuotaSetAndList(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	captureStdout(func() {
+		if _, err := executeCommand(NewQuotaCmd(cfg, log), "set", "--user", "alice", "--producer-byte-rate", "1048576"); err != nil {
+			t.Fatalf("quota set failed: %v", err)
+		}
+	})
+
+	output := captureStdout(func() {
+		if _, err := executeCommand(NewQuotaCmd(cfg, log), "list", "--user", "alice", "--format", "json"); err != nil {
+			t.Fatalf("quota list failed: %v", err)
+		}
+	})
+
+	var quotas []*types.QuotaEntry
+	if err := json.Unmarshal([]byte(output), &quotas); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(quotas) != 1 || quotas[0].User != "alice" || quotas[0].Values["producer_byte_rate"] != 1048576 {
+		t.Errorf("Expected alice's producer byte-rate quota, got %+v", quotas)
+	}
+}
+
+func TestQuotaSetRequiresValue(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	if _, err := executeCommand(NewQuotaCmd(cfg, log), "set", "--user", "alice"); err == nil {
+		t.Error("quota set without a quota value should fail")
+	}
+}
@@ -1,11 +1,20 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/config"
 	"github.com/nipunap/kim/internal/logger"
 	"github.com/nipunap/kim/internal/ui"
+	"github.com/nipunap/kim/pkg/types"
 
 	"github.com/spf13/cobra"
 )
@@ -13,15 +22,66 @@ import (
 var (
 	cfgFile     string
 	debug       bool
+	quiet       bool
+	logLevel    string
+	logFile     string
+	logFormat   string
 	interactive bool
+	noColor     bool
+	clientID    string
+
+	// assumeYes answers every confirmation prompt with yes, set by --yes
+	assumeYes bool
+
+	// commandTimeout limits the admin operations of a command, set by --timeout
+	commandTimeout time.Duration
+
+	// colorScheme is the color scheme used for table output, set from the
+	// settings and --no-color before a command runs
+	colorScheme string
 )
 
+// newClientManager creates the client manager used by commands, applying the
+// --client-id override. Tests replace it to inject mock clients.
+var newClientManager = func(log *logger.Logger) *client.Manager {
+	m := client.NewManager(log)
+	m.SetClientID(clientID)
+	return m
+}
+
 // Execute executes the root command
 func Execute(cfg *config.Config, log *logger.Logger) error {
 	rootCmd := NewRootCmd(cfg, log)
 	return rootCmd.Execute()
 }
 
+// exitCodes maps error codes to process exit codes so scripts can tell
+// failures apart. Other errors exit with 1.
+var exitCodes = map[string]int{
+	types.ErrCodeClusterUnhealthy:     2,
+	types.ErrCodeConnectionFailed:     3,
+	types.ErrCodeDNSResolution:        3,
+	types.ErrCodeConnectionTimeout:    4,
+	types.ErrCodeTLSHandshake:         5,
+	types.ErrCodeAuthFailed:           6,
+	types.ErrCodeLagThresholdExceeded: 7,
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var kimErr *types.KimError
+	if errors.As(err, &kimErr) {
+		if code, ok := exitCodes[kimErr.Code]; ok {
+			return code
+		}
+	}
+	return 1
+}
+
 // NewRootCmd creates the root command
 func NewRootCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	rootCmd := &cobra.Command{
@@ -30,11 +90,57 @@ func NewRootCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 		Long: `Kim is a powerful command-line interface for managing Kafka and MSK clusters.
 It provides an intuitive way to interact with Kafka topics, consumer groups, and messages
 with support for both regular Kafka and AWS MSK clusters.`,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Rebuild the logger for --log-file and --log-format. Logging to a
+			// file keeps stdout for command output.
+			if logFile != "" || logFormat != "" {
+				newLog, err := logger.NewWithOptions(logger.Options{Format: logFormat, File: logFile})
+				if err != nil {
+					return err
+				}
+				*log = *newLog
+			}
+
 			if debug {
-				log.SetLevel("debug")
+				if logLevel != "" && logLevel != "debug" {
+					return fmt.Errorf("--debug cannot be combined with --log-level %s", logLevel)
+				}
+				logLevel = "debug"
+			}
+			if quiet {
+				if logLevel != "" {
+					return fmt.Errorf("--quiet cannot be combined with --debug or --log-level")
+				}
+				logLevel = "warn"
+			}
+			if logLevel != "" {
+				if err := log.SetLevel(logLevel); err != nil {
+					return err
+				}
 				log.Debug("Debug logging enabled")
 			}
+
+			// Replace the default config with the one given by --config
+			if cfgFile != "" {
+				loaded, err := config.NewWithPath(cfgFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config %s: %w", cfgFile, err)
+				}
+				*cfg = *loaded
+				log.Debug("Using config file", "path", cfgFile)
+			}
+			for _, warning := range cfg.Warnings {
+				log.Warn(warning)
+			}
+
+			colorScheme = ""
+			if cfg.Settings != nil {
+				colorScheme = cfg.Settings.ColorScheme
+			}
+			if noColor {
+				colorScheme = "none"
+			}
+			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if interactive {
@@ -52,19 +158,88 @@ with support for both regular Kafka and AWS MSK clusters.`,
 	}
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.github.com/nipunap/kim/config.yaml)")
-	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug logging")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/kim/config.yaml or $HOME/.kim/config.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug logging (same as --log-level debug)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only log warnings and errors (same as --log-level warn)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "log level (debug, info, warn, error) (default info)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "write logs to this file instead of stdout")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "log format (json, console) (default console on a terminal, json otherwise)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().StringVar(&clientID, "client-id", "", "client ID sent to the brokers, overriding the profile's")
+	rootCmd.PersistentFlags().DurationVar(&commandTimeout, "timeout", defaultCommandTimeout, "timeout for admin operations, checked between broker requests (0 disables)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "answer yes to all confirmation prompts")
 	rootCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "run in interactive mode")
 
 	// Add subcommands
 	rootCmd.AddCommand(NewTopicCmd(cfg, log))
 	rootCmd.AddCommand(NewGroupCmd(cfg, log))
 	rootCmd.AddCommand(NewMessageCmd(cfg, log))
+	rootCmd.AddCommand(NewClusterCmd(cfg, log))
+	rootCmd.AddCommand(NewACLCmd(cfg, log))
+	rootCmd.AddCommand(NewQuotaCmd(cfg, log))
 	rootCmd.AddCommand(NewProfileCmd(cfg, log))
+	rootCmd.AddCommand(NewConfigCmd(cfg, log))
+	rootCmd.AddCommand(NewCompletionCmd())
+	rootCmd.AddCommand(NewVersionCmd())
 
 	return rootCmd
 }
 
+// confirmAction asks the user to confirm an action on the command's input.
+// The command's --force or the global --yes confirm without prompting.
+func confirmAction(cmd *cobra.Command, force bool, prompt string) bool {
+	if force || assumeYes {
+		return true
+	}
+	return confirm(cmd.OutOrStdout(), cmd.InOrStdin(), prompt)
+}
+
+// confirm writes a prompt to w and reads a line from r. Only y or yes,
+// in any case, confirms; a read error or end of input does not.
+func confirm(w io.Writer, r io.Reader, msg string) bool {
+	fmt.Fprint(w, msg)
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(w)
+		return false
+	}
+
+	response := strings.ToLower(strings.TrimSpace(line))
+	return response == "y" || response == "yes"
+}
+
+// defaultCommandTimeout is the default of the --timeout flag
+const defaultCommandTimeout = 30 * time.Second
+
+// commandContext returns the context for a command's admin operations, which
+// expires after --timeout. Managers check it between requests, so it does not
+// interrupt a single blocking sarama call; the client's read timeout bounds that.
+func commandContext() (context.Context, context.CancelFunc) {
+	return withCommandTimeout(context.Background())
+}
+
+// withCommandTimeout derives a context from parent that expires after --timeout
+func withCommandTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	if commandTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, commandTimeout)
+}
+
+// newDisplayOptions creates display options for a command's --format and --template
+// flags. A template selects the go-template format.
+func newDisplayOptions(format, tmpl string) *types.DisplayOptions {
+	if tmpl != "" {
+		format = "go-template"
+	}
+	return &types.DisplayOptions{
+		Format:      format,
+		Template:    tmpl,
+		ColorScheme: colorScheme,
+	}
+}
+
 // runInteractiveMode starts the interactive mode
 func runInteractiveMode(cfg *config.Config, log *logger.Logger) error {
 	ui := ui.NewInteractiveMode(cfg, log)
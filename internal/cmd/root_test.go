@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, 0},
+		{"generic", errors.New("topic not found"), 1},
+		{"auth", fmt.Errorf("failed to create client: %w", types.NewKimError(types.ErrCodeAuthFailed, "check SASL credentials")), 6},
+		{"tls", types.NewKimError(types.ErrCodeTLSHandshake, "check TLS"), 5},
+		{"timeout", types.NewKimError(types.ErrCodeConnectionTimeout, "check network"), 4},
+		{"dns", types.NewKimError(types.ErrCodeDNSResolution, "check hosts"), 3},
+		{"unhealthy", types.NewKimError(types.ErrCodeClusterUnhealthy, "cluster health is warn"), 2},
+		{"lag", types.NewKimError(types.ErrCodeLagThresholdExceeded, "lag exceeds 100 on orders-0 (lag 150)"), 7},
+		{"unknown code", types.NewKimError("SOMETHING_ELSE", "oops"), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.expected {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfigFlagOverride(t *testing.T) {
+	homeDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Cleanup(func() { cfgFile = "" })
+
+	configPath := filepath.Join(t.TempDir(), "x.yaml")
+	if err := os.WriteFile(configPath, []byte(`active_profile: from-file
+profiles:
+  from-file:
+    name: from-file
+    type: kafka
+    bootstrap_servers: localhost:9092
+settings:
+  page_size: 5
+  default_format: table
+`), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	if _, err := executeCommand(NewRootCmd(cfg, log), "--config", configPath,
+		"profile", "add", "added", "--type", "kafka", "--bootstrap-servers", "localhost:9093"); err != nil {
+		t.Fatalf("profile add failed: %v", err)
+	}
+
+	// The config was read from the file
+	if cfg.ActiveProfile != "from-file" {
+		t.Errorf("Expected active profile from-file, got %s", cfg.ActiveProfile)
+	}
+	if _, exists := cfg.Profiles["test-kafka"]; exists {
+		t.Error("Expected the default config to be replaced")
+	}
+
+	// and written back to it
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(data), "added") || !strings.Contains(string(data), "from-file") {
+		t.Errorf("Expected both profiles in %s, got:\n%s", configPath, data)
+	}
+
+	// The home directory config was left alone
+	if _, err := os.Stat(filepath.Join(homeDir, ".kim", "config.yaml")); !os.IsNotExist(err) {
+		t.Errorf("Expected no config in the home directory, got %v", err)
+	}
+}
+
+func TestCommandContextTimeout(t *testing.T) {
+	t.Cleanup(func() { commandTimeout = 0 })
+
+	flag := NewRootCmd(testutil.TestConfig(), testutil.TestLogger()).PersistentFlags().Lookup("timeout")
+	if flag == nil || flag.DefValue != "30s" {
+		t.Fatalf("Expected a --timeout flag defaulting to 30s, got %v", flag)
+	}
+
+	commandTimeout = time.Millisecond
+	ctx, cancel := commandContext()
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("Expected the command context to have a deadline")
+	}
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", ctx.Err())
+	}
+
+	// A zero timeout disables the deadline
+	commandTimeout = 0
+	ctx, cancel = commandContext()
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no deadline when the timeout is disabled")
+	}
+}
+
+func TestLogLevelFlag(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Cleanup(func() { logLevel, debug = "", false })
+
+	log := testutil.TestLogger()
+	run := func(args ...string) error {
+		var err error
+		captureStdout(func() {
+			_, err = executeCommand(NewRootCmd(testutil.TestConfig(), log), append(args, "profile", "list")...)
+		})
+		return err
+	}
+
+	if err := run("--log-level", "warn"); err != nil {
+		t.Errorf("--log-level warn failed: %v", err)
+	}
+	if log.Desugar().Core().Enabled(zapcore.InfoLevel) {
+		t.Error("Expected info logging to be disabled at warn level")
+	}
+
+	if err := run("--log-level", "verbose"); err == nil {
+		t.Error("Expected an error for an invalid log level")
+	}
+	if err := run("--debug", "--log-level", "error"); err == nil {
+		t.Error("Expected an error when --debug and --log-level disagree")
+	}
+
+	if err := run("--debug"); err != nil {
+		t.Errorf("--debug failed: %v", err)
+	}
+	if !log.Desugar().Core().Enabled(zapcore.DebugLevel) {
+		t.Error("Expected debug logging to be enabled by --debug")
+	}
+}
+
+func TestLogFileFlag(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Cleanup(func() { logFile, logLevel, debug = "", "", false })
+
+	path := filepath.Join(t.TempDir(), "kim.log")
+	log := testutil.TestLogger()
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewRootCmd(testutil.TestConfig(), log), "--log-file", path, "--debug", "profile", "list")
+	})
+	if err != nil {
+		t.Fatalf("profile list failed: %v", err)
+	}
+	log.Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "Debug logging enabled") {
+		t.Errorf("Expected the debug log in %s, got %q", path, data)
+	}
+	if strings.Contains(output, "Debug logging enabled") {
+		t.Errorf("Expected no logs on stdout, got %q", output)
+	}
+}
+
+func TestQuietFlag(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Cleanup(func() { logFile, logLevel, debug, quiet = "", "", false, false })
+
+	path := filepath.Join(t.TempDir(), "kim.log")
+	log := testutil.TestLogger()
+
+	var err error
+	captureStdout(func() {
+		_, err = executeCommand(NewRootCmd(testutil.TestConfig(), log), "--quiet", "--log-file", path, "profile", "list")
+	})
+	if err != nil {
+		t.Fatalf("profile list failed: %v", err)
+	}
+
+	log.Info("quiet info message")
+	log.Error("quiet error message")
+	log.Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if strings.Contains(string(data), "quiet info message") {
+		t.Error("Expected --quiet to suppress info logs")
+	}
+	if !strings.Contains(string(data), "quiet error message") {
+		t.Error("Expected --quiet to keep error logs")
+	}
+
+	if _, err := executeCommand(NewRootCmd(testutil.TestConfig(), log), "--quiet", "--debug", "profile", "list"); err == nil {
+		t.Error("Expected an error when --quiet and --debug are combined")
+	}
+}
+
+func TestYesFlagSkipsPrompts(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Cleanup(func() { assumeYes = false })
+
+	// A prompt would block on this stdin, which is never written to
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer w.Close()
+	oldStdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() { os.Stdin = oldStdin })
+
+	log := testutil.TestLogger()
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("foo", 1, 1)
+	useMockClient(t, mock)
+
+	done := make(chan error, 1)
+	go func() {
+		captureStdout(func() {
+			_, err := executeCommand(NewRootCmd(testutil.TestConfig(), log), "--yes", "topic", "delete", "foo")
+			done <- err
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("topic delete failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("topic delete --yes blocked on a confirmation prompt")
+	}
+
+	if _, exists := mock.MockTopic("foo"); exists {
+		t.Error("Expected topic foo to be deleted")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" yes \r\n", true},
+		{"y", true}, // no trailing newline
+		{"n\n", false},
+		{"\n", false},
+		{"yep\n", false},
+		{"", false}, // end of input
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirm(&out, strings.NewReader(tt.input), "Continue? (y/N): "); got != tt.want {
+			t.Errorf("confirm(%q) = %t, want %t", tt.input, got, tt.want)
+		}
+		if !strings.HasPrefix(out.String(), "Continue? (y/N): ") {
+			t.Errorf("Expected the prompt to be written, got %q", out.String())
+		}
+	}
+}
+
+func TestTopicDeletePromptCancelled(t *testing.T) {
+	log := testutil.TestLogger()
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("foo", 1, 1)
+	useMockClient(t, mock)
+
+	cmd := NewTopicCmd(testutil.TestConfig(), log)
+	cmd.SetIn(strings.NewReader("n\n"))
+
+	var (
+		cmdOutput string
+		err       error
+	)
+	output := captureStdout(func() {
+		cmdOutput, err = executeCommand(cmd, "delete", "foo")
+	})
+	if err != nil {
+		t.Fatalf("topic delete failed: %v", err)
+	}
+	if !strings.Contains(cmdOutput, "Are you sure you want to delete topic 'foo'?") {
+		t.Errorf("Expected a confirmation prompt, got %q", cmdOutput)
+	}
+	if !strings.Contains(output, "Topic deletion cancelled") {
+		t.Errorf("Expected the deletion to be cancelled, got %q", output)
+	}
+	if _, exists := mock.MockTopic("foo"); !exists {
+		t.Error("Topic foo should not be deleted when the prompt is answered with n")
+	}
+}
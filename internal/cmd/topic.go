@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
 	"strings"
 
-	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/config"
 	"github.com/nipunap/kim/internal/logger"
 	"github.com/nipunap/kim/internal/manager"
@@ -13,6 +16,7 @@ import (
 	"github.com/nipunap/kim/pkg/types"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // NewTopicCmd creates the topic command
@@ -25,8 +29,13 @@ func NewTopicCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 
 	cmd.AddCommand(NewTopicListCmd(cfg, log))
 	cmd.AddCommand(NewTopicDescribeCmd(cfg, log))
+	cmd.AddCommand(NewTopicConfigCmd(cfg, log))
 	cmd.AddCommand(NewTopicCreateCmd(cfg, log))
 	cmd.AddCommand(NewTopicDeleteCmd(cfg, log))
+	cmd.AddCommand(NewTopicTruncateCmd(cfg, log))
+	cmd.AddCommand(NewTopicCopyConfigCmd(cfg, log))
+	cmd.AddCommand(NewTopicDiffCmd(cfg, log))
+	cmd.AddCommand(NewTopicExistsCmd(cfg, log))
 
 	return cmd
 }
@@ -34,19 +43,58 @@ func NewTopicCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 // NewTopicListCmd creates the topic list command
 func NewTopicListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	var (
-		pattern  string
-		page     int
-		pageSize int
-		sortBy   string
-		order    string
-		format   string
+		pattern       string
+		exclude       []string
+		page          int
+		pageSize      int
+		sortBy        string
+		order         string
+		format        string
+		tmpl          string
+		minPartitions int32
+		maxPartitions int32
+		withSize      bool
+		all           bool
+		internalOnly  bool
+		watch         watchFlags
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List Kafka topics",
-		Long:  "List all Kafka topics with optional filtering and pagination.",
+		Long: `List all Kafka topics with optional filtering and pagination.
+
+Internal topics such as __consumer_offsets are hidden unless --all or
+--internal-only is given.
+
+Use --sort-by size or --sort-by messages with --with-size to find the biggest
+topics. Every matching topic is sized before the page is taken, which is slower
+on large clusters.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("format") {
+				format = defaultFormat(cfg)
+			}
+			if !cmd.Flags().Changed("page-size") {
+				pageSize = defaultPageSize(cfg)
+			}
+
+			if all && internalOnly {
+				return fmt.Errorf("--all and --internal-only cannot be used together")
+			}
+			internal := types.InternalExclude
+			if all {
+				internal = types.InternalInclude
+			} else if internalOnly {
+				internal = types.InternalOnly
+			}
+
+			if minPartitions < 0 || maxPartitions < 0 {
+				return fmt.Errorf("--min-partitions and --max-partitions cannot be negative")
+			}
+			if minPartitions > 0 && maxPartitions > 0 && minPartitions > maxPartitions {
+				return fmt.Errorf("--min-partitions (%d) cannot be greater than --max-partitions (%d)", minPartitions, maxPartitions)
+			}
+
 			// Get active profile
 			profile, err := cfg.GetActiveProfile()
 			if err != nil {
@@ -54,7 +102,7 @@ func NewTopicListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -66,46 +114,138 @@ func NewTopicListCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 
 			// List topics
 			opts := &types.ListOptions{
-				Page:     page,
-				PageSize: pageSize,
-				Pattern:  pattern,
-				SortBy:   sortBy,
-				Order:    order,
+				Page:          page,
+				PageSize:      pageSize,
+				Pattern:       pattern,
+				Exclude:       exclude,
+				SortBy:        sortBy,
+				Order:         order,
+				MinPartitions: minPartitions,
+				MaxPartitions: maxPartitions,
+				WithSize:      withSize,
+				Internal:      internal,
 			}
 
-			topicList, err := topicManager.ListTopics(context.Background(), opts)
-			if err != nil {
-				return fmt.Errorf("failed to list topics: %w", err)
-			}
+			displayOpts := newDisplayOptions(format, tmpl)
 
-			// Display results
-			displayOpts := &types.DisplayOptions{
-				Format: format,
-			}
-
-			return ui.DisplayTopicList(topicList, displayOpts)
+			return watch.run(cfg, func(ctx context.Context) error {
+				topicList, err := topicManager.ListTopics(ctx, opts)
+				if err != nil {
+					return fmt.Errorf("failed to list topics: %w", err)
+				}
+				return ui.DisplayTopicList(topicList, displayOpts)
+			})
 		},
 	}
 
 	cmd.Flags().StringVar(&pattern, "pattern", "", "filter topics by pattern (supports wildcards)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "hide topics matching this pattern (repeatable)")
+	cmd.Flags().Int32Var(&minPartitions, "min-partitions", 0, "only list topics with at least this many partitions")
+	cmd.Flags().Int32Var(&maxPartitions, "max-partitions", 0, "only list topics with at most this many partitions")
+	cmd.Flags().BoolVar(&withSize, "with-size", false, "estimate the message count and size of each topic (slower)")
+	cmd.Flags().BoolVar(&all, "all", false, "include internal topics")
+	cmd.Flags().BoolVar(&internalOnly, "internal-only", false, "only list internal topics")
+	watch.register(cmd)
 	cmd.Flags().IntVar(&page, "page", 1, "page number")
 	cmd.Flags().IntVar(&pageSize, "page-size", 20, "number of topics per page")
-	cmd.Flags().StringVar(&sortBy, "sort-by", "name", "sort by field (name, partitions, replication_factor)")
+	cmd.Flags().StringVar(&sortBy, "sort-by", "name", "sort by field (name, partitions, replication_factor, size, messages)")
 	cmd.Flags().StringVar(&order, "order", "asc", "sort order (asc, desc)")
-	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml)")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
 
 	return cmd
 }
 
 // NewTopicDescribeCmd creates the topic describe command
 func NewTopicDescribeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
-	var format string
+	var (
+		format      string
+		tmpl        string
+		withOffsets bool
+	)
 
 	cmd := &cobra.Command{
-		Use:   "describe TOPIC_NAME",
-		Short: "Describe a Kafka topic",
-		Long:  "Show detailed information about a specific Kafka topic including configuration and partition details.",
-		Args:  cobra.ExactArgs(1),
+		Use:   "describe TOPIC_NAME...",
+		Short: "Describe Kafka topics",
+		Long: `Show detailed information about Kafka topics including configuration and partition details.
+
+Several topics are described concurrently. A failure to describe one topic does not stop
+the others; the command fails at the end if any topic could not be described.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeTopicNames(cfg, log),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create topic manager
+			topicManager := manager.NewTopicManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			describeOpts := &types.DescribeTopicOptions{WithOffsets: withOffsets}
+			displayOpts := newDisplayOptions(format, tmpl)
+
+			// Describe a single topic
+			if len(args) == 1 {
+				topicDetails, err := topicManager.DescribeTopic(ctx, args[0], describeOpts)
+				if err != nil {
+					return fmt.Errorf("failed to describe topic: %w", err)
+				}
+
+				return ui.DisplayTopicDetails(topicDetails, displayOpts)
+			}
+
+			// Describe several topics concurrently
+			results := topicManager.DescribeTopics(ctx, args, describeOpts)
+			if err := ui.DisplayDescribeTopicResults(results, displayOpts); err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, result := range results {
+				if result.Error != "" {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("failed to describe %d of %d topics", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&withOffsets, "with-offsets", false, "show the earliest and latest offset of each partition")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// NewTopicConfigCmd creates the topic config command
+func NewTopicConfigCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		format string
+		tmpl   string
+	)
+
+	cmd := &cobra.Command{
+		Use:               "config TOPIC_NAME",
+		Short:             "Show topic configuration",
+		Long:              "Show the configuration of a Kafka topic with raw and human-readable values and whether each value is a default or has been overridden.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTopicNames(cfg, log),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			topicName := args[0]
 
@@ -116,7 +256,7 @@ func NewTopicDescribeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -126,22 +266,24 @@ func NewTopicDescribeCmd(cfg *config.Config, log *logger.Logger) *cobra.Command
 			// Create topic manager
 			topicManager := manager.NewTopicManager(kafkaClient, log)
 
-			// Describe topic
-			topicDetails, err := topicManager.DescribeTopic(context.Background(), topicName)
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Describe topic config
+			topicConfig, err := topicManager.DescribeTopicConfig(ctx, topicName)
 			if err != nil {
-				return fmt.Errorf("failed to describe topic: %w", err)
+				return fmt.Errorf("failed to describe topic config: %w", err)
 			}
 
 			// Display results
-			displayOpts := &types.DisplayOptions{
-				Format: format,
-			}
+			displayOpts := newDisplayOptions(format, tmpl)
 
-			return ui.DisplayTopicDetails(topicDetails, displayOpts)
+			return ui.DisplayTopicConfig(topicConfig, displayOpts)
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml)")
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
 
 	return cmd
 }
@@ -152,16 +294,60 @@ func NewTopicCreateCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 		partitions        int32
 		replicationFactor int16
 		configs           []string
+		fromFile          string
+		dryRun            bool
 	)
 
 	cmd := &cobra.Command{
-		Use:   "create TOPIC_NAME",
+		Use:   "create [TOPIC_NAME]",
 		Short: "Create a Kafka topic",
-		Long:  "Create a new Kafka topic with specified configuration.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Create a new Kafka topic with specified configuration.
+
+With --from-file several topics are created from a YAML file, e.g.
+
+  topics:
+    - name: orders
+      partitions: 6
+      replication_factor: 3
+      configs:
+        retention.ms: "604800000"
+    - name: payments
+      partitions: 3
+      replication_factor: 3
+
+Each topic is created independently and a summary is printed at the end. Use
+--dry-run to validate the file without creating anything.
+
+For a single topic, --dry-run asks the broker to validate the partitions,
+replication factor and configs without creating the topic.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromFile != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("TOPIC_NAME cannot be used with --from-file")
+				}
+				for _, flag := range []string{"partitions", "replication-factor", "config"} {
+					if cmd.Flags().Changed(flag) {
+						return fmt.Errorf("--partitions, --replication-factor and --config cannot be used with --from-file")
+					}
+				}
+				return createTopicsFromFile(cfg, log, fromFile, dryRun)
+			}
+
+			if len(args) == 0 {
+				return fmt.Errorf("topic name is required (or use --from-file)")
+			}
+
 			topicName := args[0]
 
+			// Validate locally for a clearer error than the broker's
+			if partitions < 1 {
+				return fmt.Errorf("partitions must be >= 1, got %d", partitions)
+			}
+			if replicationFactor < 1 {
+				return fmt.Errorf("replication-factor must be >= 1, got %d", replicationFactor)
+			}
+
 			// Parse config entries
 			configMap := make(map[string]string)
 			for _, config := range configs {
@@ -169,6 +355,9 @@ func NewTopicCreateCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 				if len(parts) != 2 {
 					return fmt.Errorf("invalid config format: %s (expected key=value)", config)
 				}
+				if strings.TrimSpace(parts[0]) == "" {
+					return fmt.Errorf("invalid config %s: key cannot be empty", config)
+				}
 				configMap[parts[0]] = parts[1]
 			}
 
@@ -179,7 +368,7 @@ func NewTopicCreateCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -189,18 +378,26 @@ func NewTopicCreateCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			// Create topic manager
 			topicManager := manager.NewTopicManager(kafkaClient, log)
 
+			ctx, cancel := commandContext()
+			defer cancel()
+
 			// Create topic
 			req := &types.CreateTopicRequest{
 				Name:              topicName,
 				Partitions:        partitions,
 				ReplicationFactor: replicationFactor,
 				Configs:           configMap,
+				ValidateOnly:      dryRun,
 			}
 
-			if err := topicManager.CreateTopic(context.Background(), req); err != nil {
+			if err := topicManager.CreateTopic(ctx, req); err != nil {
 				return fmt.Errorf("failed to create topic: %w", err)
 			}
 
+			if dryRun {
+				fmt.Printf("Topic '%s' validation passed (dry run, nothing created)\n", topicName)
+				return nil
+			}
 			fmt.Printf("Topic '%s' created successfully\n", topicName)
 			return nil
 		},
@@ -209,31 +406,173 @@ func NewTopicCreateCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 	cmd.Flags().Int32Var(&partitions, "partitions", 1, "number of partitions")
 	cmd.Flags().Int16Var(&replicationFactor, "replication-factor", 1, "replication factor")
 	cmd.Flags().StringSliceVar(&configs, "config", nil, "topic configuration (key=value)")
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "create the topics listed in a YAML file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "validate without creating topics")
 
 	return cmd
 }
 
+// topicSpecFile is the layout of a --from-file topics file
+type topicSpecFile struct {
+	Topics []struct {
+		Name              string            `yaml:"name"`
+		Partitions        int32             `yaml:"partitions"`
+		ReplicationFactor int16             `yaml:"replication_factor"`
+		Configs           map[string]string `yaml:"configs"`
+	} `yaml:"topics"`
+}
+
+// parseTopicSpecFile parses a topics file into create requests. Unknown fields
+// are rejected so that typos are not silently ignored.
+func parseTopicSpecFile(r io.Reader) ([]types.CreateTopicRequest, error) {
+	var spec topicSpecFile
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	reqs := make([]types.CreateTopicRequest, 0, len(spec.Topics))
+	for _, topic := range spec.Topics {
+		reqs = append(reqs, types.CreateTopicRequest{
+			Name:              topic.Name,
+			Partitions:        topic.Partitions,
+			ReplicationFactor: topic.ReplicationFactor,
+			Configs:           topic.Configs,
+		})
+	}
+	return reqs, nil
+}
+
+// validateCreateTopicRequest checks a create request before it is sent to the cluster
+func validateCreateTopicRequest(req *types.CreateTopicRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if req.Partitions <= 0 {
+		return fmt.Errorf("partitions must be a positive number, got %d", req.Partitions)
+	}
+	if req.ReplicationFactor <= 0 {
+		return fmt.Errorf("replication_factor must be a positive number, got %d", req.ReplicationFactor)
+	}
+	return nil
+}
+
+// createTopicsFromFile creates the topics listed in a topics file. A failing topic
+// does not stop the others; every result is reported followed by a summary. With
+// dryRun the entries are only validated and the cluster is not contacted.
+func createTopicsFromFile(cfg *config.Config, log *logger.Logger, path string, dryRun bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open topics file: %w", err)
+	}
+	defer file.Close()
+
+	reqs, err := parseTopicSpecFile(file)
+	if err != nil {
+		return fmt.Errorf("invalid topics file: %w", err)
+	}
+	if len(reqs) == 0 {
+		return fmt.Errorf("no topics found in %s", path)
+	}
+
+	var topicManager *manager.TopicManager
+	if !dryRun {
+		// Get active profile
+		profile, err := cfg.GetActiveProfile()
+		if err != nil {
+			return fmt.Errorf("no active profile: %w", err)
+		}
+
+		// Create client
+		clientManager := newClientManager(log)
+		kafkaClient, err := clientManager.GetClient(profile)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+		defer kafkaClient.Close()
+
+		topicManager = manager.NewTopicManager(kafkaClient, log)
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	seen := make(map[string]bool, len(reqs))
+	failed := 0
+	for i := range reqs {
+		req := &reqs[i]
+
+		name := req.Name
+		if name == "" {
+			name = fmt.Sprintf("entry %d", i+1)
+		}
+
+		err := validateCreateTopicRequest(req)
+		if err == nil && seen[req.Name] {
+			err = fmt.Errorf("duplicate topic name")
+		}
+		seen[req.Name] = true
+
+		if err == nil && !dryRun {
+			err = topicManager.CreateTopic(ctx, req)
+		}
+
+		switch {
+		case err != nil:
+			failed++
+			fmt.Printf("FAILED  %s: %v\n", name, err)
+		case dryRun:
+			fmt.Printf("VALID   %s (%d partitions, replication factor %d)\n", name, req.Partitions, req.ReplicationFactor)
+		default:
+			fmt.Printf("CREATED %s (%d partitions, replication factor %d)\n", name, req.Partitions, req.ReplicationFactor)
+		}
+	}
+
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("%d of %d topics valid (dry run, nothing created)\n", len(reqs)-failed, len(reqs))
+	} else {
+		fmt.Printf("Created %d of %d topics\n", len(reqs)-failed, len(reqs))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d topics failed", failed, len(reqs))
+	}
+	return nil
+}
+
 // NewTopicDeleteCmd creates the topic delete command
 func NewTopicDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
-	var force bool
+	var (
+		force    bool
+		pattern  string
+		allowAll bool
+	)
 
 	cmd := &cobra.Command{
-		Use:   "delete TOPIC_NAME",
-		Short: "Delete a Kafka topic",
-		Long:  "Delete an existing Kafka topic. This operation is irreversible.",
-		Args:  cobra.ExactArgs(1),
+		Use:   "delete [TOPIC_NAME]",
+		Short: "Delete Kafka topics",
+		Long: `Delete an existing Kafka topic. This operation is irreversible.
+
+With --pattern every non-internal topic matching a glob pattern (e.g. 'tmp-*') is deleted.
+The matching topics are listed before asking for confirmation, and a failure to delete
+one topic does not stop the others. A pattern of only '*' needs --allow-all.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTopicNames(cfg, log),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			topicName := args[0]
+			if (len(args) == 1) == (pattern != "") {
+				return fmt.Errorf("specify either a topic name or --pattern")
+			}
+			if pattern != "" && strings.Trim(pattern, "*") == "" && !allowAll {
+				return fmt.Errorf("pattern %q matches every topic (use --allow-all to delete them all)", pattern)
+			}
 
-			// Confirm deletion unless force flag is used
-			if !force {
-				fmt.Printf("Are you sure you want to delete topic '%s'? This operation is irreversible. (y/N): ", topicName)
-				var response string
-				fmt.Scanln(&response)
-				if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-					fmt.Println("Topic deletion cancelled")
-					return nil
-				}
+			// Confirm deletion of a single topic unless --force or --yes is used
+			if pattern == "" && !confirmAction(cmd, force, fmt.Sprintf("Are you sure you want to delete topic '%s'? This operation is irreversible. (y/N): ", args[0])) {
+				fmt.Println("Topic deletion cancelled")
+				return nil
 			}
 
 			// Get active profile
@@ -243,7 +582,7 @@ func NewTopicDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			}
 
 			// Create client
-			clientManager := client.NewManager(log)
+			clientManager := newClientManager(log)
 			kafkaClient, err := clientManager.GetClient(profile)
 			if err != nil {
 				return fmt.Errorf("failed to create client: %w", err)
@@ -253,17 +592,321 @@ func NewTopicDeleteCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
 			// Create topic manager
 			topicManager := manager.NewTopicManager(kafkaClient, log)
 
+			ctx, cancel := commandContext()
+			defer cancel()
+
 			// Delete topic
-			if err := topicManager.DeleteTopic(context.Background(), topicName); err != nil {
-				return fmt.Errorf("failed to delete topic: %w", err)
+			if pattern == "" {
+				if err := topicManager.DeleteTopic(ctx, args[0]); err != nil {
+					return fmt.Errorf("failed to delete topic: %w", err)
+				}
+
+				fmt.Printf("Topic '%s' deleted successfully\n", args[0])
+				return nil
+			}
+
+			names, err := topicManager.MatchTopics(ctx, pattern)
+			if err != nil {
+				return fmt.Errorf("failed to match topics: %w", err)
+			}
+			if len(names) == 0 {
+				fmt.Printf("No topics match pattern '%s'\n", pattern)
+				return nil
+			}
+
+			fmt.Printf("Topics matching pattern '%s':\n", pattern)
+			for _, name := range names {
+				fmt.Printf("  %s\n", name)
+			}
+
+			if !confirmAction(cmd, force, fmt.Sprintf("Are you sure you want to delete these %d topics? This operation is irreversible. (y/N): ", len(names))) {
+				fmt.Println("Topic deletion cancelled")
+				return nil
+			}
+
+			// Delete the matching topics and summarize the results
+			results := topicManager.DeleteTopics(ctx, names)
+
+			failed := 0
+			fmt.Printf("%-40s %s\n", "TOPIC", "RESULT")
+			fmt.Println(strings.Repeat("-", 80))
+			for _, result := range results {
+				if result.Error != "" {
+					failed++
+					fmt.Printf("%-40s failed: %s\n", result.Topic, result.Error)
+				} else {
+					fmt.Printf("%-40s deleted\n", result.Topic)
+				}
+			}
+			fmt.Printf("\nDeleted %d of %d topics\n", len(results)-failed, len(results))
+
+			if failed > 0 {
+				return fmt.Errorf("failed to delete %d of %d topics", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "skip confirmation prompt")
+	cmd.Flags().StringVar(&pattern, "pattern", "", "delete every topic matching this glob pattern (e.g. 'tmp-*')")
+	cmd.Flags().BoolVar(&allowAll, "allow-all", false, "allow a --pattern that matches every topic")
+
+	return cmd
+}
+
+// NewTopicTruncateCmd creates the topic truncate command
+func NewTopicTruncateCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		partitions []int32
+		force      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "truncate TOPIC_NAME",
+		Aliases: []string{"empty"},
+		Short:   "Delete all messages from a Kafka topic",
+		Long: `Delete every message currently in a topic, or in the partitions given with
+--partition, without deleting the topic itself. This operation is irreversible.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTopicNames(cfg, log),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topicName := args[0]
+
+			// Confirm truncation unless --force or --yes is used
+			if !confirmAction(cmd, force, fmt.Sprintf("Are you sure you want to delete all messages from topic '%s'? This operation is irreversible. (y/N): ", topicName)) {
+				fmt.Println("Topic truncation cancelled")
+				return nil
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
 			}
 
-			fmt.Printf("Topic '%s' deleted successfully\n", topicName)
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create topic manager
+			topicManager := manager.NewTopicManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Truncate topic
+			offsets, err := topicManager.TruncateTopic(ctx, topicName, partitions)
+			if err != nil {
+				return fmt.Errorf("failed to truncate topic: %w", err)
+			}
+
+			truncated := make([]int32, 0, len(offsets))
+			for partition := range offsets {
+				truncated = append(truncated, partition)
+			}
+			sort.Slice(truncated, func(i, j int) bool { return truncated[i] < truncated[j] })
+
+			fmt.Printf("Topic '%s' truncated\n", topicName)
+			for _, partition := range truncated {
+				fmt.Printf("  partition %d: messages before offset %d deleted\n", partition, offsets[partition])
+			}
 			return nil
 		},
 	}
 
+	cmd.Flags().Int32SliceVar(&partitions, "partition", nil, "only truncate these partitions (default all)")
 	cmd.Flags().BoolVar(&force, "force", false, "skip confirmation prompt")
 
 	return cmd
 }
+
+// NewTopicCopyConfigCmd creates the topic copy-config command
+func NewTopicCopyConfigCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var copyLayout bool
+
+	cmd := &cobra.Command{
+		Use:   "copy-config SOURCE_TOPIC DESTINATION_TOPIC",
+		Short: "Copy the configuration of a topic to another topic",
+		Long: `Copy the config overrides of a topic to another topic. A missing destination
+topic is created with the broker's default partition count and replication
+factor, or with those of the source topic when --copy-layout is given. Default,
+broker-level and read-only values are not copied.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeTopicNames(cfg, log),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, dst := args[0], args[1]
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create topic manager
+			topicManager := manager.NewTopicManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			// Copy config
+			copied, err := topicManager.CopyConfig(ctx, src, dst, copyLayout)
+			if err != nil {
+				return fmt.Errorf("failed to copy topic config: %w", err)
+			}
+
+			names := make([]string, 0, len(copied))
+			for name := range copied {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			fmt.Printf("Copied %d config(s) from '%s' to '%s'\n", len(copied), src, dst)
+			for _, name := range names {
+				fmt.Printf("  %s=%s\n", name, copied[name])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&copyLayout, "copy-layout", false, "create the destination topic with the source's partition count and replication factor")
+
+	return cmd
+}
+
+// NewTopicDiffCmd creates the topic diff command
+func NewTopicDiffCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var (
+		format string
+		tmpl   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff TOPIC_A TOPIC_B",
+		Short: "Show the differences between two topics",
+		Long: `Compare the partition count, replication factor and configuration of two topics
+and print the keys whose values differ in unified diff style, with the first
+topic's values prefixed by '-' and the second topic's by '+'. Useful for
+checking that a topic matches its counterpart in another environment.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeTopicNames(cfg, log),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create topic manager
+			topicManager := manager.NewTopicManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			diff, err := topicManager.DiffTopics(ctx, args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("failed to diff topics: %w", err)
+			}
+
+			// Display results
+			displayOpts := newDisplayOptions(format, tmpl)
+
+			return ui.DisplayTopicDiff(diff, displayOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml, go-template)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// NewTopicExistsCmd creates the topic exists command
+func NewTopicExistsCmd(cfg *config.Config, log *logger.Logger) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "exists TOPIC_NAME",
+		Short: "Check whether a topic exists",
+		Long: `Check whether a topic exists. The command exits with status 0 if the topic exists
+and 1 if it does not, printing nothing unless --format json is given, e.g.
+
+  if kim topic exists orders; then ...; fi`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTopicNames(cfg, log),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topicName := args[0]
+
+			if format != "" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be json)", format)
+			}
+
+			// Get active profile
+			profile, err := cfg.GetActiveProfile()
+			if err != nil {
+				return fmt.Errorf("no active profile: %w", err)
+			}
+
+			// Create client
+			clientManager := newClientManager(log)
+			kafkaClient, err := clientManager.GetClient(profile)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			defer kafkaClient.Close()
+
+			// Create topic manager
+			topicManager := manager.NewTopicManager(kafkaClient, log)
+
+			ctx, cancel := commandContext()
+			defer cancel()
+
+			exists, err := topicManager.Exists(ctx, topicName)
+			if err != nil {
+				return fmt.Errorf("failed to check topic: %w", err)
+			}
+
+			if format == "json" {
+				data, err := json.Marshal(struct {
+					Topic  string `json:"topic"`
+					Exists bool   `json:"exists"`
+				}{topicName, exists})
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+			}
+
+			if !exists {
+				// The exit status is the answer, so print nothing else
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return fmt.Errorf("topic %s does not exist", topicName)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "output format (json); prints nothing by default")
+
+	return cmd
+}
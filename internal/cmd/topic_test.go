@@ -0,0 +1,648 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+func TestTopicListTemplateFlag(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockTopic("payments", 1, 1)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "list", "--template", "{{range .Topics}}{{.Name}}\n{{end}}")
+	})
+	if err != nil {
+		t.Fatalf("topic list failed: %v", err)
+	}
+	if output != "orders\npayments\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+}
+
+func TestNoColorFlag(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	captureStdout(func() {
+		executeCommand(NewRootCmd(cfg, log), "topic", "list")
+	})
+	if colorScheme != "default" {
+		t.Errorf("Expected the color scheme from settings, got %q", colorScheme)
+	}
+
+	captureStdout(func() {
+		executeCommand(NewRootCmd(cfg, log), "--no-color", "topic", "list")
+	})
+	if colorScheme != "none" {
+		t.Errorf("--no-color should disable colors, got %q", colorScheme)
+	}
+}
+
+func TestTopicConfigCommand(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockConfig(sarama.TopicResource, "orders",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "604800000", Source: sarama.SourceTopic},
+		sarama.ConfigEntry{Name: "cleanup.policy", Value: "delete", Default: true, Source: sarama.SourceDefault},
+	)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "config", "orders")
+	})
+	if err != nil {
+		t.Fatalf("topic config failed: %v", err)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "retention.ms" {
+			continue
+		}
+		if strings.Join(fields[1:], " ") != "604800000 7 days 0 hours overridden" {
+			t.Errorf("Unexpected retention.ms row: %q", line)
+		}
+		return
+	}
+	t.Errorf("No retention.ms row in output:\n%s", output)
+}
+
+func TestTopicConfigCommandJSON(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockConfig(sarama.TopicResource, "orders",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "604800000", Source: sarama.SourceTopic},
+	)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "config", "orders", "--format", "json")
+	})
+	if err != nil {
+		t.Fatalf("topic config failed: %v", err)
+	}
+	if !strings.Contains(output, `"formatted": "7 days 0 hours"`) {
+		t.Errorf("Expected the humanized value in JSON output, got:\n%s", output)
+	}
+}
+
+const topicSpecYAML = `topics:
+  - name: orders
+    partitions: 3
+    replication_factor: 1
+    configs:
+      retention.ms: 604800000
+  - name: broken
+    partitions: 0
+    replication_factor: 1
+  - name: payments
+    partitions: 2
+    replication_factor: 1
+`
+
+// writeTopicSpec writes a topics file for --from-file
+func writeTopicSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "topics.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write topics file: %v", err)
+	}
+	return path
+}
+
+func TestTopicCreateFromFile(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	path := writeTopicSpec(t, topicSpecYAML)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "create", "--from-file", path)
+	})
+	if err == nil {
+		t.Error("Expected an error reporting the failed topic")
+	}
+
+	for _, name := range []string{"orders", "payments"} {
+		if _, exists := mock.MockTopic(name); !exists {
+			t.Errorf("Topic %s should have been created", name)
+		}
+	}
+	if meta, _ := mock.MockTopic("orders"); meta != nil && len(meta.Partitions) != 3 {
+		t.Errorf("Expected 3 partitions for orders, got %d", len(meta.Partitions))
+	}
+	if _, exists := mock.MockTopic("broken"); exists {
+		t.Error("Invalid topic should not be created")
+	}
+
+	if !strings.Contains(output, "FAILED  broken: partitions must be a positive number") {
+		t.Errorf("Expected the failure to be reported, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Created 2 of 3 topics") {
+		t.Errorf("Expected a summary, got:\n%s", output)
+	}
+}
+
+func TestTopicCreateFromFileDryRun(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	path := writeTopicSpec(t, topicSpecYAML)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "create", "--from-file", path, "--dry-run")
+	})
+	if err == nil {
+		t.Error("Dry run should report invalid entries as an error")
+	}
+	if _, exists := mock.MockTopic("orders"); exists {
+		t.Error("Dry run should not create topics")
+	}
+	if !strings.Contains(output, "VALID   orders") || !strings.Contains(output, "2 of 3 topics valid") {
+		t.Errorf("Unexpected dry run output:\n%s", output)
+	}
+}
+
+func TestTopicCreateDryRun(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "create", "orders", "--partitions", "6", "--dry-run")
+	})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+
+	calls := mock.CreateTopicCalls()
+	if len(calls) != 1 || !calls[0].ValidateOnly {
+		t.Fatalf("Expected a single validate-only CreateTopic call, got %+v", calls)
+	}
+	if calls[0].Detail.NumPartitions != 6 {
+		t.Errorf("Expected 6 partitions to be validated, got %d", calls[0].Detail.NumPartitions)
+	}
+	if _, exists := mock.MockTopic("orders"); exists {
+		t.Error("Dry run should not create the topic")
+	}
+	if !strings.Contains(output, "Topic 'orders' validation passed") {
+		t.Errorf("Expected validation passed, got:\n%s", output)
+	}
+}
+
+func TestTopicCreateValidation(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	useMockClient(t, mock)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"zero partitions", []string{"--partitions", "0"}, "partitions must be >= 1"},
+		{"zero replication factor", []string{"--replication-factor", "0"}, "replication-factor must be >= 1"},
+		{"config without value", []string{"--config", "retention.ms"}, "invalid config format"},
+		{"config without key", []string{"--config", "=604800000"}, "key cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"create", "orders"}, tt.args...)
+			_, err := executeCommand(NewTopicCmd(cfg, log), args...)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if calls := mock.CreateTopicCalls(); len(calls) != 0 {
+		t.Errorf("Invalid requests should not reach the cluster, got %+v", calls)
+	}
+}
+
+func TestParseTopicSpecFile(t *testing.T) {
+	reqs, err := parseTopicSpecFile(strings.NewReader(topicSpecYAML))
+	if err != nil {
+		t.Fatalf("parseTopicSpecFile failed: %v", err)
+	}
+	if len(reqs) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(reqs))
+	}
+	if reqs[0].Configs["retention.ms"] != "604800000" {
+		t.Errorf("Expected retention.ms config, got %v", reqs[0].Configs)
+	}
+
+	// Unknown fields are rejected
+	if _, err := parseTopicSpecFile(strings.NewReader("topics:\n  - name: a\n    partitons: 3\n")); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
+
+func TestTopicListInternalTopics(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockTopic("__consumer_offsets", 50, 1)
+	if meta, exists := mock.MockTopic("__consumer_offsets"); exists {
+		meta.IsInternal = true
+	}
+	useMockClient(t, mock)
+
+	list := func(args ...string) string {
+		t.Helper()
+		var err error
+		args = append([]string{"list", "--template", "{{range .Topics}}{{.Name}}\n{{end}}"}, args...)
+		output := captureStdout(func() {
+			_, err = executeCommand(NewTopicCmd(cfg, log), args...)
+		})
+		if err != nil {
+			t.Fatalf("topic list %v failed: %v", args, err)
+		}
+		return output
+	}
+
+	if output := list(); output != "orders\n" {
+		t.Errorf("Expected internal topics to be hidden by default, got %q", output)
+	}
+	if output := list("--all"); output != "__consumer_offsets\norders\n" {
+		t.Errorf("Expected --all to include internal topics, got %q", output)
+	}
+	if output := list("--internal-only"); output != "__consumer_offsets\n" {
+		t.Errorf("Expected --internal-only to list only internal topics, got %q", output)
+	}
+
+	if _, err := executeCommand(NewTopicCmd(cfg, log), "list", "--all", "--internal-only"); err == nil {
+		t.Error("Expected an error when --all and --internal-only are combined")
+	}
+}
+
+func TestTopicListPartitionRangeValidation(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	if _, err := executeCommand(NewTopicCmd(cfg, log), "list", "--min-partitions", "10", "--max-partitions", "2"); err == nil {
+		t.Error("Expected an error when --min-partitions is greater than --max-partitions")
+	}
+}
+
+func TestTopicDescribeWithOffsets(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 2, 1)
+
+	// Partition 0 holds offsets 40-100 after retention, partition 1 is empty
+	pc := mock.Consumer().AddMockPartition("orders", 0)
+	for i := 0; i < 100; i++ {
+		pc.SendMockMessage("", "value")
+	}
+	pc.SetLogStartOffset(40)
+	mock.Consumer().AddMockPartition("orders", 1)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "describe", "orders", "--with-offsets")
+	})
+	if err != nil {
+		t.Fatalf("Topic describe failed: %v", err)
+	}
+
+	if !strings.Contains(output, "EARLIEST") || !strings.Contains(output, "LATEST") {
+		t.Errorf("Expected offset columns, got:\n%s", output)
+	}
+
+	var found bool
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 8 && fields[0] == "0" {
+			found = true
+			if fields[5] != "40" || fields[6] != "100" || fields[7] != "60" {
+				t.Errorf("Expected offsets 40/100 and 60 messages for partition 0, got %v", fields[5:])
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Partition 0 row not found in:\n%s", output)
+	}
+
+	// Offsets are not fetched by default
+	output = captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "describe", "orders")
+	})
+	if err != nil {
+		t.Fatalf("Topic describe failed: %v", err)
+	}
+	if strings.Contains(output, "EARLIEST") {
+		t.Errorf("Expected no offset columns without --with-offsets, got:\n%s", output)
+	}
+}
+
+func TestTopicTruncate(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 2, 1)
+	pc := mock.Consumer().AddMockPartition("orders", 0)
+	for i := 0; i < 3; i++ {
+		pc.SendMockMessage("", "value")
+	}
+	mock.Consumer().AddMockPartition("orders", 1)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "truncate", "orders", "--partition", "0", "--force")
+	})
+	if err != nil {
+		t.Fatalf("topic truncate failed: %v", err)
+	}
+	if !strings.Contains(output, "partition 0: messages before offset 3 deleted") {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+
+	calls := mock.DeleteRecordsCalls()
+	if len(calls) != 1 || len(calls[0].Offsets) != 1 || calls[0].Offsets[0] != 3 {
+		t.Errorf("Expected partition 0 to be truncated to offset 3, got %+v", calls)
+	}
+}
+
+func TestTopicCopyConfig(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 3, 1)
+	mock.AddMockConfig(sarama.TopicResource, "orders",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "3600000", Source: sarama.SourceTopic})
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "copy-config", "orders", "orders-v2", "--copy-layout")
+	})
+	if err != nil {
+		t.Fatalf("topic copy-config failed: %v", err)
+	}
+	if !strings.Contains(output, "Copied 1 config(s) from 'orders' to 'orders-v2'") || !strings.Contains(output, "retention.ms=3600000") {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+
+	meta, exists := mock.MockTopic("orders-v2")
+	if !exists || len(meta.Partitions) != 3 {
+		t.Errorf("Expected orders-v2 to be created with 3 partitions, got %+v", meta)
+	}
+}
+
+func TestTopicDiff(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders-staging", 3, 1)
+	mock.AddMockConfig(sarama.TopicResource, "orders-staging",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "86400000", Source: sarama.SourceTopic})
+	mock.AddMockTopic("orders-prod", 6, 1)
+	mock.AddMockConfig(sarama.TopicResource, "orders-prod",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "604800000", Source: sarama.SourceTopic})
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "diff", "orders-staging", "orders-prod")
+	})
+	if err != nil {
+		t.Fatalf("topic diff failed: %v", err)
+	}
+	for _, want := range []string{
+		"--- orders-staging", "+++ orders-prod",
+		"-partitions=3", "+partitions=6",
+		"-retention.ms=86400000", "+retention.ms=604800000",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "replication_factor") {
+		t.Errorf("Expected the equal replication factor to be omitted, got:\n%s", output)
+	}
+
+	output = captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "diff", "orders-staging", "orders-staging")
+	})
+	if err != nil {
+		t.Fatalf("topic diff failed: %v", err)
+	}
+	if !strings.Contains(output, "are identical") {
+		t.Errorf("Expected identical topics to be reported, got:\n%s", output)
+	}
+}
+
+func TestTopicExists(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 3, 1)
+	useMockClient(t, mock)
+
+	var (
+		cmdOutput string
+		err       error
+	)
+	output := captureStdout(func() {
+		cmdOutput, err = executeCommand(NewTopicCmd(cfg, log), "exists", "orders")
+	})
+	if err != nil {
+		t.Errorf("topic exists should succeed for an existing topic: %v", err)
+	}
+	if output != "" || cmdOutput != "" {
+		t.Errorf("topic exists should print nothing by default, got %q and %q", output, cmdOutput)
+	}
+
+	output = captureStdout(func() {
+		cmdOutput, err = executeCommand(NewTopicCmd(cfg, log), "exists", "missing")
+	})
+	if err == nil {
+		t.Error("topic exists should fail for a missing topic")
+	}
+	if output != "" || cmdOutput != "" {
+		t.Errorf("topic exists should print nothing for a missing topic, got %q and %q", output, cmdOutput)
+	}
+
+	output = captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "exists", "missing", "--format", "json")
+	})
+	if err == nil {
+		t.Error("topic exists --format json should still fail for a missing topic")
+	}
+	if strings.TrimSpace(output) != `{"topic":"missing","exists":false}` {
+		t.Errorf("Unexpected JSON output: %q", output)
+	}
+}
+
+func TestTopicDescribeMultiple(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 3, 1)
+	mock.AddMockTopic("payments", 2, 1)
+	mock.AddMockTopic("shipments", 1, 1)
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "describe", "orders", "payments", "shipments", "--format", "json")
+	})
+	if err != nil {
+		t.Fatalf("Topic describe failed: %v", err)
+	}
+
+	var results []types.DescribeTopicResult
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for i, name := range []string{"orders", "payments", "shipments"} {
+		if results[i].Name != name || results[i].Details == nil {
+			t.Errorf("Result %d: expected details of %s, got %+v", i, name, results[i])
+		}
+	}
+
+	// A missing topic fails the command without hiding the others
+	output = captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "describe", "orders", "missing")
+	})
+	if err == nil {
+		t.Error("Describe should fail when a topic is missing")
+	}
+	if !strings.Contains(output, "Topic: orders") || !strings.Contains(output, "missing not found") {
+		t.Errorf("Expected orders and the missing topic error, got:\n%s", output)
+	}
+}
+
+func TestTopicDeletePattern(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	for _, name := range []string{"tmp-a", "tmp-b", "orders", "orders-tmp-c"} {
+		mock.AddMockTopic(name, 1, 1)
+	}
+	useMockClient(t, mock)
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewTopicCmd(cfg, log), "delete", "--pattern", "tmp-*", "--force")
+	})
+	if err != nil {
+		t.Fatalf("Topic delete --pattern failed: %v", err)
+	}
+	if !strings.Contains(output, "Deleted 2 of 2 topics") {
+		t.Errorf("Expected a summary of 2 deletions, got:\n%s", output)
+	}
+
+	// Only the matching topics are deleted
+	topics, err := mock.ListTopics()
+	if err != nil {
+		t.Fatalf("ListTopics failed: %v", err)
+	}
+	for _, name := range []string{"tmp-a", "tmp-b"} {
+		if _, exists := topics[name]; exists {
+			t.Errorf("Topic %s should have been deleted", name)
+		}
+	}
+	for _, name := range []string{"orders", "orders-tmp-c"} {
+		if _, exists := topics[name]; !exists {
+			t.Errorf("Topic %s should not have been deleted", name)
+		}
+	}
+}
+
+func TestTopicDeletePatternGuards(t *testing.T) {
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), log)
+	mock.AddMockTopic("orders", 1, 1)
+	useMockClient(t, mock)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"match everything", []string{"delete", "--pattern", "*", "--force"}},
+		{"name and pattern", []string{"delete", "orders", "--pattern", "ord*", "--force"}},
+		{"neither name nor pattern", []string{"delete", "--force"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := executeCommand(NewTopicCmd(cfg, log), tt.args...); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	if topics, _ := mock.ListTopics(); len(topics) != 1 {
+		t.Errorf("No topic should have been deleted, %d remain", len(topics))
+	}
+
+	// A declined confirmation deletes nothing
+	cmd := NewTopicCmd(cfg, log)
+	cmd.SetIn(strings.NewReader("n\n"))
+	captureStdout(func() {
+		_, err := executeCommand(cmd, "delete", "--pattern", "ord*")
+		if err != nil {
+			t.Errorf("Declined deletion should not fail: %v", err)
+		}
+	})
+	if topics, _ := mock.ListTopics(); len(topics) != 1 {
+		t.Error("Declined deletion should keep the topic")
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"runtime"
+	runtimedebug "runtime/debug"
+
+	"github.com/nipunap/kim/internal/client"
+	"github.com/nipunap/kim/internal/ui"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Build information, injected at build time with
+// -ldflags "-X github.com/nipunap/kim/internal/cmd.Version=..."
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// saramaModule is the module path of the Kafka client library
+const saramaModule = "github.com/IBM/sarama"
+
+// NewVersionCmd creates the version command
+func NewVersionCmd() *cobra.Command {
+	var (
+		format string
+		tmpl   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show version and build information",
+		Long: `Show the kim version, git commit and build date, along with the version of the
+sarama Kafka client and the Kafka protocol version used when a profile sets none.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := &types.VersionInfo{
+				Version:              Version,
+				Commit:               Commit,
+				BuildDate:            BuildDate,
+				GoVersion:            runtime.Version(),
+				SaramaVersion:        saramaVersion(),
+				KafkaProtocolVersion: client.DefaultKafkaVersion(),
+			}
+
+			return ui.DisplayVersionInfo(info, newDisplayOptions(format, tmpl))
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json, yaml)")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template used to render the output (implies --format go-template)")
+
+	return cmd
+}
+
+// saramaVersion returns the version of the sarama module linked into the binary
+func saramaVersion() string {
+	buildInfo, ok := runtimedebug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, dep := range buildInfo.Deps {
+		if dep.Path == saramaModule {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+)
+
+// setVersion sets the injected build information for the duration of a test
+func setVersion(t *testing.T, version, commit, buildDate string) {
+	t.Helper()
+
+	oldVersion, oldCommit, oldBuildDate := Version, Commit, BuildDate
+	Version, Commit, BuildDate = version, commit, buildDate
+	t.Cleanup(func() {
+		Version, Commit, BuildDate = oldVersion, oldCommit, oldBuildDate
+	})
+}
+
+func TestVersionCommand(t *testing.T) {
+	setVersion(t, "1.2.3", "abc1234", "2024-01-02T15:04:05Z")
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewRootCmd(cfg, log), "version")
+	})
+	if err != nil {
+		t.Fatalf("version failed: %v", err)
+	}
+
+	for _, expected := range []string{"1.2.3", "abc1234", "2024-01-02T15:04:05Z"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestVersionCommandJSON(t *testing.T) {
+	setVersion(t, "1.2.3", "abc1234", "2024-01-02T15:04:05Z")
+
+	cfg := testutil.TestConfig()
+	log := testutil.TestLogger()
+
+	var err error
+	output := captureStdout(func() {
+		_, err = executeCommand(NewRootCmd(cfg, log), "version", "--format", "json")
+	})
+	if err != nil {
+		t.Fatalf("version --format json failed: %v", err)
+	}
+
+	var info types.VersionInfo
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		t.Fatalf("Output is not JSON: %v (%s)", err, output)
+	}
+	if info.Version != "1.2.3" || info.Commit != "abc1234" {
+		t.Errorf("Unexpected version info: %+v", info)
+	}
+	if info.KafkaProtocolVersion == "" {
+		t.Error("Expected the default Kafka protocol version to be set")
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nipunap/kim/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// watchFlags holds the --watch and --interval flags of list commands
+type watchFlags struct {
+	watch    bool
+	interval time.Duration
+}
+
+// register adds the watch flags to the command
+func (f *watchFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&f.watch, "watch", "w", false, "refresh the output periodically until interrupted")
+	cmd.Flags().DurationVar(&f.interval, "interval", 0, "refresh interval for --watch (default refresh_interval setting)")
+}
+
+// run renders once, or repeatedly until Ctrl+C when --watch is set. Each
+// render is limited by --timeout.
+func (f *watchFlags) run(cfg *config.Config, render func(ctx context.Context) error) error {
+	if !f.watch {
+		ctx, cancel := commandContext()
+		defer cancel()
+		return render(ctx)
+	}
+
+	interval := f.interval
+	if interval <= 0 {
+		interval = defaultRefreshInterval(cfg)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return watchLoop(ctx, interval, func(ctx context.Context) error {
+		ctx, cancel := withCommandTimeout(ctx)
+		defer cancel()
+		return render(ctx)
+	})
+}
+
+// clearScreen clears the terminal before each render in watch mode
+var clearScreen = func() {
+	fmt.Print("\x1b[H\x1b[2J")
+}
+
+// watchLoop clears the screen and renders every interval until ctx is cancelled
+func watchLoop(ctx context.Context, interval time.Duration, render func(ctx context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		clearScreen()
+		if err := render(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		fmt.Printf("\nEvery %s, updated %s. Press Ctrl+C to stop.\n", interval, time.Now().Format("15:04:05"))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatchLoop(t *testing.T) {
+	oldClear := clearScreen
+	clears := 0
+	clearScreen = func() { clears++ }
+	t.Cleanup(func() { clearScreen = oldClear })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	renders := 0
+	var err error
+	captureStdout(func() {
+		err = watchLoop(ctx, 10*time.Millisecond, func(ctx context.Context) error {
+			renders++
+			if renders == 2 {
+				cancel()
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		t.Fatalf("watchLoop failed: %v", err)
+	}
+	if renders != 2 {
+		t.Errorf("Expected the loop to stop after the second render, got %d renders", renders)
+	}
+	if clears != renders {
+		t.Errorf("Expected the screen to be cleared before each render, got %d clears", clears)
+	}
+}
+
+func TestWatchLoopRenderError(t *testing.T) {
+	oldClear := clearScreen
+	clearScreen = func() {}
+	t.Cleanup(func() { clearScreen = oldClear })
+
+	renderErr := errors.New("failed to list topics")
+	err := watchLoop(context.Background(), time.Millisecond, func(ctx context.Context) error {
+		return renderErr
+	})
+	if !errors.Is(err, renderErr) {
+		t.Errorf("Expected the render error, got %v", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,27 +15,42 @@ type Config struct {
 	Profiles      map[string]*Profile `mapstructure:"profiles" yaml:"profiles"`
 	ActiveProfile string              `mapstructure:"active_profile" yaml:"active_profile"`
 	Settings      *Settings           `mapstructure:"settings" yaml:"settings"`
+	EncryptionKey string              `mapstructure:"-" yaml:"-"` // from KIM_CONFIG_KEY
+	Warnings      []string            `mapstructure:"-" yaml:"-"` // problems found while loading
 	configPath    string
 }
 
 // Profile represents a Kafka cluster configuration
 type Profile struct {
-	Name             string            `mapstructure:"name" yaml:"name"`
-	Type             string            `mapstructure:"type" yaml:"type"` // "kafka" or "msk"
-	BootstrapServers string            `mapstructure:"bootstrap_servers,omitempty" yaml:"bootstrap_servers,omitempty"`
-	Region           string            `mapstructure:"region,omitempty" yaml:"region,omitempty"`
-	ClusterARN       string            `mapstructure:"cluster_arn,omitempty" yaml:"cluster_arn,omitempty"`
-	AuthMethod       string            `mapstructure:"auth_method,omitempty" yaml:"auth_method,omitempty"`
-	SecurityProtocol string            `mapstructure:"security_protocol,omitempty" yaml:"security_protocol,omitempty"`
-	SASLMechanism    string            `mapstructure:"sasl_mechanism,omitempty" yaml:"sasl_mechanism,omitempty"`
-	SASLUsername     string            `mapstructure:"sasl_username,omitempty" yaml:"sasl_username,omitempty"`
-	SASLPassword     string            `mapstructure:"sasl_password,omitempty" yaml:"sasl_password,omitempty"`
-	SSLCAFile        string            `mapstructure:"ssl_ca_file,omitempty" yaml:"ssl_ca_file,omitempty"`
-	SSLCertFile      string            `mapstructure:"ssl_cert_file,omitempty" yaml:"ssl_cert_file,omitempty"`
-	SSLKeyFile       string            `mapstructure:"ssl_key_file,omitempty" yaml:"ssl_key_file,omitempty"`
-	SSLPassword      string            `mapstructure:"ssl_password,omitempty" yaml:"ssl_password,omitempty"`
-	SSLCheckHostname bool              `mapstructure:"ssl_check_hostname,omitempty" yaml:"ssl_check_hostname,omitempty"`
-	Extra            map[string]string `mapstructure:"extra,omitempty" yaml:"extra,omitempty"`
+	Name              string            `mapstructure:"name" yaml:"name"`
+	Type              string            `mapstructure:"type" yaml:"type"` // "kafka" or "msk"
+	BootstrapServers  string            `mapstructure:"bootstrap_servers,omitempty" yaml:"bootstrap_servers,omitempty"`
+	Region            string            `mapstructure:"region,omitempty" yaml:"region,omitempty"`
+	ClusterARN        string            `mapstructure:"cluster_arn,omitempty" yaml:"cluster_arn,omitempty"`
+	AuthMethod        string            `mapstructure:"auth_method,omitempty" yaml:"auth_method,omitempty"`
+	AWSProfile        string            `mapstructure:"aws_profile,omitempty" yaml:"aws_profile,omitempty"`
+	AWSRoleARN        string            `mapstructure:"aws_role_arn,omitempty" yaml:"aws_role_arn,omitempty"`
+	SecurityProtocol  string            `mapstructure:"security_protocol,omitempty" yaml:"security_protocol,omitempty"`
+	SASLMechanism     string            `mapstructure:"sasl_mechanism,omitempty" yaml:"sasl_mechanism,omitempty"`
+	SASLUsername      string            `mapstructure:"sasl_username,omitempty" yaml:"sasl_username,omitempty"`
+	SASLPassword      string            `mapstructure:"sasl_password,omitempty" yaml:"sasl_password,omitempty"`
+	OAuthTokenCommand string            `mapstructure:"oauth_token_command,omitempty" yaml:"oauth_token_command,omitempty"`
+	OAuthTokenEnv     string            `mapstructure:"oauth_token_env,omitempty" yaml:"oauth_token_env,omitempty"`
+	SSLCAFile         string            `mapstructure:"ssl_ca_file,omitempty" yaml:"ssl_ca_file,omitempty"`
+	SSLCertFile       string            `mapstructure:"ssl_cert_file,omitempty" yaml:"ssl_cert_file,omitempty"`
+	SSLKeyFile        string            `mapstructure:"ssl_key_file,omitempty" yaml:"ssl_key_file,omitempty"`
+	SSLPassword       string            `mapstructure:"ssl_password,omitempty" yaml:"ssl_password,omitempty"`
+	SSLCheckHostname  bool              `mapstructure:"ssl_check_hostname,omitempty" yaml:"ssl_check_hostname,omitempty"`
+	TLSMinVersion     string            `mapstructure:"tls_min_version,omitempty" yaml:"tls_min_version,omitempty"` // "1.0" to "1.3"
+	TLSServerName     string            `mapstructure:"tls_server_name,omitempty" yaml:"tls_server_name,omitempty"` // SNI and verified hostname, e.g. behind a load balancer
+	SchemaRegistryURL string            `mapstructure:"schema_registry_url,omitempty" yaml:"schema_registry_url,omitempty"`
+	KafkaVersion      string            `mapstructure:"kafka_version,omitempty" yaml:"kafka_version,omitempty"`
+	ClientID          string            `mapstructure:"client_id,omitempty" yaml:"client_id,omitempty"`
+	DialTimeout       time.Duration     `mapstructure:"dial_timeout,omitempty" yaml:"dial_timeout,omitempty"`
+	ReadTimeout       time.Duration     `mapstructure:"read_timeout,omitempty" yaml:"read_timeout,omitempty"`
+	WriteTimeout      time.Duration     `mapstructure:"write_timeout,omitempty" yaml:"write_timeout,omitempty"`
+	MetadataRetryMax  int               `mapstructure:"metadata_retry_max,omitempty" yaml:"metadata_retry_max,omitempty"`
+	Extra             map[string]string `mapstructure:"extra,omitempty" yaml:"extra,omitempty"`
 }
 
 // Settings represents application settings
@@ -45,25 +62,35 @@ type Settings struct {
 	VimMode         bool   `mapstructure:"vim_mode" yaml:"vim_mode"`
 }
 
-// New creates a new configuration instance
+// New creates a new configuration instance from the default config file,
+// $XDG_CONFIG_HOME/kim/config.yaml if XDG_CONFIG_HOME is set and
+// ~/.kim/config.yaml otherwise
 func New() (*Config, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %w", err)
-	}
+	return NewWithPath("")
+}
 
-	configDir := filepath.Join(homeDir, ".kim")
-	configPath := filepath.Join(configDir, "config.yaml")
+// NewWithPath creates a new configuration instance from the config file at
+// path, creating it with default settings if it does not exist. An empty path
+// selects the default config file.
+func NewWithPath(path string) (*Config, error) {
+	configPath := path
+	if configPath == "" {
+		var err error
+		if configPath, err = defaultConfigPath(); err != nil {
+			return nil, err
+		}
+	}
+	configDir := filepath.Dir(configPath)
 
 	// Create config directory if it doesn't exist
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Initialize viper
-	viper.SetConfigName("config")
+	// Initialize viper, dropping state left by a previously loaded config
+	viper.Reset()
+	viper.SetConfigFile(configPath)
 	viper.SetConfigType("yaml")
-	viper.AddConfigPath(configDir)
 
 	// Set defaults
 	viper.SetDefault("profiles", map[string]*Profile{})
@@ -77,33 +104,78 @@ func New() (*Config, error) {
 	})
 
 	config := &Config{
-		configPath: configPath,
+		EncryptionKey: os.Getenv(encryptionKeyEnv),
+		configPath:    configPath,
 	}
 
-	// Try to read existing config
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// Config file not found, create default
-			if err := config.createDefaultConfig(); err != nil {
-				return nil, fmt.Errorf("failed to create default config: %w", err)
-			}
-			// Try to read the newly created config
-			if err := viper.ReadInConfig(); err != nil {
-				return nil, fmt.Errorf("failed to read newly created config: %w", err)
-			}
-		} else {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+	// Create a default config file if there is none yet
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if err := config.createDefaultConfig(); err != nil {
+			return nil, fmt.Errorf("failed to create default config: %w", err)
 		}
 	}
 
+	// Read the config
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
 	// Unmarshal config
 	if err := viper.Unmarshal(config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Decrypt password fields stored encrypted at rest
+	if err := config.decryptProfiles(); err != nil {
+		return nil, fmt.Errorf("failed to decrypt config: %w", err)
+	}
+
 	return config, nil
 }
 
+// defaultConfigPath returns the path of the default config file. When
+// XDG_CONFIG_HOME is set, a config in the legacy ~/.kim directory is copied
+// there the first time.
+func defaultConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	legacyPath := filepath.Join(homeDir, ".kim", "config.yaml")
+
+	xdgHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgHome == "" {
+		return legacyPath, nil
+	}
+	configPath := filepath.Join(xdgHome, "kim", "config.yaml")
+
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		return configPath, nil
+	}
+	if err := migrateConfig(legacyPath, configPath); err != nil {
+		return "", fmt.Errorf("failed to migrate %s to %s: %w", legacyPath, configPath, err)
+	}
+
+	return configPath, nil
+}
+
+// migrateConfig copies the config file at from to to, if there is one. The
+// original is kept for older versions of kim.
+func migrateConfig(from, to string) error {
+	data, err := os.ReadFile(from)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(to, data, 0600)
+}
+
 // createDefaultConfig creates a default configuration file
 func (c *Config) createDefaultConfig() error {
 	c.Profiles = make(map[string]*Profile)
@@ -121,7 +193,12 @@ func (c *Config) createDefaultConfig() error {
 
 // Save saves the configuration to file
 func (c *Config) Save() error {
-	viper.Set("profiles", c.Profiles)
+	profiles, err := c.encryptedProfiles()
+	if err != nil {
+		return err
+	}
+
+	viper.Set("profiles", profiles)
 	viper.Set("active_profile", c.ActiveProfile)
 	viper.Set("settings", c.Settings)
 
@@ -150,6 +227,46 @@ func (c *Config) AddProfile(profile *Profile) error {
 	return c.Save()
 }
 
+// UpdateProfile replaces an existing profile with the given one
+func (c *Config) UpdateProfile(profile *Profile) error {
+	if _, exists := c.Profiles[profile.Name]; !exists {
+		return fmt.Errorf("profile '%s' not found", profile.Name)
+	}
+
+	// Validate profile
+	if err := c.validateProfile(profile); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	c.Profiles[profile.Name] = profile
+	return c.Save()
+}
+
+// RenameProfile renames a profile, keeping it active if it was the active profile
+func (c *Config) RenameProfile(oldName, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("new profile name is required")
+	}
+
+	profile, exists := c.Profiles[oldName]
+	if !exists {
+		return fmt.Errorf("profile '%s' not found", oldName)
+	}
+	if _, exists := c.Profiles[newName]; exists {
+		return fmt.Errorf("profile '%s' already exists", newName)
+	}
+
+	delete(c.Profiles, oldName)
+	profile.Name = newName
+	c.Profiles[newName] = profile
+
+	if c.ActiveProfile == oldName {
+		c.ActiveProfile = newName
+	}
+
+	return c.Save()
+}
+
 // GetProfile returns a profile by name
 func (c *Config) GetProfile(name string) (*Profile, error) {
 	profile, exists := c.Profiles[name]
@@ -176,6 +293,15 @@ func (c *Config) SetActiveProfile(name string) error {
 	return c.Save()
 }
 
+// Dir returns the directory holding the configuration file, or "" if the
+// configuration was not loaded from a file
+func (c *Config) Dir() string {
+	if c.configPath == "" {
+		return ""
+	}
+	return filepath.Dir(c.configPath)
+}
+
 // ListProfiles returns all profile names
 func (c *Config) ListProfiles() []string {
 	names := make([]string, 0, len(c.Profiles))
@@ -219,11 +345,77 @@ func (c *Config) validateProfile(profile *Profile) error {
 				return fmt.Errorf("invalid security_protocol: %s", profile.SecurityProtocol)
 			}
 		}
+		if strings.HasPrefix(profile.SecurityProtocol, "SASL_") {
+			if err := validateSASL(profile); err != nil {
+				return err
+			}
+		}
 	case "":
 		return fmt.Errorf("profile type is required (must be 'kafka' or 'msk')")
 	default:
 		return fmt.Errorf("invalid profile type: %s (must be 'kafka' or 'msk')", profile.Type)
 	}
 
+	if profile.DialTimeout < 0 || profile.ReadTimeout < 0 || profile.WriteTimeout < 0 {
+		return fmt.Errorf("dial_timeout, read_timeout and write_timeout must not be negative")
+	}
+	if profile.MetadataRetryMax < 0 {
+		return fmt.Errorf("metadata_retry_max must not be negative")
+	}
+	switch profile.TLSMinVersion {
+	case "", "1.0", "1.1", "1.2", "1.3":
+	default:
+		return fmt.Errorf("invalid tls_min_version: %s (must be 1.0, 1.1, 1.2 or 1.3)", profile.TLSMinVersion)
+	}
+
+	return nil
+}
+
+// validateSASL checks that a SASL profile has what its mechanism needs to
+// authenticate. GSSAPI takes its credentials from Kerberos and OAUTHBEARER from
+// a token source, so neither needs a username or password.
+func validateSASL(profile *Profile) error {
+	switch profile.SASLMechanism {
+	case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+		if profile.SASLUsername == "" || profile.SASLPassword == "" {
+			return fmt.Errorf("sasl_username and sasl_password are required for SASL mechanism %s", profile.SASLMechanism)
+		}
+	case "GSSAPI":
+	case "OAUTHBEARER":
+		if profile.OAuthTokenCommand == "" && profile.OAuthTokenEnv == "" {
+			return fmt.Errorf("oauth_token_command or oauth_token_env is required for SASL mechanism OAUTHBEARER")
+		}
+	case "":
+		return fmt.Errorf("sasl_mechanism is required for security protocol %s", profile.SecurityProtocol)
+	default:
+		return fmt.Errorf("unsupported sasl_mechanism: %s (must be PLAIN, SCRAM-SHA-256, SCRAM-SHA-512, GSSAPI or OAUTHBEARER)", profile.SASLMechanism)
+	}
+	return nil
+}
+
+// CheckProfileFiles checks that the SSL files a profile refers to exist and are
+// readable. It is kept out of validateProfile so configs that are copied
+// between machines still load.
+func CheckProfileFiles(profile *Profile) error {
+	files := []struct {
+		name string
+		path string
+	}{
+		{"ssl_ca_file", profile.SSLCAFile},
+		{"ssl_cert_file", profile.SSLCertFile},
+		{"ssl_key_file", profile.SSLKeyFile},
+	}
+
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		f, err := os.Open(file.path)
+		if err != nil {
+			return fmt.Errorf("%s %s is not readable: %w", file.name, file.path, err)
+		}
+		f.Close()
+	}
+
 	return nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -49,6 +50,99 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewWithPath(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Set HOME to temp directory
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", oldHome)
+
+	configPath := filepath.Join(tempDir, "custom", "kim.yaml")
+
+	cfg, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	if err := cfg.AddProfile(&Profile{Name: "custom", Type: "kafka", BootstrapServers: "localhost:9092"}); err != nil {
+		t.Fatalf("Failed to add profile: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, ".kim", "config.yaml")); !os.IsNotExist(err) {
+		t.Error("Expected no config file in the home directory")
+	}
+
+	// Loading the default config must not see the custom profile
+	defaultCfg, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create default config: %v", err)
+	}
+	if _, exists := defaultCfg.Profiles["custom"]; exists {
+		t.Error("Expected the default config not to contain the custom profile")
+	}
+
+	cfg2, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if _, err := cfg2.GetProfile("custom"); err != nil {
+		t.Errorf("Expected the custom profile to be saved to %s: %v", configPath, err)
+	}
+}
+
+func TestNewXDGConfigHome(t *testing.T) {
+	profile := &Profile{Name: "existing", Type: "kafka", BootstrapServers: "localhost:9092"}
+
+	tests := []struct {
+		name         string
+		xdg          bool
+		legacyConfig bool
+		expectedPath string
+	}{
+		{name: "XDG unset", expectedPath: ".kim/config.yaml"},
+		{name: "XDG set", xdg: true, expectedPath: "xdg/kim/config.yaml"},
+		{name: "XDG set with legacy config", xdg: true, legacyConfig: true, expectedPath: "xdg/kim/config.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			t.Setenv("HOME", tempDir)
+			t.Setenv("XDG_CONFIG_HOME", "")
+			if tt.xdg {
+				t.Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			}
+
+			if tt.legacyConfig {
+				legacy, err := NewWithPath(filepath.Join(tempDir, ".kim", "config.yaml"))
+				if err != nil {
+					t.Fatalf("Failed to create legacy config: %v", err)
+				}
+				if err := legacy.AddProfile(profile); err != nil {
+					t.Fatalf("Failed to add profile: %v", err)
+				}
+			}
+
+			cfg, err := New()
+			if err != nil {
+				t.Fatalf("Failed to create config: %v", err)
+			}
+
+			expectedPath := filepath.Join(tempDir, tt.expectedPath)
+			if _, err := os.Stat(expectedPath); err != nil {
+				t.Errorf("Expected config file at %s: %v", expectedPath, err)
+			}
+			if cfg.Dir() != filepath.Dir(expectedPath) {
+				t.Errorf("Expected config dir %s, got %s", filepath.Dir(expectedPath), cfg.Dir())
+			}
+
+			if _, exists := cfg.Profiles["existing"]; exists != tt.legacyConfig {
+				t.Errorf("Expected existing profile to be migrated: %v, got %v", tt.legacyConfig, exists)
+			}
+		})
+	}
+}
+
 func TestAddProfile(t *testing.T) {
 	// Create a temporary directory for testing
 	tempDir, err := os.MkdirTemp("", "kim-test-*")
@@ -418,9 +512,450 @@ func TestProfileValidationEdgeCases(t *testing.T) {
 		BootstrapServers: "localhost:9092",
 		SecurityProtocol: "SASL_PLAINTEXT",
 		SASLMechanism:    "PLAIN",
+		SASLUsername:     "testuser",
+		SASLPassword:     "testpass",
 	}
 	err = cfg.validateProfile(validSASL)
 	if err != nil {
 		t.Errorf("Valid SASL profile should not return error: %v", err)
 	}
 }
+
+func TestValidateProfileSASL(t *testing.T) {
+	cfg := &Config{}
+
+	base := func(mechanism string) *Profile {
+		return &Profile{
+			Name:             "sasl",
+			Type:             "kafka",
+			BootstrapServers: "localhost:9092",
+			SecurityProtocol: "SASL_SSL",
+			SASLMechanism:    mechanism,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		profile func() *Profile
+		wantErr string
+	}{
+		{
+			name:    "missing mechanism",
+			profile: func() *Profile { return base("") },
+			wantErr: "sasl_mechanism is required",
+		},
+		{
+			name:    "unsupported mechanism",
+			profile: func() *Profile { return base("DIGEST-MD5") },
+			wantErr: "unsupported sasl_mechanism",
+		},
+		{
+			name:    "PLAIN without credentials",
+			profile: func() *Profile { return base("PLAIN") },
+			wantErr: "sasl_username and sasl_password are required",
+		},
+		{
+			name: "SCRAM without password",
+			profile: func() *Profile {
+				p := base("SCRAM-SHA-512")
+				p.SASLUsername = "testuser"
+				return p
+			},
+			wantErr: "sasl_username and sasl_password are required",
+		},
+		{
+			name: "SCRAM with credentials",
+			profile: func() *Profile {
+				p := base("SCRAM-SHA-256")
+				p.SASLUsername = "testuser"
+				p.SASLPassword = "${KAFKA_PASSWORD}"
+				return p
+			},
+		},
+		{
+			name:    "GSSAPI without credentials",
+			profile: func() *Profile { return base("GSSAPI") },
+		},
+		{
+			name:    "OAUTHBEARER without a token source",
+			profile: func() *Profile { return base("OAUTHBEARER") },
+			wantErr: "oauth_token_command or oauth_token_env is required",
+		},
+		{
+			name: "OAUTHBEARER with a token command",
+			profile: func() *Profile {
+				p := base("OAUTHBEARER")
+				p.OAuthTokenCommand = "get-token"
+				return p
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.validateProfile(tt.profile())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// SASL settings are not checked for other protocols
+	plaintext := base("")
+	plaintext.SecurityProtocol = "PLAINTEXT"
+	if err := cfg.validateProfile(plaintext); err != nil {
+		t.Errorf("A PLAINTEXT profile should not need SASL settings: %v", err)
+	}
+}
+
+func TestCheckProfileFiles(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, []byte("pem"), 0600); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+
+	profile := &Profile{
+		Name:             "ssl",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9093",
+		SecurityProtocol: "SSL",
+	}
+	if err := CheckProfileFiles(profile); err != nil {
+		t.Errorf("A profile without SSL files should pass: %v", err)
+	}
+
+	profile.SSLCAFile = caFile
+	if err := CheckProfileFiles(profile); err != nil {
+		t.Errorf("Existing SSL files should pass: %v", err)
+	}
+
+	missing := filepath.Join(dir, "missing-key.pem")
+	profile.SSLKeyFile = missing
+	err := CheckProfileFiles(profile)
+	if err == nil {
+		t.Fatal("A missing SSL key file should fail")
+	}
+	if !strings.Contains(err.Error(), "ssl_key_file") || !strings.Contains(err.Error(), missing) {
+		t.Errorf("Error should name the missing file, got: %v", err)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	// Create a temporary directory for testing
+	tempDir, err := os.MkdirTemp("", "kim-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Set HOME to temp directory
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	profile := &Profile{
+		Name:             "test-update",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9092",
+		SecurityProtocol: "PLAINTEXT",
+	}
+	if err := cfg.AddProfile(profile); err != nil {
+		t.Fatalf("Failed to add profile: %v", err)
+	}
+
+	// Update with a valid change
+	updated := *profile
+	updated.BootstrapServers = "localhost:9093"
+	if err := cfg.UpdateProfile(&updated); err != nil {
+		t.Fatalf("Failed to update profile: %v", err)
+	}
+	if cfg.Profiles["test-update"].BootstrapServers != "localhost:9093" {
+		t.Errorf("Expected bootstrap servers 'localhost:9093', got '%s'", cfg.Profiles["test-update"].BootstrapServers)
+	}
+
+	// Invalid update should be rejected
+	invalid := updated
+	invalid.BootstrapServers = ""
+	if err := cfg.UpdateProfile(&invalid); err == nil {
+		t.Error("Update with missing bootstrap servers should return error")
+	}
+
+	// Updating a missing profile should fail
+	missing := &Profile{Name: "missing", Type: "kafka", BootstrapServers: "localhost:9092"}
+	if err := cfg.UpdateProfile(missing); err == nil {
+		t.Error("Updating a non-existent profile should return error")
+	}
+}
+
+func TestRenameProfile(t *testing.T) {
+	// Create a temporary directory for testing
+	tempDir, err := os.MkdirTemp("", "kim-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Set HOME to temp directory
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	profile1 := &Profile{
+		Name:             "profile1",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9092",
+		SecurityProtocol: "PLAINTEXT",
+	}
+	profile2 := &Profile{
+		Name:             "profile2",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9093",
+		SecurityProtocol: "PLAINTEXT",
+	}
+
+	cfg.AddProfile(profile1)
+	cfg.AddProfile(profile2)
+	cfg.SetActiveProfile("profile1")
+
+	// Rename the active profile
+	if err := cfg.RenameProfile("profile1", "renamed"); err != nil {
+		t.Fatalf("Failed to rename profile: %v", err)
+	}
+
+	if _, exists := cfg.Profiles["profile1"]; exists {
+		t.Error("Old profile name should no longer exist")
+	}
+
+	renamed, err := cfg.GetProfile("renamed")
+	if err != nil {
+		t.Fatalf("Failed to get renamed profile: %v", err)
+	}
+	if renamed.Name != "renamed" {
+		t.Errorf("Expected profile name 'renamed', got '%s'", renamed.Name)
+	}
+	if cfg.ActiveProfile != "renamed" {
+		t.Errorf("Expected active profile 'renamed', got '%s'", cfg.ActiveProfile)
+	}
+
+	// Renaming to an existing name should fail
+	if err := cfg.RenameProfile("renamed", "profile2"); err == nil {
+		t.Error("Renaming to an existing profile name should return error")
+	}
+	if _, exists := cfg.Profiles["renamed"]; !exists {
+		t.Error("Failed rename should leave the original profile in place")
+	}
+
+	// Renaming a missing profile should fail
+	if err := cfg.RenameProfile("missing", "other"); err == nil {
+		t.Error("Renaming a non-existent profile should return error")
+	}
+}
+
+func TestConfigEncryptedPasswords(t *testing.T) {
+	// Create a temporary directory for testing
+	tempDir, err := os.MkdirTemp("", "kim-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Set HOME to temp directory
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", oldHome)
+
+	// Enable encryption
+	oldKey := os.Getenv("KIM_CONFIG_KEY")
+	os.Setenv("KIM_CONFIG_KEY", "test-encryption-key")
+	defer os.Setenv("KIM_CONFIG_KEY", oldKey)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	profile := &Profile{
+		Name:             "test-encrypted",
+		Type:             "kafka",
+		BootstrapServers: "localhost:9092",
+		SecurityProtocol: "SASL_SSL",
+		SASLMechanism:    "PLAIN",
+		SASLUsername:     "testuser",
+		SASLPassword:     "super-secret-sasl",
+		SSLPassword:      "super-secret-ssl",
+	}
+
+	if err := cfg.AddProfile(profile); err != nil {
+		t.Fatalf("Failed to add profile: %v", err)
+	}
+
+	// Verify the file on disk does not contain plaintext passwords
+	data, err := os.ReadFile(filepath.Join(tempDir, ".kim", "config.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+
+	content := string(data)
+	if strings.Contains(content, "super-secret-sasl") || strings.Contains(content, "super-secret-ssl") {
+		t.Error("Config file should not contain plaintext passwords")
+	}
+	if !strings.Contains(content, "enc:") {
+		t.Error("Config file should contain encrypted password values")
+	}
+
+	// In-memory profile keeps the plaintext value
+	if cfg.Profiles["test-encrypted"].SASLPassword != "super-secret-sasl" {
+		t.Error("In-memory profile should keep the plaintext password")
+	}
+
+	// Reloading decrypts transparently
+	cfg2, err := New()
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+
+	loaded, err := cfg2.GetProfile("test-encrypted")
+	if err != nil {
+		t.Fatalf("Failed to get loaded profile: %v", err)
+	}
+	if loaded.SASLPassword != "super-secret-sasl" {
+		t.Errorf("Expected decrypted SASL password, got '%s'", loaded.SASLPassword)
+	}
+	if loaded.SSLPassword != "super-secret-ssl" {
+		t.Errorf("Expected decrypted SSL password, got '%s'", loaded.SSLPassword)
+	}
+}
+
+func TestEncryptDecryptValue(t *testing.T) {
+	cfg := &Config{EncryptionKey: "test-key"}
+
+	encrypted, err := cfg.encryptValue("password")
+	if err != nil {
+		t.Fatalf("Failed to encrypt value: %v", err)
+	}
+	if !strings.HasPrefix(encrypted, "enc:") {
+		t.Errorf("Encrypted value should have 'enc:' prefix, got '%s'", encrypted)
+	}
+
+	decrypted, err := cfg.decryptValue(encrypted)
+	if err != nil {
+		t.Fatalf("Failed to decrypt value: %v", err)
+	}
+	if decrypted != "password" {
+		t.Errorf("Expected 'password', got '%s'", decrypted)
+	}
+
+	// Plaintext values pass through unchanged
+	plain, err := cfg.decryptValue("plaintext")
+	if err != nil || plain != "plaintext" {
+		t.Errorf("Plaintext should pass through unchanged, got '%s' (%v)", plain, err)
+	}
+
+	// Wrong key fails
+	wrongKey := &Config{EncryptionKey: "other-key"}
+	if _, err := wrongKey.decryptValue(encrypted); err == nil {
+		t.Error("Decrypting with the wrong key should return error")
+	}
+
+	// Missing key fails
+	noKey := &Config{}
+	if _, err := noKey.decryptValue(encrypted); err == nil {
+		t.Error("Decrypting without a key should return error")
+	}
+
+	// A plaintext value that starts with the prefix is not mistaken for one
+	for _, value := range []string{"enc:hunter2", "enc:c2VjcmV0"} {
+		for _, c := range []*Config{cfg, noKey} {
+			plain, err := c.decryptValue(value)
+			if err != nil || plain != value {
+				t.Errorf("Expected %q to pass through unchanged, got '%s' (%v)", value, plain, err)
+			}
+		}
+	}
+
+	// and is encrypted like any other value
+	encrypted, err = cfg.encryptValue("enc:hunter2")
+	if err != nil {
+		t.Fatalf("Failed to encrypt value: %v", err)
+	}
+	if decrypted, err := cfg.decryptValue(encrypted); err != nil || decrypted != "enc:hunter2" {
+		t.Errorf("Expected 'enc:hunter2' to round-trip, got '%s' (%v)", decrypted, err)
+	}
+}
+
+func TestConfigPlaintextPasswordWithEncryptedPrefix(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("KIM_CONFIG_KEY", "")
+
+	configPath := filepath.Join(tempDir, "config.yaml")
+	content := `profiles:
+  legacy:
+    name: legacy
+    type: kafka
+    bootstrap_servers: localhost:9092
+    sasl_password: "enc:hunter2"
+active_profile: legacy
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected the config to load, got %v", err)
+	}
+	if got := cfg.Profiles["legacy"].SASLPassword; got != "enc:hunter2" {
+		t.Errorf("Expected the plaintext password, got '%s'", got)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "legacy") {
+		t.Errorf("Expected a warning about profile 'legacy', got %v", cfg.Warnings)
+	}
+}
+
+func TestSettingsSetAndGet(t *testing.T) {
+	settings := &Settings{PageSize: 20, DefaultFormat: "table"}
+
+	if err := settings.Set("page_size", "100"); err != nil {
+		t.Fatalf("Set page_size failed: %v", err)
+	}
+	if err := settings.Set("vim_mode", "true"); err != nil {
+		t.Fatalf("Set vim_mode failed: %v", err)
+	}
+
+	for key, want := range map[string]string{"page_size": "100", "vim_mode": "true", "default_format": "table"} {
+		got, err := settings.Get(key)
+		if err != nil {
+			t.Fatalf("Get %s failed: %v", key, err)
+		}
+		if got != want {
+			t.Errorf("Expected %s = %s, got %s", key, want, got)
+		}
+	}
+
+	if err := settings.Set("page_size", "-1"); err == nil {
+		t.Error("Negative page_size should be rejected")
+	}
+	if err := settings.Set("default_format", "csv"); err == nil {
+		t.Error("Unknown default_format should be rejected")
+	}
+	if settings.PageSize != 100 || settings.DefaultFormat != "table" {
+		t.Errorf("Rejected values should not change settings, got %+v", settings)
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// encryptionKeyEnv is the environment variable holding the config encryption key
+	encryptionKeyEnv = "KIM_CONFIG_KEY"
+
+	// encryptedPrefix marks values that are stored encrypted on disk
+	encryptedPrefix = "enc:"
+
+	// minSealedSize is the size of an encrypted value without any plaintext: the
+	// AES-GCM nonce followed by the authentication tag
+	minSealedSize = 12 + 16
+)
+
+// encryptedProfiles returns copies of the profiles with password fields encrypted.
+// Profiles are returned unchanged when no encryption key is configured.
+func (c *Config) encryptedProfiles() (map[string]*Profile, error) {
+	if c.EncryptionKey == "" {
+		return c.Profiles, nil
+	}
+
+	profiles := make(map[string]*Profile, len(c.Profiles))
+	for name, profile := range c.Profiles {
+		encrypted := *profile
+
+		var err error
+		if encrypted.SASLPassword, err = c.encryptValue(profile.SASLPassword); err != nil {
+			return nil, fmt.Errorf("failed to encrypt SASL password for profile '%s': %w", name, err)
+		}
+		if encrypted.SSLPassword, err = c.encryptValue(profile.SSLPassword); err != nil {
+			return nil, fmt.Errorf("failed to encrypt SSL password for profile '%s': %w", name, err)
+		}
+
+		profiles[name] = &encrypted
+	}
+
+	return profiles, nil
+}
+
+// decryptProfiles decrypts the password fields of all loaded profiles in place
+func (c *Config) decryptProfiles() error {
+	for name, profile := range c.Profiles {
+		var err error
+		if profile.SASLPassword, err = c.decryptField(name, "SASL password", profile.SASLPassword); err != nil {
+			return err
+		}
+		if profile.SSLPassword, err = c.decryptField(name, "SSL password", profile.SSLPassword); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decryptField decrypts a password field of a profile. A plaintext value that
+// only looks encrypted is kept as-is, with a warning.
+func (c *Config) decryptField(profile, field, value string) (string, error) {
+	if _, ok := sealedValue(value); !ok && strings.HasPrefix(value, encryptedPrefix) {
+		c.Warnings = append(c.Warnings, fmt.Sprintf(
+			"%s of profile '%s' starts with %q but is not encrypted, using it as plaintext", field, profile, encryptedPrefix))
+	}
+
+	plaintext, err := c.decryptValue(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s for profile '%s': %w", field, profile, err)
+	}
+	return plaintext, nil
+}
+
+// encryptValue encrypts a value with AES-GCM and returns it with the encrypted prefix.
+// Values are always plaintext in memory, so one that starts with the prefix is
+// encrypted too and reads back unchanged.
+func (c *Config) encryptValue(value string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+
+	gcm, err := c.newGCM()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptValue decrypts a value produced by encryptValue. Plaintext values, including
+// ones that start with the prefix but are not a valid encrypted value, are returned as-is.
+func (c *Config) decryptValue(value string) (string, error) {
+	sealed, ok := sealedValue(value)
+	if !ok {
+		return value, nil
+	}
+
+	if c.EncryptionKey == "" {
+		return "", fmt.Errorf("value is encrypted but %s is not set", encryptionKeyEnv)
+	}
+
+	gcm, err := c.newGCM()
+	if err != nil {
+		return "", err
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value (wrong %s?): %w", encryptionKeyEnv, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// sealedValue returns the nonce and ciphertext of a value produced by encryptValue.
+// ok is false for a plaintext value, even one that starts with the prefix.
+func sealedValue(value string) ([]byte, bool) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return nil, false
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < minSealedSize {
+		return nil, false
+	}
+	return sealed, true
+}
+
+// newGCM creates an AES-GCM cipher from the configured encryption key
+func (c *Config) newGCM() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(c.EncryptionKey))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SettingKeys lists the keys accepted by Settings.Get and Settings.Set
+var SettingKeys = []string{"page_size", "refresh_interval", "default_format", "color_scheme", "vim_mode"}
+
+// validFormats are the output formats accepted for default_format
+var validFormats = map[string]bool{
+	"table": true,
+	"json":  true,
+	"yaml":  true,
+}
+
+// Get returns the value of a setting as a string
+func (s *Settings) Get(key string) (string, error) {
+	switch key {
+	case "page_size":
+		return strconv.Itoa(s.PageSize), nil
+	case "refresh_interval":
+		return strconv.Itoa(s.RefreshInterval), nil
+	case "default_format":
+		return s.DefaultFormat, nil
+	case "color_scheme":
+		return s.ColorScheme, nil
+	case "vim_mode":
+		return strconv.FormatBool(s.VimMode), nil
+	default:
+		return "", fmt.Errorf("unknown setting: %s", key)
+	}
+}
+
+// Set parses and validates a value and stores it in the setting. The setting
+// is left unchanged if the value is invalid.
+func (s *Settings) Set(key, value string) error {
+	switch key {
+	case "page_size":
+		n, err := parsePositiveInt(value)
+		if err != nil {
+			return fmt.Errorf("invalid page_size: %w", err)
+		}
+		s.PageSize = n
+	case "refresh_interval":
+		n, err := parsePositiveInt(value)
+		if err != nil {
+			return fmt.Errorf("invalid refresh_interval: %w", err)
+		}
+		s.RefreshInterval = n
+	case "default_format":
+		if !validFormats[value] {
+			return fmt.Errorf("invalid default_format: %s (must be table, json or yaml)", value)
+		}
+		s.DefaultFormat = value
+	case "color_scheme":
+		if value == "" {
+			return fmt.Errorf("invalid color_scheme: value cannot be empty")
+		}
+		s.ColorScheme = value
+	case "vim_mode":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid vim_mode: %s (must be true or false)", value)
+		}
+		s.VimMode = b
+	default:
+		return fmt.Errorf("unknown setting: %s", key)
+	}
+
+	return nil
+}
+
+// parsePositiveInt parses a strictly positive integer
+func parsePositiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a number", value)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("must be a positive number, got %d", n)
+	}
+	return n, nil
+}
@@ -1,19 +1,108 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/term"
 )
 
 // Logger wraps zap.SugaredLogger for structured logging
 type Logger struct {
 	*zap.SugaredLogger
+
+	// level is shared with the logger's core so SetLevel takes effect immediately
+	level zap.AtomicLevel
+}
+
+// Log formats
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Options configures a logger built by NewWithOptions
+type Options struct {
+	// Format is FormatJSON or FormatConsole. Empty selects console when logging
+	// to a terminal and JSON otherwise.
+	Format string
+
+	// File is the path of a file logs are appended to instead of stdout.
+	// Errors are also written to stderr.
+	File string
 }
 
-// New creates a new logger instance
+// New creates a new logger instance writing to stdout
 func New() *Logger {
+	logger, err := NewWithOptions(Options{})
+	if err != nil {
+		panic(err)
+	}
+	return logger
+}
+
+// NewWithFile creates a logger that appends to the file at path instead of
+// writing to stdout. Errors are also written to stderr.
+func NewWithFile(path string) (*Logger, error) {
+	return NewWithOptions(Options{File: path})
+}
+
+// NewWithOptions creates a logger with the given format and output
+func NewWithOptions(opts Options) (*Logger, error) {
+	config := newConfig()
+
+	format := opts.Format
+	if format == "" {
+		format = FormatJSON
+		if opts.File == "" && term.IsTerminal(int(os.Stdout.Fd())) {
+			format = FormatConsole
+		}
+	}
+	switch format {
+	case FormatJSON:
+	case FormatConsole:
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	default:
+		return nil, fmt.Errorf("invalid log format '%s': must be json or console", format)
+	}
+	config.Encoding = format
+
+	if opts.File == "" {
+		logger, err := config.Build()
+		if err != nil {
+			return nil, err
+		}
+		return &Logger{
+			SugaredLogger: logger.Sugar(),
+			level:         config.Level,
+		}, nil
+	}
+
+	file, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	encoder := zapcore.NewJSONEncoder(config.EncoderConfig)
+	if format == FormatConsole {
+		encoder = zapcore.NewConsoleEncoder(config.EncoderConfig)
+	}
+	core := zapcore.NewTee(
+		zapcore.NewCore(encoder, zapcore.AddSync(file), config.Level),
+		zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), zap.ErrorLevel),
+	)
+	logger := zap.New(core, zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr)))
+
+	return &Logger{
+		SugaredLogger: logger.Sugar(),
+		level:         config.Level,
+	}, nil
+}
+
+// newConfig returns the configuration shared by all loggers
+func newConfig() zap.Config {
 	config := zap.NewProductionConfig()
 	config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
 	config.OutputPaths = []string{"stdout"}
@@ -27,18 +116,11 @@ func New() *Logger {
 		config.Development = true
 	}
 
-	logger, err := config.Build()
-	if err != nil {
-		panic(err)
-	}
-
-	return &Logger{
-		SugaredLogger: logger.Sugar(),
-	}
+	return config
 }
 
-// SetLevel sets the logging level
-func (l *Logger) SetLevel(level string) {
+// SetLevel changes the logging level (debug, info, warn or error) of the logger
+func (l *Logger) SetLevel(level string) error {
 	var zapLevel zapcore.Level
 	switch level {
 	case "debug":
@@ -50,13 +132,12 @@ func (l *Logger) SetLevel(level string) {
 	case "error":
 		zapLevel = zap.ErrorLevel
 	default:
-		zapLevel = zap.InfoLevel
+		return fmt.Errorf("invalid log level '%s': must be debug, info, warn or error", level)
 	}
 
-	// Note: This is a simplified approach. In production, you might want to
-	// use zap.NewAtomicLevel() for dynamic level changes
-	config := zap.NewProductionConfig()
-	config.Level = zap.NewAtomicLevelAt(zapLevel)
-	logger, _ := config.Build()
-	l.SugaredLogger = logger.Sugar()
+	if l.level == (zap.AtomicLevel{}) {
+		return fmt.Errorf("logger level cannot be changed")
+	}
+	l.level.SetLevel(zapLevel)
+	return nil
 }
@@ -0,0 +1,258 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nipunap/kim/internal/client"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+// clusterResourceName is the only valid resource name for cluster ACLs
+const clusterResourceName = "kafka-cluster"
+
+// ACLManager manages Kafka access control lists
+type ACLManager struct {
+	client *client.Client
+	logger *logger.Logger
+}
+
+// NewACLManager creates a new ACL manager
+func NewACLManager(client *client.Client, logger *logger.Logger) *ACLManager {
+	return &ACLManager{
+		client: client,
+		logger: logger,
+	}
+}
+
+// ListACLs returns the ACLs matching the filter. A topic or group filter also
+// returns prefixed and wildcard ACLs that apply to that resource.
+func (am *ACLManager) ListACLs(ctx context.Context, filter *types.ACLFilter) ([]*types.ACLBinding, error) {
+	if !am.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	aclFilter, err := listACLFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resourceACLs, err := am.client.AdminClient.ListAcls(aclFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ACLs: %w", err)
+	}
+
+	bindings := make([]*types.ACLBinding, 0)
+	for _, resourceACL := range resourceACLs {
+		for _, acl := range resourceACL.Acls {
+			bindings = append(bindings, aclBindingFromSarama(resourceACL.Resource, acl))
+		}
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		a, b := bindings[i], bindings[j]
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		if a.ResourceName != b.ResourceName {
+			return a.ResourceName < b.ResourceName
+		}
+		if a.Principal != b.Principal {
+			return a.Principal < b.Principal
+		}
+		return a.Operation < b.Operation
+	})
+
+	return bindings, nil
+}
+
+// CreateACL creates a single ACL
+func (am *ACLManager) CreateACL(ctx context.Context, binding *types.ACLBinding) error {
+	if !am.client.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	resource, acl, err := parseACLBinding(binding)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := am.client.AdminClient.CreateACL(resource, acl); err != nil {
+		return fmt.Errorf("failed to create ACL: %w", err)
+	}
+
+	am.logger.Info("ACL created",
+		"principal", acl.Principal,
+		"resource", binding.ResourceName,
+		"operation", binding.Operation)
+	return nil
+}
+
+// DeleteACLs deletes the ACLs exactly matching the binding and returns the
+// deleted entries
+func (am *ACLManager) DeleteACLs(ctx context.Context, binding *types.ACLBinding) ([]*types.ACLBinding, error) {
+	if !am.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	resource, acl, err := parseACLBinding(binding)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := sarama.AclFilter{
+		ResourceType:              resource.ResourceType,
+		ResourceName:              &resource.ResourceName,
+		ResourcePatternTypeFilter: resource.ResourcePatternType,
+		Principal:                 &acl.Principal,
+		Host:                      &acl.Host,
+		Operation:                 acl.Operation,
+		PermissionType:            acl.PermissionType,
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	matches, err := am.client.AdminClient.DeleteACL(filter, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete ACL: %w", err)
+	}
+
+	deleted := make([]*types.ACLBinding, 0, len(matches))
+	for _, match := range matches {
+		if match.Err != sarama.ErrNoError {
+			return deleted, fmt.Errorf("failed to delete ACL: %w", match.Err)
+		}
+		deleted = append(deleted, aclBindingFromSarama(match.Resource, &match.Acl))
+	}
+
+	if len(deleted) == 0 {
+		return nil, fmt.Errorf("no matching ACL found")
+	}
+
+	am.logger.Info("ACLs deleted", "principal", acl.Principal, "count", len(deleted))
+	return deleted, nil
+}
+
+// listACLFilter converts a list filter into a sarama filter, matching
+// everything that is not constrained
+func listACLFilter(filter *types.ACLFilter) (sarama.AclFilter, error) {
+	aclFilter := sarama.AclFilter{
+		ResourceType:              sarama.AclResourceAny,
+		ResourcePatternTypeFilter: sarama.AclPatternAny,
+		Operation:                 sarama.AclOperationAny,
+		PermissionType:            sarama.AclPermissionAny,
+	}
+	if filter == nil {
+		return aclFilter, nil
+	}
+
+	if filter.Topic != "" && filter.Group != "" {
+		return aclFilter, fmt.Errorf("topic and group filters cannot be combined")
+	}
+
+	if filter.Topic != "" {
+		topic := filter.Topic
+		aclFilter.ResourceType = sarama.AclResourceTopic
+		aclFilter.ResourceName = &topic
+		aclFilter.ResourcePatternTypeFilter = sarama.AclPatternMatch
+	}
+	if filter.Group != "" {
+		group := filter.Group
+		aclFilter.ResourceType = sarama.AclResourceGroup
+		aclFilter.ResourceName = &group
+		aclFilter.ResourcePatternTypeFilter = sarama.AclPatternMatch
+	}
+	if filter.Principal != "" {
+		principal := filter.Principal
+		aclFilter.Principal = &principal
+	}
+
+	return aclFilter, nil
+}
+
+// parseACLBinding validates a binding and converts it into a sarama resource and ACL
+func parseACLBinding(binding *types.ACLBinding) (sarama.Resource, sarama.Acl, error) {
+	var (
+		resource sarama.Resource
+		acl      sarama.Acl
+	)
+
+	if binding == nil {
+		return resource, acl, fmt.Errorf("ACL cannot be nil")
+	}
+
+	if binding.Principal == "" {
+		return resource, acl, fmt.Errorf("principal is required")
+	}
+	if !strings.Contains(binding.Principal, ":") {
+		return resource, acl, fmt.Errorf("invalid principal '%s': expected the form TYPE:NAME, e.g. User:alice", binding.Principal)
+	}
+	acl.Principal = binding.Principal
+
+	acl.Host = binding.Host
+	if acl.Host == "" {
+		acl.Host = "*"
+	}
+
+	if err := resource.ResourceType.UnmarshalText([]byte(binding.ResourceType)); err != nil ||
+		resource.ResourceType == sarama.AclResourceAny || resource.ResourceType == sarama.AclResourceUnknown {
+		return resource, acl, fmt.Errorf("invalid resource type '%s': must be one of topic, group, cluster, transactionalid", binding.ResourceType)
+	}
+
+	resource.ResourceName = binding.ResourceName
+	if resource.ResourceType == sarama.AclResourceCluster && resource.ResourceName == "" {
+		resource.ResourceName = clusterResourceName
+	}
+	if resource.ResourceName == "" {
+		return resource, acl, fmt.Errorf("resource name is required")
+	}
+
+	patternType := binding.PatternType
+	if patternType == "" {
+		patternType = "literal"
+	}
+	if err := resource.ResourcePatternType.UnmarshalText([]byte(patternType)); err != nil ||
+		(resource.ResourcePatternType != sarama.AclPatternLiteral && resource.ResourcePatternType != sarama.AclPatternPrefixed) {
+		return resource, acl, fmt.Errorf("invalid pattern type '%s': must be literal or prefixed", binding.PatternType)
+	}
+
+	if err := acl.Operation.UnmarshalText([]byte(binding.Operation)); err != nil ||
+		acl.Operation == sarama.AclOperationAny || acl.Operation == sarama.AclOperationUnknown {
+		return resource, acl, fmt.Errorf("invalid operation '%s'", binding.Operation)
+	}
+
+	if err := acl.PermissionType.UnmarshalText([]byte(binding.Permission)); err != nil ||
+		(acl.PermissionType != sarama.AclPermissionAllow && acl.PermissionType != sarama.AclPermissionDeny) {
+		return resource, acl, fmt.Errorf("invalid permission '%s': must be allow or deny", binding.Permission)
+	}
+
+	return resource, acl, nil
+}
+
+// aclBindingFromSarama converts a sarama resource and ACL into an ACL binding
+func aclBindingFromSarama(resource sarama.Resource, acl *sarama.Acl) *types.ACLBinding {
+	return &types.ACLBinding{
+		ResourceType: resource.ResourceType.String(),
+		ResourceName: resource.ResourceName,
+		PatternType:  resource.ResourcePatternType.String(),
+		Principal:    acl.Principal,
+		Host:         acl.Host,
+		Operation:    acl.Operation.String(),
+		Permission:   acl.PermissionType.String(),
+	}
+}
@@ -0,0 +1,219 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+// newACLMock creates a mock with ACLs for two principals
+func newACLMock() *testutil.MockClient {
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+
+	allow := func(principal string, operation sarama.AclOperation) sarama.Acl {
+		return sarama.Acl{Principal: principal, Host: "*", Operation: operation, PermissionType: sarama.AclPermissionAllow}
+	}
+	mock.AddMockACL(sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "orders", ResourcePatternType: sarama.AclPatternLiteral},
+		allow("User:alice", sarama.AclOperationRead))
+	mock.AddMockACL(sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "ord", ResourcePatternType: sarama.AclPatternPrefixed},
+		allow("User:bob", sarama.AclOperationWrite))
+	mock.AddMockACL(sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "payments", ResourcePatternType: sarama.AclPatternLiteral},
+		allow("User:alice", sarama.AclOperationRead))
+	mock.AddMockACL(sarama.Resource{ResourceType: sarama.AclResourceGroup, ResourceName: "orders-service", ResourcePatternType: sarama.AclPatternLiteral},
+		allow("User:alice", sarama.AclOperationRead))
+
+	return mock
+}
+
+func TestACLManagerListACLs(t *testing.T) {
+	mock := newACLMock()
+	am := NewACLManager(mock.KafkaClient(), testutil.TestLogger())
+
+	acls, err := am.ListACLs(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListACLs failed: %v", err)
+	}
+	if len(acls) != 4 {
+		t.Fatalf("Expected 4 ACLs, got %d", len(acls))
+	}
+
+	// Results are sorted by resource type, then name
+	first := acls[0]
+	if first.ResourceType != "Group" || first.ResourceName != "orders-service" || first.Operation != "Read" || first.Permission != "Allow" {
+		t.Errorf("Unexpected first ACL: %+v", first)
+	}
+}
+
+func TestACLManagerListACLsFiltered(t *testing.T) {
+	mock := newACLMock()
+	am := NewACLManager(mock.KafkaClient(), testutil.TestLogger())
+
+	// A topic filter includes prefixed ACLs that apply to the topic
+	acls, err := am.ListACLs(context.Background(), &types.ACLFilter{Topic: "orders"})
+	if err != nil {
+		t.Fatalf("ListACLs failed: %v", err)
+	}
+	if len(acls) != 2 {
+		t.Fatalf("Expected 2 ACLs for topic orders, got %d: %+v", len(acls), acls)
+	}
+	if acls[0].ResourceName != "ord" || acls[0].PatternType != "Prefixed" || acls[1].ResourceName != "orders" {
+		t.Errorf("Unexpected ACLs: %+v, %+v", acls[0], acls[1])
+	}
+
+	acls, err = am.ListACLs(context.Background(), &types.ACLFilter{Principal: "User:alice", Topic: "payments"})
+	if err != nil {
+		t.Fatalf("ListACLs failed: %v", err)
+	}
+	if len(acls) != 1 || acls[0].ResourceName != "payments" {
+		t.Errorf("Expected alice's payments ACL, got %+v", acls)
+	}
+
+	acls, err = am.ListACLs(context.Background(), &types.ACLFilter{Group: "orders-service"})
+	if err != nil {
+		t.Fatalf("ListACLs failed: %v", err)
+	}
+	if len(acls) != 1 || acls[0].ResourceType != "Group" {
+		t.Errorf("Expected the orders-service group ACL, got %+v", acls)
+	}
+
+	if _, err := am.ListACLs(context.Background(), &types.ACLFilter{Topic: "orders", Group: "orders-service"}); err == nil {
+		t.Error("Combining topic and group filters should fail")
+	}
+}
+
+func TestACLManagerCreateACL(t *testing.T) {
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	am := NewACLManager(mock.KafkaClient(), testutil.TestLogger())
+
+	err := am.CreateACL(context.Background(), &types.ACLBinding{
+		ResourceType: "topic",
+		ResourceName: "orders",
+		Principal:    "User:alice",
+		Operation:    "read",
+		Permission:   "allow",
+	})
+	if err != nil {
+		t.Fatalf("CreateACL failed: %v", err)
+	}
+
+	acls := mock.MockACLs()
+	if len(acls) != 1 {
+		t.Fatalf("Expected 1 ACL, got %d", len(acls))
+	}
+	created := acls[0]
+	if created.Resource.ResourceType != sarama.AclResourceTopic || created.Resource.ResourceName != "orders" ||
+		created.Resource.ResourcePatternType != sarama.AclPatternLiteral {
+		t.Errorf("Unexpected resource: %+v", created.Resource)
+	}
+	if created.Acl.Principal != "User:alice" || created.Acl.Host != "*" ||
+		created.Acl.Operation != sarama.AclOperationRead || created.Acl.PermissionType != sarama.AclPermissionAllow {
+		t.Errorf("Unexpected ACL: %+v", created.Acl)
+	}
+}
+
+func TestACLManagerCreateACLValidation(t *testing.T) {
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	am := NewACLManager(mock.KafkaClient(), testutil.TestLogger())
+
+	valid := types.ACLBinding{ResourceType: "topic", ResourceName: "orders", Principal: "User:alice", Operation: "read", Permission: "allow"}
+
+	tests := []struct {
+		name   string
+		modify func(b *types.ACLBinding)
+	}{
+		{"missing principal", func(b *types.ACLBinding) { b.Principal = "" }},
+		{"principal without type", func(b *types.ACLBinding) { b.Principal = "alice" }},
+		{"invalid resource type", func(b *types.ACLBinding) { b.ResourceType = "queue" }},
+		{"missing resource name", func(b *types.ACLBinding) { b.ResourceName = "" }},
+		{"match pattern type", func(b *types.ACLBinding) { b.PatternType = "match" }},
+		{"any operation", func(b *types.ACLBinding) { b.Operation = "any" }},
+		{"invalid permission", func(b *types.ACLBinding) { b.Permission = "maybe" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binding := valid
+			tt.modify(&binding)
+			if err := am.CreateACL(context.Background(), &binding); err == nil {
+				t.Error("Expected CreateACL to fail")
+			}
+		})
+	}
+
+	if len(mock.MockACLs()) != 0 {
+		t.Errorf("Invalid ACLs should not be created, got %d", len(mock.MockACLs()))
+	}
+}
+
+func TestACLManagerDeleteACLs(t *testing.T) {
+	mock := newACLMock()
+	am := NewACLManager(mock.KafkaClient(), testutil.TestLogger())
+
+	binding := &types.ACLBinding{
+		ResourceType: "topic",
+		ResourceName: "orders",
+		Principal:    "User:alice",
+		Host:         "*",
+		Operation:    "read",
+		Permission:   "allow",
+	}
+
+	deleted, err := am.DeleteACLs(context.Background(), binding)
+	if err != nil {
+		t.Fatalf("DeleteACLs failed: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ResourceName != "orders" {
+		t.Errorf("Unexpected deleted ACLs: %+v", deleted)
+	}
+	if len(mock.MockACLs()) != 3 {
+		t.Errorf("Expected 3 remaining ACLs, got %d", len(mock.MockACLs()))
+	}
+
+	if _, err := am.DeleteACLs(context.Background(), binding); err == nil {
+		t.Error("Deleting a missing ACL should fail")
+	}
+}
+
+func TestACLManagerCanceledContext(t *testing.T) {
+	mock := newACLMock()
+	am := NewACLManager(mock.KafkaClient(), testutil.TestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	binding := &types.ACLBinding{
+		ResourceType: "topic",
+		ResourceName: "orders",
+		Principal:    "User:alice",
+		Host:         "*",
+		Operation:    "read",
+		Permission:   "allow",
+	}
+	operations := map[string]func() error{
+		"ListACLs": func() error {
+			_, err := am.ListACLs(ctx, &types.ACLFilter{})
+			return err
+		},
+		"CreateACL": func() error {
+			return am.CreateACL(ctx, binding)
+		},
+		"DeleteACLs": func() error {
+			_, err := am.DeleteACLs(ctx, binding)
+			return err
+		},
+	}
+	for name, operation := range operations {
+		if err := operation(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+
+	if len(mock.MockACLs()) != 4 {
+		t.Errorf("Expected the ACLs to be left alone, got %d", len(mock.MockACLs()))
+	}
+}
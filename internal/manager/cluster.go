@@ -0,0 +1,367 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/nipunap/kim/internal/client"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+// ClusterManager manages Kafka cluster-level operations
+type ClusterManager struct {
+	client *client.Client
+	logger *logger.Logger
+}
+
+// NewClusterManager creates a new cluster manager
+func NewClusterManager(client *client.Client, logger *logger.Logger) *ClusterManager {
+	return &ClusterManager{
+		client: client,
+		logger: logger,
+	}
+}
+
+// DescribeCluster returns the brokers, controller and cluster ID of the cluster
+func (cm *ClusterManager) DescribeCluster(ctx context.Context) (*types.ClusterInfo, error) {
+	if !cm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	brokers, controllerID, err := cm.client.AdminClient.DescribeCluster()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+
+	info := &types.ClusterInfo{
+		ControllerID: controllerID,
+		Brokers:      make([]*types.BrokerInfo, 0, len(brokers)),
+	}
+
+	for _, broker := range brokers {
+		host, port := splitBrokerAddr(broker.Addr())
+		info.Brokers = append(info.Brokers, &types.BrokerInfo{
+			ID:   broker.ID(),
+			Host: host,
+			Port: port,
+		})
+	}
+
+	sort.Slice(info.Brokers, func(i, j int) bool {
+		return info.Brokers[i].ID < info.Brokers[j].ID
+	})
+
+	// The cluster ID is only available from a metadata response, so it is
+	// best effort and left empty if the controller cannot be queried
+	clusterID, err := cm.fetchClusterID()
+	if err != nil {
+		cm.logger.Debug("Failed to fetch cluster ID", "error", err)
+	}
+	info.ClusterID = clusterID
+
+	return info, nil
+}
+
+// DescribeBrokerConfig returns the configuration of a broker along with the source of each value
+func (cm *ClusterManager) DescribeBrokerConfig(ctx context.Context, brokerID int32) (*types.BrokerConfig, error) {
+	if !cm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	configResource := sarama.ConfigResource{
+		Type: sarama.BrokerResource,
+		Name: strconv.Itoa(int(brokerID)),
+	}
+
+	entries, err := cm.client.AdminClient.DescribeConfig(configResource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe broker config: %w", err)
+	}
+
+	brokerConfig := &types.BrokerConfig{
+		BrokerID: brokerID,
+		Configs:  make(map[string]*types.ConfigEntry, len(entries)),
+	}
+
+	for _, entry := range entries {
+		configEntry := &types.ConfigEntry{
+			Value:     entry.Value,
+			Source:    entry.Source.String(),
+			Default:   entry.Default || entry.Source == sarama.SourceDefault,
+			ReadOnly:  entry.ReadOnly,
+			Sensitive: entry.Sensitive,
+		}
+
+		if !entry.Sensitive {
+			if formatted := cm.FormatConfigValue(entry.Name, entry.Value); formatted != entry.Value {
+				configEntry.Formatted = formatted
+			}
+		}
+
+		brokerConfig.Configs[entry.Name] = configEntry
+	}
+
+	return brokerConfig, nil
+}
+
+// Health checks the brokers, controller and partitions of all topics and
+// summarizes them in a pass/warn/fail status
+func (cm *ClusterManager) Health(ctx context.Context) (*types.ClusterHealth, error) {
+	info, err := cm.DescribeCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := NewTopicManager(cm.client, cm.logger).describeAllTopics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &types.ClusterHealth{
+		ClusterID:     info.ClusterID,
+		ControllerID:  info.ControllerID,
+		Brokers:       len(info.Brokers),
+		Topics:        len(metadata),
+		ProblemTopics: make([]*types.TopicHealth, 0),
+	}
+
+	for _, topic := range metadata {
+		if topic.Err != sarama.ErrNoError {
+			cm.logger.Warn("Failed to describe topic", "topic", topic.Name, "error", topic.Err)
+			continue
+		}
+
+		topicHealth := &types.TopicHealth{Topic: topic.Name}
+		for _, partition := range topic.Partitions {
+			health.Partitions++
+
+			// Same checks as topic describe
+			if len(partition.Isr) < len(partition.Replicas) {
+				topicHealth.UnderReplicatedPartitions = append(topicHealth.UnderReplicatedPartitions, partition.ID)
+			}
+			if len(partition.OfflineReplicas) > 0 || partition.Leader < 0 {
+				topicHealth.OfflinePartitions = append(topicHealth.OfflinePartitions, partition.ID)
+			}
+		}
+
+		health.UnderReplicatedPartitions += len(topicHealth.UnderReplicatedPartitions)
+		health.OfflinePartitions += len(topicHealth.OfflinePartitions)
+		if len(topicHealth.UnderReplicatedPartitions) > 0 || len(topicHealth.OfflinePartitions) > 0 {
+			health.ProblemTopics = append(health.ProblemTopics, topicHealth)
+		}
+	}
+
+	switch {
+	case health.OfflinePartitions > 0 || health.ControllerID < 0 || health.Brokers == 0:
+		health.Status = types.HealthFail
+	case health.UnderReplicatedPartitions > 0:
+		health.Status = types.HealthWarn
+	default:
+		health.Status = types.HealthPass
+	}
+
+	return health, nil
+}
+
+// ElectLeaders runs a preferred leader election, moving leadership of each
+// partition back to the first replica in its replica list. topicPartitions
+// maps topic names to partitions; a topic without partitions elects all of its
+// partitions and an empty map elects every partition in the cluster.
+func (cm *ClusterManager) ElectLeaders(ctx context.Context, topicPartitions map[string][]int32) (*types.LeaderElection, error) {
+	if !cm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	metadata, err := cm.electionMetadata(ctx, topicPartitions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the partitions to elect and record their leader before the election
+	targets := make(map[string][]int32)
+	leaders := make(map[string]map[int32]*types.ElectedPartition)
+	for _, topic := range metadata {
+		if topic.Err != sarama.ErrNoError {
+			return nil, fmt.Errorf("failed to describe topic '%s': %w", topic.Name, topic.Err)
+		}
+
+		partitions := make(map[int32]*types.ElectedPartition, len(topic.Partitions))
+		for _, partition := range topic.Partitions {
+			elected := &types.ElectedPartition{
+				Topic:          topic.Name,
+				Partition:      partition.ID,
+				PreviousLeader: partition.Leader,
+				Leader:         partition.Leader,
+			}
+			// A successful preferred election hands leadership to the first replica
+			if len(partition.Replicas) > 0 {
+				elected.Leader = partition.Replicas[0]
+			}
+			partitions[partition.ID] = elected
+		}
+		leaders[topic.Name] = partitions
+
+		requested := append([]int32(nil), topicPartitions[topic.Name]...)
+		if len(requested) == 0 {
+			for id := range partitions {
+				requested = append(requested, id)
+			}
+		}
+		for _, id := range requested {
+			if _, ok := partitions[id]; !ok {
+				return nil, fmt.Errorf("partition %d does not exist in topic '%s'", id, topic.Name)
+			}
+		}
+		sort.Slice(requested, func(i, j int) bool { return requested[i] < requested[j] })
+		targets[topic.Name] = requested
+	}
+
+	election := &types.LeaderElection{Changed: make([]*types.ElectedPartition, 0)}
+	if len(targets) == 0 {
+		return election, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results, err := cm.client.AdminClient.ElectLeaders(sarama.PreferredElection, targets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to elect leaders: %w", err)
+	}
+
+	topics := make([]string, 0, len(targets))
+	for topic := range targets {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		for _, id := range targets[topic] {
+			election.Partitions++
+
+			result, ok := results[topic][id]
+			if !ok || result.ErrorCode == sarama.ErrElectionNotNeeded {
+				continue
+			}
+
+			elected := leaders[topic][id]
+			if result.ErrorCode != sarama.ErrNoError {
+				elected.Leader = elected.PreviousLeader
+				elected.Error = result.ErrorCode.Error()
+				if result.ErrorMessage != nil && *result.ErrorMessage != "" {
+					elected.Error = *result.ErrorMessage
+				}
+				election.Failed = append(election.Failed, elected)
+				continue
+			}
+			election.Changed = append(election.Changed, elected)
+		}
+	}
+
+	return election, nil
+}
+
+// electionMetadata describes the topics named in topicPartitions, or every
+// topic when it is empty
+func (cm *ClusterManager) electionMetadata(ctx context.Context, topicPartitions map[string][]int32) ([]*sarama.TopicMetadata, error) {
+	if len(topicPartitions) == 0 {
+		return NewTopicManager(cm.client, cm.logger).describeAllTopics(ctx)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(topicPartitions))
+	for name := range topicPartitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metadata, err := cm.client.AdminClient.DescribeTopics(names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe topics: %w", err)
+	}
+
+	described := make(map[string]bool, len(metadata))
+	for _, topic := range metadata {
+		described[topic.Name] = true
+	}
+	for _, name := range names {
+		if !described[name] {
+			return nil, fmt.Errorf("topic '%s' does not exist", name)
+		}
+	}
+	return metadata, nil
+}
+
+// FormatConfigValue formats broker configuration values for display
+func (cm *ClusterManager) FormatConfigValue(key, value string) string {
+	switch key {
+	case "log.retention.ms", "log.roll.ms", "log.cleaner.delete.retention.ms",
+		"group.initial.rebalance.delay.ms", "replica.lag.time.max.ms":
+		return humanizeTimeMs(value)
+	case "log.retention.bytes", "log.segment.bytes", "message.max.bytes",
+		"replica.fetch.max.bytes", "socket.request.max.bytes",
+		"socket.send.buffer.bytes", "socket.receive.buffer.bytes":
+		return humanizeBytes(value)
+	case "log.cleanup.policy":
+		switch value {
+		case "delete":
+			return "Delete (messages are deleted after retention period)"
+		case "compact":
+			return "Compact (only latest messages per key are kept)"
+		case "compact,delete":
+			return "Compact and Delete"
+		default:
+			return value
+		}
+	case "auto.create.topics.enable", "delete.topic.enable",
+		"unclean.leader.election.enable", "auto.leader.rebalance.enable":
+		if value == "true" {
+			return "Enabled"
+		}
+		return "Disabled"
+	default:
+		return value
+	}
+}
+
+// fetchClusterID requests metadata from the controller to read the cluster ID
+func (cm *ClusterManager) fetchClusterID() (string, error) {
+	controller, err := cm.client.AdminClient.Controller()
+	if err != nil {
+		return "", fmt.Errorf("failed to get controller: %w", err)
+	}
+
+	metadata, err := controller.GetMetadata(&sarama.MetadataRequest{Version: 2})
+	if err != nil {
+		return "", fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	if metadata.ClusterID == nil {
+		return "", nil
+	}
+	return *metadata.ClusterID, nil
+}
+
+// splitBrokerAddr splits a broker address into host and port
+func splitBrokerAddr(addr string) (string, int32) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, -1
+	}
+
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil {
+		return host, -1
+	}
+
+	return host, int32(port)
+}
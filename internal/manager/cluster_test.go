@@ -0,0 +1,239 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+func TestClusterManagerDescribeCluster(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockBroker(2, "broker-2:9093")
+	mock.AddMockBroker(1, "broker-1:9092")
+	mock.SetControllerID(2)
+
+	cm := NewClusterManager(mock.KafkaClient(), logger)
+
+	info, err := cm.DescribeCluster(context.Background())
+	if err != nil {
+		t.Fatalf("DescribeCluster failed: %v", err)
+	}
+
+	if info.ControllerID != 2 {
+		t.Errorf("Expected controller ID 2, got %d", info.ControllerID)
+	}
+	if len(info.Brokers) != 2 {
+		t.Fatalf("Expected 2 brokers, got %d", len(info.Brokers))
+	}
+
+	// Brokers are sorted by ID
+	first := info.Brokers[0]
+	if first.ID != 1 || first.Host != "broker-1" || first.Port != 9092 {
+		t.Errorf("Unexpected first broker: %+v", first)
+	}
+	second := info.Brokers[1]
+	if second.ID != 2 || second.Host != "broker-2" || second.Port != 9093 {
+		t.Errorf("Unexpected second broker: %+v", second)
+	}
+}
+
+func TestClusterManagerDescribeClusterError(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.SetShouldFailOps(true)
+
+	cm := NewClusterManager(mock.KafkaClient(), logger)
+
+	if _, err := cm.DescribeCluster(context.Background()); err == nil {
+		t.Error("DescribeCluster should fail when the admin client fails")
+	}
+}
+
+func TestClusterManagerDescribeBrokerConfig(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockConfig(sarama.BrokerResource, "1",
+		sarama.ConfigEntry{Name: "log.retention.ms", Value: "604800000", Source: sarama.SourceStaticBroker},
+		sarama.ConfigEntry{Name: "num.io.threads", Value: "8", Default: true, Source: sarama.SourceDefault},
+		sarama.ConfigEntry{Name: "ssl.keystore.password", Sensitive: true, Source: sarama.SourceStaticBroker},
+	)
+
+	cm := NewClusterManager(mock.KafkaClient(), logger)
+
+	brokerConfig, err := cm.DescribeBrokerConfig(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("DescribeBrokerConfig failed: %v", err)
+	}
+
+	if len(brokerConfig.Configs) != 3 {
+		t.Fatalf("Expected 3 config entries, got %d", len(brokerConfig.Configs))
+	}
+
+	retention := brokerConfig.Configs["log.retention.ms"]
+	if retention.Value != "604800000" {
+		t.Errorf("Expected raw value to be kept, got %s", retention.Value)
+	}
+	if retention.Formatted != "7 days 0 hours" {
+		t.Errorf("Expected formatted retention '7 days 0 hours', got '%s'", retention.Formatted)
+	}
+	if retention.Default {
+		t.Error("Statically configured entry should be reported as overridden")
+	}
+
+	threads := brokerConfig.Configs["num.io.threads"]
+	if !threads.Default {
+		t.Error("Default entry should be reported as default")
+	}
+	if threads.Formatted != "" {
+		t.Errorf("Unformatted entry should have no formatted value, got '%s'", threads.Formatted)
+	}
+
+	if !brokerConfig.Configs["ssl.keystore.password"].Sensitive {
+		t.Error("Sensitive entry should be marked sensitive")
+	}
+
+	// Unknown brokers return no entries
+	brokerConfig, err = cm.DescribeBrokerConfig(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("DescribeBrokerConfig failed: %v", err)
+	}
+	if len(brokerConfig.Configs) != 0 {
+		t.Errorf("Expected no entries for broker 2, got %d", len(brokerConfig.Configs))
+	}
+}
+
+func TestClusterManagerHealth(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockBroker(0, "broker-0:9092")
+	mock.AddMockBroker(1, "broker-1:9092")
+	mock.AddMockTopic("orders", 3, 2)
+	mock.AddMockTopic("payments", 2, 2)
+
+	cm := NewClusterManager(mock.KafkaClient(), logger)
+
+	health, err := cm.Health(context.Background())
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if health.Status != types.HealthPass {
+		t.Errorf("Expected status pass, got %s", health.Status)
+	}
+	if health.Brokers != 2 || health.Topics != 2 || health.Partitions != 5 {
+		t.Errorf("Unexpected counts: %+v", health)
+	}
+
+	// Drop a replica of orders partition 1 out of the ISR
+	orders, _ := mock.MockTopic("orders")
+	orders.Partitions[1].Isr = []int32{0}
+
+	health, err = cm.Health(context.Background())
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if health.Status != types.HealthWarn {
+		t.Errorf("Expected status warn, got %s", health.Status)
+	}
+	if health.UnderReplicatedPartitions != 1 || health.OfflinePartitions != 0 {
+		t.Errorf("Unexpected partition counts: %+v", health)
+	}
+	if len(health.ProblemTopics) != 1 || health.ProblemTopics[0].Topic != "orders" ||
+		len(health.ProblemTopics[0].UnderReplicatedPartitions) != 1 || health.ProblemTopics[0].UnderReplicatedPartitions[0] != 1 {
+		t.Errorf("Unexpected problem topics: %+v", health.ProblemTopics)
+	}
+
+	// A partition without a leader fails the check
+	payments, _ := mock.MockTopic("payments")
+	payments.Partitions[0].Leader = -1
+
+	health, err = cm.Health(context.Background())
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if health.Status != types.HealthFail || health.OfflinePartitions != 1 {
+		t.Errorf("Expected status fail with 1 offline partition, got %+v", health)
+	}
+}
+
+func TestClusterManagerElectLeaders(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 3, 2)
+	mock.AddMockTopic("payments", 2, 2)
+
+	// orders partition 1 and payments partition 0 are led by their second replica
+	orders, _ := mock.MockTopic("orders")
+	orders.Partitions[1].Leader = 1
+	payments, _ := mock.MockTopic("payments")
+	payments.Partitions[0].Leader = 1
+
+	cm := NewClusterManager(mock.KafkaClient(), logger)
+
+	// Without partitions every partition in the cluster is elected
+	election, err := cm.ElectLeaders(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ElectLeaders failed: %v", err)
+	}
+
+	elections := mock.Elections()
+	if len(elections) != 1 {
+		t.Fatalf("Expected 1 election, got %d", len(elections))
+	}
+	if elections[0].Type != sarama.PreferredElection {
+		t.Errorf("Expected a preferred election, got %v", elections[0].Type)
+	}
+	if got := elections[0].Partitions; len(got) != 2 || len(got["orders"]) != 3 || len(got["payments"]) != 2 {
+		t.Errorf("Expected every partition to be targeted, got %v", got)
+	}
+
+	if election.Partitions != 5 {
+		t.Errorf("Expected 5 partitions, got %d", election.Partitions)
+	}
+	if len(election.Changed) != 2 {
+		t.Fatalf("Expected 2 changed partitions, got %d", len(election.Changed))
+	}
+	for i, want := range []struct {
+		topic     string
+		partition int32
+	}{{"orders", 1}, {"payments", 0}} {
+		changed := election.Changed[i]
+		if changed.Topic != want.topic || changed.Partition != want.partition ||
+			changed.PreviousLeader != 1 || changed.Leader != 0 {
+			t.Errorf("Unexpected changed partition %d: %+v", i, changed)
+		}
+	}
+
+	// Named partitions are the only ones targeted
+	orders.Partitions[2].Leader = 1
+
+	election, err = cm.ElectLeaders(context.Background(), map[string][]int32{"orders": {2}})
+	if err != nil {
+		t.Fatalf("ElectLeaders failed: %v", err)
+	}
+
+	elections = mock.Elections()
+	if got := elections[len(elections)-1].Partitions; len(got) != 1 || len(got["orders"]) != 1 || got["orders"][0] != 2 {
+		t.Errorf("Expected only orders partition 2 to be targeted, got %v", got)
+	}
+	if election.Partitions != 1 || len(election.Changed) != 1 || election.Changed[0].Partition != 2 {
+		t.Errorf("Expected orders partition 2 to change leader, got %+v", election)
+	}
+
+	// Unknown partitions are rejected before an election is sent
+	if _, err := cm.ElectLeaders(context.Background(), map[string][]int32{"orders": {7}}); err == nil {
+		t.Error("Expected an error for an unknown partition")
+	}
+	if len(mock.Elections()) != len(elections) {
+		t.Error("Expected no election for an unknown partition")
+	}
+}
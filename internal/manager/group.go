@@ -3,7 +3,10 @@ package manager
 import (
 	"context"
 	"fmt"
+	"net"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/logger"
@@ -31,12 +34,24 @@ func (gm *GroupManager) ListGroups(ctx context.Context, opts *types.ListOptions)
 	if !gm.client.IsConnected() {
 		return nil, fmt.Errorf("client not connected")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Get consumer group list
 	groupList, err := gm.client.AdminClient.ListConsumerGroups()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list consumer groups: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, state := range opts.States {
+		if _, ok := groupStates[strings.ToLower(state)]; !ok {
+			return nil, fmt.Errorf("invalid group state: %s (must be Stable, Empty, Rebalancing or Dead)", state)
+		}
+	}
 
 	// Convert to group info
 	var groups []*types.GroupInfo
@@ -45,19 +60,55 @@ func (gm *GroupManager) ListGroups(ctx context.Context, opts *types.ListOptions)
 		if opts.Pattern != "" && !matchesPattern(groupID, opts.Pattern) {
 			continue
 		}
+		if matchesAnyPattern(groupID, opts.Exclude) {
+			continue
+		}
 
 		group := &types.GroupInfo{
 			GroupID:      groupID,
 			ProtocolType: groupType,
-			State:        "Unknown", // We'll need to describe the group to get the state
+			State:        "Unknown", // Set by describeGroupSummaries
 		}
 
 		groups = append(groups, group)
 	}
 
+	// Filtering by state needs the state of every group, not just the current page
+	if len(opts.States) > 0 {
+		gm.describeGroupSummaries(groups)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		filtered := groups[:0]
+		for _, group := range groups {
+			if matchesGroupState(group.State, opts.States) {
+				filtered = append(filtered, group)
+			}
+		}
+		groups = filtered
+	}
+
+	// Sorting by lag needs the lag of every group, not just the current page
+	if opts.SortBy == "lag" {
+		for _, group := range groups {
+			offsets, err := gm.ExportOffsets(ctx, group.GroupID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate lag of group %s: %w", group.GroupID, err)
+			}
+			totalLag := offsets.TotalLag
+			group.TotalLag = &totalLag
+		}
+	}
+
 	// Sort groups
 	sort.Slice(groups, func(i, j int) bool {
 		switch opts.SortBy {
+		case "lag":
+			if opts.Order == "desc" {
+				return *groups[i].TotalLag > *groups[j].TotalLag
+			}
+			return *groups[i].TotalLag < *groups[j].TotalLag
 		case "state":
 			if opts.Order == "desc" {
 				return groups[i].State > groups[j].State
@@ -91,6 +142,14 @@ func (gm *GroupManager) ListGroups(ctx context.Context, opts *types.ListOptions)
 
 	paginatedGroups := groups[start:end]
 
+	// Fill in state and members for the groups on this page
+	if len(opts.States) == 0 {
+		gm.describeGroupSummaries(paginatedGroups)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &types.GroupList{
 		Groups: paginatedGroups,
 		Pagination: &types.Pagination{
@@ -102,17 +161,100 @@ func (gm *GroupManager) ListGroups(ctx context.Context, opts *types.ListOptions)
 	}, nil
 }
 
+// groupCoordinator looks up the broker coordinating a group
+func (gm *GroupManager) groupCoordinator(groupID string) (*types.CoordinatorInfo, error) {
+	broker, err := gm.client.Offsets.Coordinator(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	host, portStr, err := net.SplitHostPort(broker.Addr())
+	if err != nil {
+		return nil, fmt.Errorf("invalid coordinator address %s: %w", broker.Addr(), err)
+	}
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid coordinator port %s: %w", portStr, err)
+	}
+
+	return &types.CoordinatorInfo{
+		ID:   broker.ID(),
+		Host: host,
+		Port: int32(port),
+	}, nil
+}
+
+// groupStates maps the lower-cased state names accepted by ListOptions.States to
+// the consumer group states they match
+var groupStates = map[string][]string{
+	"stable":              {"Stable"},
+	"empty":               {"Empty"},
+	"dead":                {"Dead"},
+	"rebalancing":         {"PreparingRebalance", "CompletingRebalance"},
+	"preparingrebalance":  {"PreparingRebalance"},
+	"completingrebalance": {"CompletingRebalance"},
+}
+
+// matchesGroupState reports whether a group state matches one of the requested states
+func matchesGroupState(state string, states []string) bool {
+	for _, requested := range states {
+		for _, match := range groupStates[strings.ToLower(requested)] {
+			if state == match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describeGroupSummaries sets the state and member count of the given groups with a
+// single describe request. Groups are left as they are if the describe fails.
+func (gm *GroupManager) describeGroupSummaries(groups []*types.GroupInfo) {
+	if len(groups) == 0 {
+		return
+	}
+
+	groupIDs := make([]string, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.GroupID
+	}
+
+	descriptions, err := gm.client.AdminClient.DescribeConsumerGroups(groupIDs)
+	if err != nil {
+		gm.logger.Warn("Failed to describe consumer groups", "error", err)
+		return
+	}
+
+	byID := make(map[string]*sarama.GroupDescription, len(descriptions))
+	for _, desc := range descriptions {
+		byID[desc.GroupId] = desc
+	}
+
+	for _, group := range groups {
+		if desc, exists := byID[group.GroupID]; exists {
+			group.State = desc.State
+			group.MemberCount = len(desc.Members)
+		}
+	}
+}
+
 // DescribeGroup returns detailed information about a specific consumer group
 func (gm *GroupManager) DescribeGroup(ctx context.Context, groupID string) (*types.GroupDetails, error) {
 	if !gm.client.IsConnected() {
 		return nil, fmt.Errorf("client not connected")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Describe the consumer group
 	groupDescriptions, err := gm.client.AdminClient.DescribeConsumerGroups([]string{groupID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe consumer group: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	if len(groupDescriptions) == 0 {
 		return nil, fmt.Errorf("consumer group %s not found", groupID)
@@ -132,11 +274,15 @@ func (gm *GroupManager) DescribeGroup(ctx context.Context, groupID string) (*typ
 		Members:      make([]*types.MemberInfo, 0, len(groupDesc.Members)),
 	}
 
-	// Add coordinator information (simplified for now)
-	details.Coordinator = &types.CoordinatorInfo{
-		ID:   -1,
-		Host: "N/A",
-		Port: -1,
+	// The coordinator is informational, so a failed lookup does not fail the describe
+	coordinator, err := gm.groupCoordinator(groupID)
+	if err != nil {
+		gm.logger.Warn("Failed to find group coordinator", "group", groupID, "error", err)
+	} else {
+		details.Coordinator = coordinator
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// Process members
@@ -165,9 +311,22 @@ func (gm *GroupManager) DescribeGroup(ctx context.Context, groupID string) (*typ
 			}
 		}
 
+		sort.Slice(memberInfo.AssignedPartitions, func(i, j int) bool {
+			a, b := memberInfo.AssignedPartitions[i], memberInfo.AssignedPartitions[j]
+			if a.Topic != b.Topic {
+				return a.Topic < b.Topic
+			}
+			return a.Partition < b.Partition
+		})
+
 		details.Members = append(details.Members, memberInfo)
 	}
 
+	// Members come from a map, so order them for stable output
+	sort.Slice(details.Members, func(i, j int) bool {
+		return details.Members[i].MemberID < details.Members[j].MemberID
+	})
+
 	// Get consumer group offsets for lag calculation
 	if err := gm.calculateLag(ctx, details); err != nil {
 		gm.logger.Warn("Failed to calculate consumer lag", "group", groupID, "error", err)
@@ -176,37 +335,357 @@ func (gm *GroupManager) DescribeGroup(ctx context.Context, groupID string) (*typ
 	return details, nil
 }
 
-// calculateLag calculates the lag for each partition assignment
+// calculateLag fills in the committed offset, log end offset and lag of every
+// assigned partition, and sums the lag per member and per topic. Partitions
+// without a committed offset have a current offset of -1 and no lag.
 func (gm *GroupManager) calculateLag(ctx context.Context, details *types.GroupDetails) error {
-	// Simplified implementation - just set lag to 0 for now
-	// In a full implementation, you would need to:
-	// 1. Get the coordinator for the consumer group
-	// 2. Fetch consumer offsets for all assigned partitions
-	// 3. Get the latest offsets for comparison
-	// 4. Calculate the difference
+	details.LagByTopic = make(map[string]int64)
 
+	topicPartitions := make(map[string][]int32)
 	for _, member := range details.Members {
 		for _, assignment := range member.AssignedPartitions {
-			assignment.CurrentOffset = 0
-			assignment.LogEndOffset = 0
-			assignment.Lag = 0
+			topicPartitions[assignment.Topic] = append(topicPartitions[assignment.Topic], assignment.Partition)
+		}
+	}
+	if len(topicPartitions) == 0 {
+		return nil
+	}
+
+	response, err := gm.client.AdminClient.ListConsumerGroupOffsets(details.GroupID, topicPartitions)
+	if err != nil {
+		return fmt.Errorf("failed to list consumer group offsets: %w", err)
+	}
+	if response.Err != sarama.ErrNoError {
+		return fmt.Errorf("error listing offsets of consumer group %s: %v", details.GroupID, response.Err)
+	}
+
+	for _, member := range details.Members {
+		for _, assignment := range member.AssignedPartitions {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			assignment.CurrentOffset = -1
+			if block := response.GetBlock(assignment.Topic, assignment.Partition); block != nil && block.Err == sarama.ErrNoError {
+				assignment.CurrentOffset = block.Offset
+			}
+
+			assignment.LogEndOffset = -1
+			logEndOffset, err := gm.logEndOffset(assignment.Topic, assignment.Partition)
+			if err != nil {
+				gm.logger.Warn("Failed to fetch log end offset",
+					"topic", assignment.Topic, "partition", assignment.Partition, "error", err)
+				continue
+			}
+			assignment.LogEndOffset = logEndOffset
+
+			if assignment.CurrentOffset >= 0 {
+				if lag := logEndOffset - assignment.CurrentOffset; lag > 0 {
+					assignment.Lag = lag
+				}
+			}
+
+			member.TotalLag += assignment.Lag
+			details.TotalLag += assignment.Lag
+			details.LagByTopic[assignment.Topic] += assignment.Lag
+		}
+	}
+
+	return nil
+}
+
+// ExportOffsets returns the committed offsets of a consumer group for every
+// topic partition it tracks, together with the log end offset and lag
+func (gm *GroupManager) ExportOffsets(ctx context.Context, groupID string) (*types.GroupOffsets, error) {
+	if !gm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// A nil partition list fetches all committed offsets of the group
+	response, err := gm.client.AdminClient.ListConsumerGroupOffsets(groupID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer group offsets: %w", err)
+	}
+	if response.Err != sarama.ErrNoError {
+		return nil, fmt.Errorf("error listing offsets of consumer group %s: %v", groupID, response.Err)
+	}
+
+	result := &types.GroupOffsets{
+		GroupID: groupID,
+		Offsets: make([]*types.PartitionAssignment, 0),
+	}
+
+	for topic, partitions := range response.Blocks {
+		for partition, block := range partitions {
+			if block.Err != sarama.ErrNoError {
+				gm.logger.Warn("Failed to fetch committed offset",
+					"group", groupID, "topic", topic, "partition", partition, "error", block.Err)
+				continue
+			}
+
+			// Partitions without a committed offset have nothing to export
+			if block.Offset < 0 {
+				continue
+			}
+
+			offset := &types.PartitionAssignment{
+				Topic:         topic,
+				Partition:     partition,
+				CurrentOffset: block.Offset,
+				LogEndOffset:  -1,
+			}
+
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			logEndOffset, err := gm.logEndOffset(topic, partition)
+			if err != nil {
+				gm.logger.Warn("Failed to fetch log end offset",
+					"topic", topic, "partition", partition, "error", err)
+			} else {
+				offset.LogEndOffset = logEndOffset
+				if lag := logEndOffset - block.Offset; lag > 0 {
+					offset.Lag = lag
+				}
+			}
+
+			result.Offsets = append(result.Offsets, offset)
+			result.TotalLag += offset.Lag
+		}
+	}
+
+	sort.Slice(result.Offsets, func(i, j int) bool {
+		if result.Offsets[i].Topic != result.Offsets[j].Topic {
+			return result.Offsets[i].Topic < result.Offsets[j].Topic
+		}
+		return result.Offsets[i].Partition < result.Offsets[j].Partition
+	})
+
+	return result, nil
+}
+
+// logEndOffset returns the offset of the next message written to a partition
+func (gm *GroupManager) logEndOffset(topic string, partition int32) (int64, error) {
+	return gm.client.GetOffset(topic, partition, sarama.OffsetNewest)
+}
+
+// ValidateOffsets checks that offsets can be committed for a group: the group
+// must have no active members and every topic partition must exist
+func (gm *GroupManager) ValidateOffsets(ctx context.Context, groupID string, offsets []*types.PartitionAssignment) error {
+	if !gm.client.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	groupDescriptions, err := gm.client.AdminClient.DescribeConsumerGroups([]string{groupID})
+	if err != nil {
+		return fmt.Errorf("failed to describe consumer group: %w", err)
+	}
+	for _, groupDesc := range groupDescriptions {
+		if len(groupDesc.Members) > 0 {
+			return fmt.Errorf("consumer group %s has %d active members, stop them before changing offsets",
+				groupID, len(groupDesc.Members))
+		}
+	}
+
+	var topics []string
+	seen := make(map[string]bool)
+	for _, offset := range offsets {
+		if offset.CurrentOffset < 0 {
+			return fmt.Errorf("invalid offset %d for partition %d of topic %s",
+				offset.CurrentOffset, offset.Partition, offset.Topic)
+		}
+		if !seen[offset.Topic] {
+			seen[offset.Topic] = true
+			topics = append(topics, offset.Topic)
+		}
+	}
+	if len(topics) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	metadata, err := gm.client.AdminClient.DescribeTopics(topics)
+	if err != nil {
+		return fmt.Errorf("failed to describe topics: %w", err)
+	}
+
+	partitions := make(map[string]map[int32]bool, len(metadata))
+	for _, topicMeta := range metadata {
+		if topicMeta.Err != sarama.ErrNoError {
+			continue
+		}
+		partitions[topicMeta.Name] = make(map[int32]bool, len(topicMeta.Partitions))
+		for _, partition := range topicMeta.Partitions {
+			partitions[topicMeta.Name][partition.ID] = true
+		}
+	}
+
+	for _, offset := range offsets {
+		if partitions[offset.Topic] == nil {
+			return fmt.Errorf("topic %s does not exist", offset.Topic)
+		}
+		if !partitions[offset.Topic][offset.Partition] {
+			return fmt.Errorf("partition %d of topic %s does not exist", offset.Partition, offset.Topic)
 		}
-		member.TotalLag = 0
 	}
-	details.TotalLag = 0
 
 	return nil
 }
 
-// ResetGroupOffsets resets consumer group offsets for specified topics/partitions
+// ImportOffsets commits the given offsets for a consumer group, e.g. to restore
+// offsets saved by ExportOffsets. The offsets are validated with ValidateOffsets first.
+func (gm *GroupManager) ImportOffsets(ctx context.Context, groupID string, offsets []*types.PartitionAssignment) error {
+	if err := gm.ValidateOffsets(ctx, groupID, offsets); err != nil {
+		return err
+	}
+
+	commits := make(map[string]map[int32]int64)
+	for _, offset := range offsets {
+		if commits[offset.Topic] == nil {
+			commits[offset.Topic] = make(map[int32]int64)
+		}
+		commits[offset.Topic][offset.Partition] = offset.CurrentOffset
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := gm.client.Offsets.CommitOffsets(groupID, commits); err != nil {
+		return fmt.Errorf("failed to commit offsets: %w", err)
+	}
+
+	gm.logger.Info("Consumer group offsets imported", "group", groupID, "partitions", len(offsets))
+	return nil
+}
+
+// ResetGroupOffsets resets consumer group offsets for specified topics/partitions.
+// Without topics, every partition the group has committed offsets for is reset.
 func (gm *GroupManager) ResetGroupOffsets(ctx context.Context, req *types.ResetOffsetsRequest) error {
 	if !gm.client.IsConnected() {
 		return fmt.Errorf("client not connected")
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	partitions, err := gm.resetPartitions(req)
+	if err != nil {
+		return err
+	}
+	if len(partitions) == 0 {
+		return fmt.Errorf("consumer group %s has no committed offsets to reset", req.GroupID)
+	}
+
+	var offsets []*types.PartitionAssignment
+	for topic, topicPartitions := range partitions {
+		for _, partition := range topicPartitions {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			offset, err := gm.resolveResetOffset(topic, partition, req)
+			if err != nil {
+				return fmt.Errorf("failed to resolve offset for partition %d of topic %s: %w", partition, topic, err)
+			}
+
+			offsets = append(offsets, &types.PartitionAssignment{
+				Topic:         topic,
+				Partition:     partition,
+				CurrentOffset: offset,
+			})
+		}
+	}
+
+	return gm.ImportOffsets(ctx, req.GroupID, offsets)
+}
+
+// resetPartitions returns the partitions to reset: all partitions of the requested
+// topics, or the partitions the group has committed offsets for
+func (gm *GroupManager) resetPartitions(req *types.ResetOffsetsRequest) (map[string][]int32, error) {
+	partitions := make(map[string][]int32)
+
+	if len(req.Topics) > 0 {
+		metadata, err := gm.client.AdminClient.DescribeTopics(req.Topics)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe topics: %w", err)
+		}
+
+		for _, topicMeta := range metadata {
+			if topicMeta.Err != sarama.ErrNoError {
+				return nil, fmt.Errorf("error describing topic %s: %v", topicMeta.Name, topicMeta.Err)
+			}
+			for _, partition := range topicMeta.Partitions {
+				partitions[topicMeta.Name] = append(partitions[topicMeta.Name], partition.ID)
+			}
+		}
+
+		for _, topic := range req.Topics {
+			if _, exists := partitions[topic]; !exists {
+				return nil, fmt.Errorf("topic %s does not exist", topic)
+			}
+		}
+		return partitions, nil
+	}
 
-	// This would require implementing offset reset functionality
-	// For now, return an error indicating it's not implemented
-	return fmt.Errorf("reset group offsets not implemented yet")
+	response, err := gm.client.AdminClient.ListConsumerGroupOffsets(req.GroupID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer group offsets: %w", err)
+	}
+	for topic, blocks := range response.Blocks {
+		for partition, block := range blocks {
+			if block.Err == sarama.ErrNoError && block.Offset >= 0 {
+				partitions[topic] = append(partitions[topic], partition)
+			}
+		}
+	}
+	return partitions, nil
+}
+
+// resolveResetOffset returns the offset a partition is reset to. Explicit offsets
+// are clamped to the offsets available in the partition.
+func (gm *GroupManager) resolveResetOffset(topic string, partition int32, req *types.ResetOffsetsRequest) (int64, error) {
+	switch {
+	case req.ToEarliest:
+		return gm.client.GetOffset(topic, partition, sarama.OffsetOldest)
+	case req.ToLatest:
+		return gm.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	case req.ToDateTime != nil:
+		offset, err := gm.client.GetOffset(topic, partition, req.ToDateTime.UnixMilli())
+		if err != nil {
+			return 0, err
+		}
+		// No message at or after the time, so the group starts at the end
+		if offset < 0 {
+			return gm.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		}
+		return offset, nil
+	case req.ToOffset != nil:
+		earliest, err := gm.client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return 0, err
+		}
+		latest, err := gm.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, err
+		}
+		offset := *req.ToOffset
+		if offset < earliest {
+			offset = earliest
+		}
+		if offset > latest {
+			offset = latest
+		}
+		return offset, nil
+	default:
+		return 0, fmt.Errorf("no reset option specified")
+	}
 }
 
 // DeleteGroup deletes a consumer group
@@ -214,6 +693,9 @@ func (gm *GroupManager) DeleteGroup(ctx context.Context, groupID string) error {
 	if !gm.client.IsConnected() {
 		return fmt.Errorf("client not connected")
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Delete the consumer group
 	err := gm.client.AdminClient.DeleteConsumerGroup(groupID)
@@ -224,3 +706,17 @@ func (gm *GroupManager) DeleteGroup(ctx context.Context, groupID string) error {
 	gm.logger.Info("Consumer group deleted successfully", "group", groupID)
 	return nil
 }
+
+// DeleteGroups deletes several consumer groups. A failure does not stop the
+// remaining deletions; the outcome of each group is returned in order.
+func (gm *GroupManager) DeleteGroups(ctx context.Context, groupIDs []string) []*types.DeleteGroupResult {
+	results := make([]*types.DeleteGroupResult, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		result := &types.DeleteGroupResult{GroupID: groupID}
+		if err := gm.DeleteGroup(ctx, groupID); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -2,7 +2,11 @@ package manager
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/testutil"
@@ -100,3 +104,495 @@ func TestGroupManagerDeleteGroup(t *testing.T) {
 		t.Logf("DeleteGroup failed as expected in test environment: %v", err)
 	}
 }
+
+func TestGroupManagerListGroupsWithMock(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockGroup("group-b", "Stable", "consumer", 2)
+	mock.AddMockGroup("group-a", "Empty", "consumer", 0)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	groupList, err := gm.ListGroups(context.Background(), &types.ListOptions{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+
+	if len(groupList.Groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(groupList.Groups))
+	}
+
+	first, second := groupList.Groups[0], groupList.Groups[1]
+	if first.GroupID != "group-a" || first.State != "Empty" || first.MemberCount != 0 {
+		t.Errorf("Unexpected first group: %+v", first)
+	}
+	if second.GroupID != "group-b" || second.State != "Stable" || second.MemberCount != 2 {
+		t.Errorf("Unexpected second group: %+v", second)
+	}
+}
+
+func TestGroupManagerListGroupsByState(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockGroup("active-service", "Stable", "consumer", 2)
+	mock.AddMockGroup("idle-service", "Empty", "consumer", 0)
+	mock.AddMockGroup("old-service", "Empty", "consumer", 0)
+	mock.AddMockGroup("joining-service", "PreparingRebalance", "consumer", 1)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	groupList, err := gm.ListGroups(context.Background(), &types.ListOptions{Page: 1, PageSize: 1, States: []string{"empty"}})
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+
+	if groupList.Pagination.TotalItems != 2 {
+		t.Errorf("Expected 2 Empty groups in total, got %d", groupList.Pagination.TotalItems)
+	}
+	if len(groupList.Groups) != 1 || groupList.Groups[0].GroupID != "idle-service" {
+		t.Fatalf("Expected idle-service on the first page, got %+v", groupList.Groups)
+	}
+
+	groupList, err = gm.ListGroups(context.Background(), &types.ListOptions{Page: 1, PageSize: 10, States: []string{"Rebalancing"}})
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+	if len(groupList.Groups) != 1 || groupList.Groups[0].GroupID != "joining-service" {
+		t.Errorf("Expected only joining-service, got %+v", groupList.Groups)
+	}
+
+	if _, err := gm.ListGroups(context.Background(), &types.ListOptions{Page: 1, PageSize: 10, States: []string{"Sleeping"}}); err == nil {
+		t.Error("Expected an error for an unknown state")
+	}
+}
+
+func TestGroupManagerListGroupsExclude(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockGroup("orders-service", "Stable", "consumer", 1)
+	mock.AddMockGroup("payments-service", "Stable", "consumer", 1)
+	mock.AddMockGroup("console-consumer-123", "Empty", "consumer", 0)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	groupList, err := gm.ListGroups(context.Background(), &types.ListOptions{
+		Page:     1,
+		PageSize: 10,
+		Pattern:  "*-*",
+		Exclude:  []string{"console-consumer-*"},
+	})
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+
+	var ids []string
+	for _, group := range groupList.Groups {
+		ids = append(ids, group.GroupID)
+	}
+	if strings.Join(ids, ",") != "orders-service,payments-service" {
+		t.Errorf("Expected the console consumer to be excluded, got %v", ids)
+	}
+}
+
+func TestGroupManagerListGroupsSortByLag(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockGroup("caught-up", "Stable", "consumer", 1)
+	mock.AddMockGroup("far-behind", "Stable", "consumer", 1)
+	mock.AddMockGroup("behind", "Empty", "consumer", 0)
+
+	// Partition 0 has 10 messages and partition 1 has 5
+	for partition, count := range []int{10, 5} {
+		pc := mock.Consumer().AddMockPartition("orders", int32(partition))
+		for i := 0; i < count; i++ {
+			pc.SendMockMessage("", "value")
+		}
+	}
+	mock.AddMockGroupOffset("caught-up", "orders", 0, 10)
+	mock.AddMockGroupOffset("far-behind", "orders", 0, 1)
+	mock.AddMockGroupOffset("far-behind", "orders", 1, 0)
+	mock.AddMockGroupOffset("behind", "orders", 1, 3)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	groupList, err := gm.ListGroups(context.Background(), &types.ListOptions{Page: 1, PageSize: 10, SortBy: "lag", Order: "desc"})
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+
+	expected := []struct {
+		groupID string
+		lag     int64
+	}{
+		{"far-behind", 14},
+		{"behind", 2},
+		{"caught-up", 0},
+	}
+	if len(groupList.Groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %d", len(expected), len(groupList.Groups))
+	}
+	for i, want := range expected {
+		group := groupList.Groups[i]
+		if group.GroupID != want.groupID || group.TotalLag == nil || *group.TotalLag != want.lag {
+			t.Errorf("Expected %s with lag %d at position %d, got %+v", want.groupID, want.lag, i, group)
+		}
+	}
+
+	// Lag is only calculated when sorting by it
+	groupList, err = gm.ListGroups(context.Background(), &types.ListOptions{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+	for _, group := range groupList.Groups {
+		if group.TotalLag != nil {
+			t.Errorf("Expected no lag for %s without sorting by lag", group.GroupID)
+		}
+	}
+}
+
+func TestGroupManagerDescribeGroupLag(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockGroup("orders-service", "Stable", "consumer", 2)
+	mock.SetMockMemberAssignment("orders-service", "member-0", map[string][]int32{"orders": {1, 0}})
+	mock.SetMockMemberAssignment("orders-service", "member-1", map[string][]int32{"payments": {0}})
+
+	for _, tp := range []struct {
+		topic     string
+		partition int32
+		messages  int
+	}{{"orders", 0, 10}, {"orders", 1, 6}, {"payments", 0, 8}} {
+		pc := mock.Consumer().AddMockPartition(tp.topic, tp.partition)
+		for i := 0; i < tp.messages; i++ {
+			pc.SendMockMessage("", "value")
+		}
+	}
+	mock.AddMockGroupOffset("orders-service", "orders", 0, 7)
+	mock.AddMockGroupOffset("orders-service", "orders", 1, 2)
+	mock.AddMockGroupOffset("orders-service", "payments", 0, 3)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	details, err := gm.DescribeGroup(context.Background(), "orders-service")
+	if err != nil {
+		t.Fatalf("DescribeGroup failed: %v", err)
+	}
+
+	data, err := json.Marshal(details)
+	if err != nil {
+		t.Fatalf("Failed to marshal group details: %v", err)
+	}
+
+	var decoded types.GroupDetails
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal group details: %v", err)
+	}
+
+	if len(decoded.Members) != 2 {
+		t.Fatalf("Expected 2 members, got %d", len(decoded.Members))
+	}
+
+	expected := map[string][]types.PartitionAssignment{
+		"member-0": {
+			{Topic: "orders", Partition: 0, CurrentOffset: 7, LogEndOffset: 10, Lag: 3},
+			{Topic: "orders", Partition: 1, CurrentOffset: 2, LogEndOffset: 6, Lag: 4},
+		},
+		"member-1": {
+			{Topic: "payments", Partition: 0, CurrentOffset: 3, LogEndOffset: 8, Lag: 5},
+		},
+	}
+	for _, member := range decoded.Members {
+		want := expected[member.MemberID]
+		if len(member.AssignedPartitions) != len(want) {
+			t.Fatalf("Expected %d assignments for %s, got %d", len(want), member.MemberID, len(member.AssignedPartitions))
+		}
+		for i, assignment := range member.AssignedPartitions {
+			if *assignment != want[i] {
+				t.Errorf("Unexpected assignment %d of %s: %+v", i, member.MemberID, *assignment)
+			}
+		}
+	}
+	if decoded.Members[0].TotalLag != 7 || decoded.Members[1].TotalLag != 5 {
+		t.Errorf("Unexpected member lag: %d and %d", decoded.Members[0].TotalLag, decoded.Members[1].TotalLag)
+	}
+
+	if decoded.TotalLag != 12 {
+		t.Errorf("Expected total lag 12, got %d", decoded.TotalLag)
+	}
+	if decoded.LagByTopic["orders"] != 7 || decoded.LagByTopic["payments"] != 5 {
+		t.Errorf("Unexpected lag by topic: %v", decoded.LagByTopic)
+	}
+}
+
+func TestGroupManagerDescribeGroupCoordinator(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockBroker(1, "kafka-1.example.com:9092")
+	mock.AddMockBroker(2, "kafka-2.example.com:9093")
+	mock.AddMockGroup("orders-service", "Stable", "consumer", 1)
+	mock.AddMockGroup("billing-service", "Empty", "consumer", 0)
+	mock.SetMockCoordinator("orders-service", 2)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	details, err := gm.DescribeGroup(context.Background(), "orders-service")
+	if err != nil {
+		t.Fatalf("DescribeGroup failed: %v", err)
+	}
+
+	want := types.CoordinatorInfo{ID: 2, Host: "kafka-2.example.com", Port: 9093}
+	if details.Coordinator == nil || *details.Coordinator != want {
+		t.Errorf("Expected coordinator %+v, got %+v", want, details.Coordinator)
+	}
+
+	// A failed lookup leaves the coordinator unset rather than failing the describe
+	details, err = gm.DescribeGroup(context.Background(), "billing-service")
+	if err != nil {
+		t.Fatalf("DescribeGroup failed: %v", err)
+	}
+	if details.Coordinator != nil {
+		t.Errorf("Expected no coordinator, got %+v", details.Coordinator)
+	}
+}
+
+func TestGroupManagerExportOffsets(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockGroupOffset("orders-service", "orders", 1, 4)
+	mock.AddMockGroupOffset("orders-service", "orders", 0, 2)
+	mock.AddMockGroupOffset("other-service", "orders", 0, 1)
+
+	// Log end offsets come from the partition logs
+	for partition, count := range []int{5, 4} {
+		pc := mock.Consumer().AddMockPartition("orders", int32(partition))
+		for i := 0; i < count; i++ {
+			pc.SendMockMessage("", "value")
+		}
+	}
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	offsets, err := gm.ExportOffsets(context.Background(), "orders-service")
+	if err != nil {
+		t.Fatalf("ExportOffsets failed: %v", err)
+	}
+
+	if offsets.GroupID != "orders-service" || len(offsets.Offsets) != 2 {
+		t.Fatalf("Expected 2 offsets for orders-service, got %+v", offsets)
+	}
+
+	first, second := offsets.Offsets[0], offsets.Offsets[1]
+	if first.Partition != 0 || first.CurrentOffset != 2 || first.LogEndOffset != 5 || first.Lag != 3 {
+		t.Errorf("Unexpected offset for partition 0: %+v", first)
+	}
+	if second.Partition != 1 || second.CurrentOffset != 4 || second.LogEndOffset != 4 || second.Lag != 0 {
+		t.Errorf("Unexpected offset for partition 1: %+v", second)
+	}
+	if offsets.TotalLag != 3 {
+		t.Errorf("Expected total lag 3, got %d", offsets.TotalLag)
+	}
+}
+
+func TestGroupManagerImportOffsets(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 2, 1)
+	mock.AddMockGroup("orders-service", "Empty", "consumer", 0)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	offsets := []*types.PartitionAssignment{
+		{Topic: "orders", Partition: 0, CurrentOffset: 10},
+		{Topic: "orders", Partition: 1, CurrentOffset: 20},
+	}
+	if err := gm.ImportOffsets(context.Background(), "orders-service", offsets); err != nil {
+		t.Fatalf("ImportOffsets failed: %v", err)
+	}
+
+	commits := mock.Commits()
+	if len(commits) != 1 {
+		t.Fatalf("Expected 1 commit, got %d", len(commits))
+	}
+	if commits[0].Group != "orders-service" {
+		t.Errorf("Expected commit for orders-service, got %s", commits[0].Group)
+	}
+	if got := commits[0].Offsets["orders"]; got[0] != 10 || got[1] != 20 || len(got) != 2 {
+		t.Errorf("Unexpected committed offsets: %v", commits[0].Offsets)
+	}
+}
+
+func TestGroupManagerImportOffsetsValidation(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 2, 1)
+	mock.AddMockGroup("active-service", "Stable", "consumer", 2)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	tests := []struct {
+		name    string
+		group   string
+		offsets []*types.PartitionAssignment
+	}{
+		{"active members", "active-service", []*types.PartitionAssignment{{Topic: "orders", Partition: 0, CurrentOffset: 1}}},
+		{"unknown topic", "new-service", []*types.PartitionAssignment{{Topic: "missing", Partition: 0, CurrentOffset: 1}}},
+		{"unknown partition", "new-service", []*types.PartitionAssignment{{Topic: "orders", Partition: 5, CurrentOffset: 1}}},
+		{"negative offset", "new-service", []*types.PartitionAssignment{{Topic: "orders", Partition: 0, CurrentOffset: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := gm.ImportOffsets(context.Background(), tt.group, tt.offsets); err == nil {
+				t.Error("ImportOffsets should fail")
+			}
+		})
+	}
+
+	if len(mock.Commits()) != 0 {
+		t.Error("No offsets should be committed when validation fails")
+	}
+}
+
+func TestGroupManagerResetGroupOffsets(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockGroupOffset("orders-service", "orders", 0, 1)
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	pc := mock.Consumer().AddMockPartition("orders", 0)
+	for i := 0; i < 5; i++ {
+		pc.SendMockMessageAt("", "value", start.Add(time.Duration(i)*time.Hour))
+	}
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	committed := func() int64 {
+		t.Helper()
+		offsets, err := gm.ExportOffsets(context.Background(), "orders-service")
+		if err != nil || len(offsets.Offsets) != 1 {
+			t.Fatalf("ExportOffsets failed: %v", err)
+		}
+		return offsets.Offsets[0].CurrentOffset
+	}
+
+	toTime := start.Add(90 * time.Minute)
+	toOffset := int64(100)
+	tests := []struct {
+		name string
+		req  *types.ResetOffsetsRequest
+		want int64
+	}{
+		{"latest", &types.ResetOffsetsRequest{ToLatest: true}, 5},
+		{"earliest", &types.ResetOffsetsRequest{ToEarliest: true}, 0},
+		{"datetime", &types.ResetOffsetsRequest{ToDateTime: &toTime}, 2},
+		{"offset clamped to latest", &types.ResetOffsetsRequest{ToOffset: &toOffset}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.GroupID = "orders-service"
+			if err := gm.ResetGroupOffsets(context.Background(), tt.req); err != nil {
+				t.Fatalf("ResetGroupOffsets failed: %v", err)
+			}
+			if got := committed(); got != tt.want {
+				t.Errorf("Expected committed offset %d, got %d", tt.want, got)
+			}
+		})
+	}
+
+	// A time after the last message resets to the end of the partition
+	mock.AddMockGroupOffset("orders-service", "orders", 0, 0)
+	late := start.Add(24 * time.Hour)
+	if err := gm.ResetGroupOffsets(context.Background(), &types.ResetOffsetsRequest{GroupID: "orders-service", ToDateTime: &late}); err != nil {
+		t.Fatalf("ResetGroupOffsets failed: %v", err)
+	}
+	if got := committed(); got != 5 {
+		t.Errorf("Expected committed offset 5 for a time after the last message, got %d", got)
+	}
+}
+
+func TestGroupManagerDeleteGroups(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockGroup("idle-service", "Empty", "consumer", 0)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	results := gm.DeleteGroups(context.Background(), []string{"missing-service", "idle-service"})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].GroupID != "missing-service" || results[0].Error == "" {
+		t.Errorf("Expected a failure for missing-service, got %+v", results[0])
+	}
+	if results[1].GroupID != "idle-service" || results[1].Error != "" {
+		t.Errorf("Expected idle-service to be deleted, got %+v", results[1])
+	}
+	if _, exists := mock.MockGroup("idle-service"); exists {
+		t.Error("idle-service should be deleted despite the earlier failure")
+	}
+}
+
+func TestGroupManagerCanceledContext(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockGroup("billing", "Empty", "consumer", 0)
+	mock.AddMockGroupOffset("billing", "orders", 0, 5)
+
+	gm := NewGroupManager(mock.KafkaClient(), logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	offsets := []*types.PartitionAssignment{{Topic: "orders", Partition: 0, CurrentOffset: 1}}
+	operations := map[string]func() error{
+		"ListGroups": func() error {
+			_, err := gm.ListGroups(ctx, &types.ListOptions{Page: 1, PageSize: 10})
+			return err
+		},
+		"DescribeGroup": func() error {
+			_, err := gm.DescribeGroup(ctx, "billing")
+			return err
+		},
+		"ExportOffsets": func() error {
+			_, err := gm.ExportOffsets(ctx, "billing")
+			return err
+		},
+		"ImportOffsets": func() error {
+			return gm.ImportOffsets(ctx, "billing", offsets)
+		},
+		"ResetGroupOffsets": func() error {
+			return gm.ResetGroupOffsets(ctx, &types.ResetOffsetsRequest{GroupID: "billing", ToEarliest: true})
+		},
+		"DeleteGroup": func() error {
+			return gm.DeleteGroup(ctx, "billing")
+		},
+	}
+	for name, operation := range operations {
+		if err := operation(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+
+	exported, err := gm.ExportOffsets(context.Background(), "billing")
+	if err != nil {
+		t.Fatalf("ExportOffsets failed: %v", err)
+	}
+	if len(exported.Offsets) != 1 || exported.Offsets[0].CurrentOffset != 5 {
+		t.Errorf("Expected the committed offset to be unchanged, got %+v", exported.Offsets)
+	}
+}
@@ -2,36 +2,119 @@ package manager
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/internal/metrics"
+	"github.com/nipunap/kim/internal/serde"
 	"github.com/nipunap/kim/pkg/types"
 
 	"github.com/IBM/sarama"
 )
 
+const (
+	// defaultMessageLimit is the number of messages read when no limit is given
+	defaultMessageLimit = 100
+
+	// messageFetchTimeout bounds how long GetTopicMessages waits for messages
+	messageFetchTimeout = 5 * time.Second
+)
+
+// Encodings for rendering and parsing binary message values
+const (
+	ValueEncodingRaw    = "raw"
+	ValueEncodingBase64 = "base64"
+	ValueEncodingHex    = "hex"
+)
+
+// Acknowledgement levels a produced message can wait for
+const (
+	AcksNone   = "none"
+	AcksLeader = "leader"
+	AcksAll    = "all"
+)
+
+// Compression codecs produced messages can be compressed with
+const (
+	CompressionNone   = "none"
+	CompressionGzip   = "gzip"
+	CompressionSnappy = "snappy"
+	CompressionLZ4    = "lz4"
+	CompressionZstd   = "zstd"
+)
+
+// Headers added to messages produced to a dead letter topic
+const (
+	DeadLetterHeaderError     = "kim.dlq.error"
+	DeadLetterHeaderTopic     = "kim.dlq.topic"
+	DeadLetterHeaderPartition = "kim.dlq.partition"
+	DeadLetterHeaderOffset    = "kim.dlq.offset"
+)
+
 // MessageManager manages Kafka message operations
 type MessageManager struct {
-	client    *client.Client
-	logger    *logger.Logger
-	consumers map[string]*ConsumerSession
-	mutex     sync.RWMutex
+	client          *client.Client
+	logger          *logger.Logger
+	consumers       map[string]*ConsumerSession
+	deserializer    serde.Deserializer
+	keyDeserializer serde.Deserializer
+	metrics         *metrics.ConsumerMetrics
+	deadLetterTopic string
+	mutex           sync.RWMutex
 }
 
-// ConsumerSession represents an active consumer session
+// ConsumerSession represents an active consumer session over one or more partitions
 type ConsumerSession struct {
-	Consumer      sarama.PartitionConsumer
+	Consumers     []sarama.PartitionConsumer
 	Topic         string
-	Partition     int32
+	Partitions    []int32
 	GroupID       string
 	Messages      chan *types.Message
 	Errors        chan error
 	Stop          chan struct{}
 	FromBeginning bool
+	ValueEncoding string
+	Until         *time.Time
+	endOffsets    []int64 // per consumer, the offset it ends at, or -1 for none
+	key           string
+	done          chan struct{} // closed once all partition consumers have finished
+	stopOnce      sync.Once
+	closeOnce     sync.Once
+}
+
+// signalStop asks the session's partition consumers to stop. It is safe to call
+// more than once; the consuming goroutine owns all other cleanup.
+func (s *ConsumerSession) signalStop() {
+	s.stopOnce.Do(func() {
+		close(s.Stop)
+	})
+}
+
+// stopping reports whether the session has been asked to stop
+func (s *ConsumerSession) stopping() bool {
+	select {
+	case <-s.Stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeChannels closes the Messages and Errors channels exactly once
+func (s *ConsumerSession) closeChannels() {
+	s.closeOnce.Do(func() {
+		close(s.Messages)
+		close(s.Errors)
+	})
 }
 
 // NewMessageManager creates a new message manager
@@ -43,16 +126,525 @@ func NewMessageManager(client *client.Client, logger *logger.Logger) *MessageMan
 	}
 }
 
+// SetDeserializer sets the deserializer used to decode consumed message values
+func (mm *MessageManager) SetDeserializer(deserializer serde.Deserializer) {
+	mm.deserializer = deserializer
+}
+
+// SetKeyDeserializer sets the deserializer used to decode consumed message keys
+func (mm *MessageManager) SetKeyDeserializer(deserializer serde.Deserializer) {
+	mm.keyDeserializer = deserializer
+}
+
+// SetDeadLetterTopic sets the topic consumed messages that cannot be deserialized
+// are produced to. Empty disables dead lettering.
+func (mm *MessageManager) SetDeadLetterTopic(topic string) {
+	mm.deadLetterTopic = topic
+}
+
+// SetMetrics sets the metrics that consumed messages are recorded in
+func (mm *MessageManager) SetMetrics(consumerMetrics *metrics.ConsumerMetrics) {
+	mm.metrics = consumerMetrics
+}
+
 // ProduceMessage produces a message to a topic
 func (mm *MessageManager) ProduceMessage(ctx context.Context, req *types.ProduceRequest) (*types.ProduceResponse, error) {
 	if !mm.client.IsConnected() {
 		return nil, fmt.Errorf("client not connected")
 	}
 
+	if req.Tombstone && req.Key == "" {
+		return nil, fmt.Errorf("tombstone messages require a key")
+	}
+
 	// Create the message
+	msg, err := newProducerMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	producer, release, err := mm.producerFor(req.Acks, req.Compression)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Send the message
+	partition, offset, err := producer.SendMessage(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to produce message: %w", err)
+	}
+
+	mm.logger.Info("Message produced successfully",
+		"topic", req.Topic, "partition", partition, "offset", offset)
+
+	return &types.ProduceResponse{
+		Topic:     req.Topic,
+		Partition: partition,
+		Offset:    offset,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// ProduceBatch produces messages to a topic in order over the client's producer.
+// Every message is attempted; the number produced and the first error are returned.
+func (mm *MessageManager) ProduceBatch(ctx context.Context, topic string, reqs []types.ProduceRequest) (int, error) {
+	if !mm.client.IsConnected() {
+		return 0, fmt.Errorf("client not connected")
+	}
+
+	produced := 0
+	var firstErr error
+
+	// Messages with the same settings share a producer
+	type producerKey struct{ acks, compression string }
+	producers := make(map[producerKey]sarama.SyncProducer)
+	defer func() {
+		for _, producer := range producers {
+			if producer != mm.client.Producer {
+				if err := producer.Close(); err != nil {
+					mm.logger.Warn("Failed to close producer", "error", err)
+				}
+			}
+		}
+	}()
+
+	for i := range reqs {
+		if err := ctx.Err(); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			break
+		}
+
+		req := reqs[i]
+		req.Topic = topic
+
+		if req.Tombstone && req.Key == "" {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to produce message %d: tombstone messages require a key", i+1)
+			}
+			continue
+		}
+
+		msg, err := newProducerMessage(&req)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to produce message %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		key := producerKey{req.Acks, req.Compression}
+		producer, ok := producers[key]
+		if !ok {
+			producer, _, err = mm.producerFor(req.Acks, req.Compression)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to produce message %d: %w", i+1, err)
+				}
+				continue
+			}
+			producers[key] = producer
+		}
+
+		if _, _, err := producer.SendMessage(msg); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to produce message %d: %w", i+1, err)
+			}
+			continue
+		}
+		produced++
+	}
+
+	mm.logger.Info("Batch produced",
+		"topic", topic, "produced", produced, "total", len(reqs))
+
+	return produced, firstErr
+}
+
+// PartitionForKey returns the partition a message with the given key would be
+// produced to, using the client's partitioner over the topic's partition count.
+// Nothing is produced.
+func (mm *MessageManager) PartitionForKey(ctx context.Context, topic, key string) (int32, error) {
+	if !mm.client.IsConnected() {
+		return 0, fmt.Errorf("client not connected")
+	}
+	if key == "" {
+		return 0, fmt.Errorf("a key is required to compute its partition")
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	metadata, err := mm.client.AdminClient.DescribeTopics([]string{topic})
+	if err != nil {
+		return 0, fmt.Errorf("failed to describe topic: %w", err)
+	}
+	if len(metadata) == 0 || metadata[0].Err == sarama.ErrUnknownTopicOrPartition {
+		return 0, fmt.Errorf("topic %s not found", topic)
+	}
+	if metadata[0].Err != sarama.ErrNoError {
+		return 0, fmt.Errorf("error describing topic %s: %v", topic, metadata[0].Err)
+	}
+	numPartitions := int32(len(metadata[0].Partitions))
+	if numPartitions == 0 {
+		return 0, fmt.Errorf("topic %s has no partitions", topic)
+	}
+
+	partitioner := mm.client.Config.Producer.Partitioner(topic)
+	msg := &sarama.ProducerMessage{Topic: topic, Key: sarama.StringEncoder(key)}
+	partition, err := partitioner.Partition(msg, numPartitions)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute partition: %w", err)
+	}
+	return partition, nil
+}
+
+// Replay copies messages from req.Source to req.Destination, preserving their
+// keys, values, headers and timestamps. With FromBeginning the messages in the
+// source topic when the replay starts are copied and Replay returns once all of
+// them have been; otherwise new messages are copied until the context is done.
+// The returned result counts the messages copied, also when an error is returned.
+func (mm *MessageManager) Replay(ctx context.Context, req *types.ReplayRequest) (*types.ReplayResult, error) {
+	if !mm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+	if req.Source == "" || req.Destination == "" {
+		return nil, fmt.Errorf("source and destination topics are required")
+	}
+	if req.Source == req.Destination {
+		return nil, fmt.Errorf("source and destination topics must differ")
+	}
+	if req.Max < 0 {
+		return nil, fmt.Errorf("max must not be negative")
+	}
+
+	partitions, err := mm.client.Consumer.Partitions(req.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions: %w", err)
+	}
+	if len(partitions) == 0 {
+		return nil, fmt.Errorf("topic %s has no partitions", req.Source)
+	}
+
+	producer, release, err := mm.replayProducer(req, partitions)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// The partition consumers are temporary and stop when the replay returns
+	messages := make(chan *sarama.ConsumerMessage)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, partition := range partitions {
+		start, end, err := mm.replayRange(req.Source, partition, req.FromBeginning)
+		if err != nil {
+			close(stop)
+			wg.Wait()
+			return nil, err
+		}
+		if end >= 0 && start >= end {
+			continue
+		}
+
+		pc, err := mm.client.Consumer.ConsumePartition(req.Source, partition, start)
+		if err != nil {
+			close(stop)
+			wg.Wait()
+			return nil, fmt.Errorf("failed to create partition consumer for partition %d: %w", partition, err)
+		}
+		wg.Add(1)
+		go func(end int64) {
+			defer wg.Done()
+			mm.forwardPartition(pc, end, messages, stop)
+		}(end)
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	defer func() {
+		close(stop)
+		<-finished
+	}()
+
+	result := &types.ReplayResult{
+		Source:      req.Source,
+		Destination: req.Destination,
+	}
+	defer func() {
+		mm.logger.Info("Messages replayed",
+			"source", req.Source, "destination", req.Destination, "count", result.Replayed)
+	}()
+
+	for req.Max == 0 || result.Replayed < req.Max {
+		select {
+		case msg := <-messages:
+			if _, _, err := producer.SendMessage(replayMessage(msg, req)); err != nil {
+				return result, fmt.Errorf("failed to replay message at partition %d offset %d: %w", msg.Partition, msg.Offset, err)
+			}
+			result.Replayed++
+
+		case <-finished:
+			return result, nil
+
+		case <-ctx.Done():
+			// Following new messages only ends when the caller cancels it; a
+			// deadline is reported like any other interruption
+			if !req.FromBeginning && errors.Is(ctx.Err(), context.Canceled) {
+				return result, nil
+			}
+			return result, ctx.Err()
+		}
+	}
+
+	return result, nil
+}
+
+// replayProducer returns the producer a replay sends messages with. Preserving
+// partitions needs a dedicated producer that honours the partition of each message.
+func (mm *MessageManager) replayProducer(req *types.ReplayRequest, partitions []int32) (sarama.SyncProducer, func(), error) {
+	if !req.PreservePartitions {
+		return mm.client.Producer, func() {}, nil
+	}
+
+	metadata, err := mm.client.AdminClient.DescribeTopics([]string{req.Destination})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to describe topic: %w", err)
+	}
+	if len(metadata) == 0 || metadata[0].Err == sarama.ErrUnknownTopicOrPartition {
+		return nil, nil, fmt.Errorf("topic %s not found", req.Destination)
+	}
+	for _, partition := range partitions {
+		if int(partition) >= len(metadata[0].Partitions) {
+			return nil, nil, fmt.Errorf("topic %s has %d partitions, partition %d cannot be preserved",
+				req.Destination, len(metadata[0].Partitions), partition)
+		}
+	}
+
+	producer, err := mm.client.NewManualProducer()
+	if err != nil {
+		return nil, nil, err
+	}
+	release := func() {
+		if err := producer.Close(); err != nil {
+			mm.logger.Warn("Failed to close producer", "error", err)
+		}
+	}
+	return producer, release, nil
+}
+
+// replayRange returns the offset a partition is replayed from and the offset it
+// ends at, or -1 if the replay follows new messages
+func (mm *MessageManager) replayRange(topic string, partition int32, fromBeginning bool) (int64, int64, error) {
+	if !fromBeginning {
+		return sarama.OffsetNewest, -1, nil
+	}
+
+	oldest, err := mm.client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get oldest offset for partition %d: %w", partition, err)
+	}
+	newest, err := mm.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get newest offset for partition %d: %w", partition, err)
+	}
+	return oldest, newest, nil
+}
+
+// partitionEndIdle is how long a partition consumed up to an end offset waits for
+// a message before checking whether only transaction markers are left
+var partitionEndIdle = time.Second
+
+// newEndTimer returns a timer firing after partitionEndIdle and its channel for a
+// partition consumed up to end. Without an end it returns a nil timer and channel.
+func newEndTimer(end int64) (*time.Timer, <-chan time.Time) {
+	if end < 0 {
+		return nil, nil
+	}
+	timer := time.NewTimer(partitionEndIdle)
+	return timer, timer.C
+}
+
+// partitionDrained reports whether an idle partition consumer has nothing left to
+// deliver before end. Transaction markers take offsets but are never delivered, so
+// the last message of a transactional partition can come well before end; once the
+// broker reports no message at or past end and none is buffered, only markers are left.
+func partitionDrained(pc sarama.PartitionConsumer, end int64) bool {
+	highWaterMark := pc.HighWaterMarkOffset()
+	return highWaterMark > 0 && highWaterMark <= end && len(pc.Messages()) == 0
+}
+
+// forwardPartition sends the messages of a partition consumer to out until the
+// partition reaches end, if it is not negative, or stop is closed
+func (mm *MessageManager) forwardPartition(pc sarama.PartitionConsumer, end int64, out chan<- *sarama.ConsumerMessage, stop <-chan struct{}) {
+	defer pc.Close()
+
+	idle, idleC := newEndTimer(end)
+	if idle != nil {
+		defer idle.Stop()
+	}
+
+	for {
+		select {
+		case msg := <-pc.Messages():
+			if msg == nil {
+				return
+			}
+			if end >= 0 && msg.Offset >= end {
+				return
+			}
+
+			select {
+			case out <- msg:
+			case <-stop:
+				return
+			}
+
+			if end >= 0 && msg.Offset+1 >= end {
+				return
+			}
+			if idle != nil {
+				idle.Reset(partitionEndIdle)
+			}
+
+		case <-idleC:
+			if partitionDrained(pc, end) {
+				return
+			}
+			idle.Reset(partitionEndIdle)
+
+		case err := <-pc.Errors():
+			if err == nil {
+				return
+			}
+			mm.logger.Warn("Consumer error during replay", "error", err)
+
+		case <-stop:
+			return
+		}
+	}
+}
+
+// replayMessage converts a consumed message into the message produced by a replay
+func replayMessage(msg *sarama.ConsumerMessage, req *types.ReplayRequest) *sarama.ProducerMessage {
+	out := &sarama.ProducerMessage{
+		Topic:     req.Destination,
+		Timestamp: msg.Timestamp,
+	}
+
+	// Nil keys and values stay nil so tombstones are replayed as tombstones
+	if msg.Key != nil {
+		out.Key = sarama.ByteEncoder(msg.Key)
+	}
+	if msg.Value != nil {
+		out.Value = sarama.ByteEncoder(msg.Value)
+	}
+
+	if len(msg.Headers) > 0 {
+		out.Headers = make([]sarama.RecordHeader, 0, len(msg.Headers))
+		for _, header := range msg.Headers {
+			if header != nil {
+				out.Headers = append(out.Headers, *header)
+			}
+		}
+	}
+
+	if req.PreservePartitions {
+		out.Partition = msg.Partition
+	}
+
+	return out
+}
+
+// producerFor returns a producer that waits for the given acknowledgements and
+// compresses with the given codec. The client's producer is reused when it
+// already matches; otherwise a dedicated producer is created and closed by the
+// returned release function.
+func (mm *MessageManager) producerFor(acks, compression string) (sarama.SyncProducer, func(), error) {
+	required, err := parseRequiredAcks(acks)
+	if err != nil {
+		return nil, nil, err
+	}
+	codec, err := parseCompression(compression)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := mm.client.Config.Producer
+	if acks == "" {
+		required = config.RequiredAcks
+	}
+	if compression == "" {
+		codec = config.Compression
+	}
+	if required == config.RequiredAcks && codec == config.Compression {
+		return mm.client.Producer, func() {}, nil
+	}
+
+	producer, err := mm.client.NewProducer(required, codec)
+	if err != nil {
+		return nil, nil, err
+	}
+	release := func() {
+		if err := producer.Close(); err != nil {
+			mm.logger.Warn("Failed to close producer", "error", err)
+		}
+	}
+	return producer, release, nil
+}
+
+// parseRequiredAcks maps an acks level to sarama's required acks. Empty means
+// the client's default of waiting for all in-sync replicas.
+func parseRequiredAcks(acks string) (sarama.RequiredAcks, error) {
+	switch acks {
+	case AcksNone:
+		return sarama.NoResponse, nil
+	case AcksLeader:
+		return sarama.WaitForLocal, nil
+	case "", AcksAll:
+		return sarama.WaitForAll, nil
+	default:
+		return 0, fmt.Errorf("unsupported acks: %s (must be none, leader or all)", acks)
+	}
+}
+
+// parseCompression maps a compression name to sarama's codec. Empty means none.
+func parseCompression(compression string) (sarama.CompressionCodec, error) {
+	switch compression {
+	case "", CompressionNone:
+		return sarama.CompressionNone, nil
+	case CompressionGzip:
+		return sarama.CompressionGZIP, nil
+	case CompressionSnappy:
+		return sarama.CompressionSnappy, nil
+	case CompressionLZ4:
+		return sarama.CompressionLZ4, nil
+	case CompressionZstd:
+		return sarama.CompressionZSTD, nil
+	default:
+		return 0, fmt.Errorf("unsupported compression: %s (must be none, gzip, snappy, lz4 or zstd)", compression)
+	}
+}
+
+// newProducerMessage converts a produce request into a sarama producer message,
+// decoding the value from the request's value encoding
+func newProducerMessage(req *types.ProduceRequest) (*sarama.ProducerMessage, error) {
 	msg := &sarama.ProducerMessage{
 		Topic: req.Topic,
-		Value: sarama.StringEncoder(req.Value),
+	}
+
+	// Tombstones carry a nil value so compacted topics drop the key
+	if !req.Tombstone {
+		value, err := decodeValue(req.Value, req.ValueEncoding)
+		if err != nil {
+			return nil, err
+		}
+		msg.Value = sarama.ByteEncoder(value)
 	}
 
 	// Add key if provided
@@ -76,110 +668,263 @@ func (mm *MessageManager) ProduceMessage(ctx context.Context, req *types.Produce
 		}
 	}
 
-	// Send the message
-	partition, offset, err := mm.client.Producer.SendMessage(msg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to produce message: %w", err)
-	}
-
-	mm.logger.Info("Message produced successfully",
-		"topic", req.Topic, "partition", partition, "offset", offset)
-
-	return &types.ProduceResponse{
-		Topic:     req.Topic,
-		Partition: partition,
-		Offset:    offset,
-		Timestamp: time.Now(),
-	}, nil
+	return msg, nil
 }
 
-// StartConsumer starts consuming messages from a topic
+// StartConsumer starts consuming messages from a topic. A partition consumer is started
+// for every requested partition and their messages are merged into the returned channels.
 func (mm *MessageManager) StartConsumer(ctx context.Context, req *types.ConsumeRequest) (<-chan *types.Message, <-chan error, error) {
 	if !mm.client.IsConnected() {
 		return nil, nil, fmt.Errorf("client not connected")
 	}
 
+	if err := validateValueEncoding(req.ValueEncoding); err != nil {
+		return nil, nil, err
+	}
+
 	mm.mutex.Lock()
 	defer mm.mutex.Unlock()
 
-	sessionKey := fmt.Sprintf("%s-%s-%d", req.Topic, req.GroupID, req.Partition)
+	key := sessionKey(req)
 
-	// Check if consumer already exists
-	if session, exists := mm.consumers[sessionKey]; exists {
+	// Reuse a running session; one that is stopping is replaced
+	if session, exists := mm.consumers[key]; exists && !session.stopping() {
 		return session.Messages, session.Errors, nil
 	}
 
-	// Determine starting offset
-	var offset int64
-	if req.FromBeginning {
-		offset = sarama.OffsetOldest
-	} else {
-		offset = sarama.OffsetNewest
+	partitions, err := mm.resolvePartitions(req)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Create partition consumer
-	partitionConsumer, err := mm.client.Consumer.ConsumePartition(req.Topic, req.Partition, offset)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create partition consumer: %w", err)
+	// Once --until has passed no new message falls in the window, so each
+	// partition ends where it ended when consuming started
+	windowClosed := req.Until != nil && time.Now().After(*req.Until)
+
+	// Create partition consumers
+	consumers := make([]sarama.PartitionConsumer, 0, len(partitions))
+	endOffsets := make([]int64, 0, len(partitions))
+	for _, partition := range partitions {
+		offset, err := mm.startOffset(req.Topic, partition, req.FromBeginning, req.Since)
+		if err != nil {
+			for _, pc := range consumers {
+				pc.Close()
+			}
+			return nil, nil, err
+		}
+
+		end := int64(-1)
+		if windowClosed {
+			var unread bool
+			end, unread, err = mm.windowEndOffset(req.Topic, partition, offset)
+			if err != nil {
+				for _, pc := range consumers {
+					pc.Close()
+				}
+				return nil, nil, err
+			}
+			if !unread {
+				continue
+			}
+		}
+
+		partitionConsumer, err := mm.client.Consumer.ConsumePartition(req.Topic, partition, offset)
+		if err != nil {
+			for _, pc := range consumers {
+				pc.Close()
+			}
+			return nil, nil, fmt.Errorf("failed to create partition consumer for partition %d: %w", partition, err)
+		}
+		consumers = append(consumers, partitionConsumer)
+		endOffsets = append(endOffsets, end)
 	}
 
 	// Create consumer session
 	session := &ConsumerSession{
-		Consumer:      partitionConsumer,
+		Consumers:     consumers,
 		Topic:         req.Topic,
-		Partition:     req.Partition,
+		Partitions:    partitions,
 		GroupID:       req.GroupID,
 		Messages:      make(chan *types.Message, 100),
 		Errors:        make(chan error, 10),
 		Stop:          make(chan struct{}),
 		FromBeginning: req.FromBeginning,
+		ValueEncoding: req.ValueEncoding,
+		Until:         req.Until,
+		endOffsets:    endOffsets,
+		key:           key,
+		done:          make(chan struct{}),
 	}
 
-	mm.consumers[sessionKey] = session
+	mm.consumers[key] = session
 
 	// Start consuming in a goroutine
 	go mm.consumeMessages(session)
 
 	mm.logger.Info("Started consumer",
-		"topic", req.Topic, "partition", req.Partition, "group", req.GroupID)
+		"topic", req.Topic, "partitions", partitions, "group", req.GroupID)
 
 	return session.Messages, session.Errors, nil
 }
 
-// consumeMessages handles the message consumption loop
+// startOffset returns the offset a partition is consumed from. With since set it is
+// the first message at or after that time, or the end of the partition if there is none.
+func (mm *MessageManager) startOffset(topic string, partition int32, fromBeginning bool, since *time.Time) (int64, error) {
+	if since != nil {
+		offset, err := mm.client.GetOffset(topic, partition, since.UnixMilli())
+		if err != nil {
+			return 0, fmt.Errorf("failed to find offset at %s for partition %d: %w", since.Format(time.RFC3339), partition, err)
+		}
+		if offset < 0 {
+			return sarama.OffsetNewest, nil
+		}
+		return offset, nil
+	}
+
+	if fromBeginning {
+		return sarama.OffsetOldest, nil
+	}
+	return sarama.OffsetNewest, nil
+}
+
+// windowEndOffset returns the end of a partition, for a time window that has
+// already closed, and whether any message is left to read from offset
+func (mm *MessageManager) windowEndOffset(topic string, partition int32, offset int64) (int64, bool, error) {
+	end, err := mm.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get end offset for partition %d: %w", partition, err)
+	}
+
+	switch offset {
+	case sarama.OffsetNewest:
+		return end, false, nil
+	case sarama.OffsetOldest:
+		offset, err = mm.client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to get start offset for partition %d: %w", partition, err)
+		}
+	}
+	return end, offset < end, nil
+}
+
+// resolvePartitions returns the partitions a consume request should read from
+func (mm *MessageManager) resolvePartitions(req *types.ConsumeRequest) ([]int32, error) {
+	if req.AllPartitions {
+		partitions, err := mm.client.Consumer.Partitions(req.Topic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get partitions: %w", err)
+		}
+		if len(partitions) == 0 {
+			return nil, fmt.Errorf("topic %s has no partitions", req.Topic)
+		}
+		return partitions, nil
+	}
+
+	if len(req.Partitions) > 0 {
+		return req.Partitions, nil
+	}
+
+	return []int32{req.Partition}, nil
+}
+
+// sessionKey identifies a consumer session by topic, group and partitions
+func sessionKey(req *types.ConsumeRequest) string {
+	partitions := "all"
+	if !req.AllPartitions {
+		ids := req.Partitions
+		if len(ids) == 0 {
+			ids = []int32{req.Partition}
+		}
+
+		strs := make([]string, len(ids))
+		for i, id := range ids {
+			strs[i] = strconv.Itoa(int(id))
+		}
+		partitions = strings.Join(strs, ",")
+	}
+
+	return fmt.Sprintf("%s-%s-%s", req.Topic, req.GroupID, partitions)
+}
+
+// consumeMessages runs a consumption loop per partition and closes the session
+// channels once all of them have finished
 func (mm *MessageManager) consumeMessages(session *ConsumerSession) {
-	defer func() {
-		close(session.Messages)
-		close(session.Errors)
-		session.Consumer.Close()
-
-		mm.mutex.Lock()
-		sessionKey := fmt.Sprintf("%s-%s-%d", session.Topic, session.GroupID, session.Partition)
-		delete(mm.consumers, sessionKey)
-		mm.mutex.Unlock()
-	}()
+	var wg sync.WaitGroup
+	for i, partitionConsumer := range session.Consumers {
+		end := int64(-1)
+		if i < len(session.endOffsets) {
+			end = session.endOffsets[i]
+		}
+
+		wg.Add(1)
+		go func(pc sarama.PartitionConsumer, end int64) {
+			defer wg.Done()
+			mm.consumePartition(session, pc, end)
+		}(partitionConsumer, end)
+	}
+	wg.Wait()
+
+	session.closeChannels()
+
+	mm.mutex.Lock()
+	if mm.consumers[session.key] == session {
+		delete(mm.consumers, session.key)
+	}
+	mm.mutex.Unlock()
+
+	close(session.done)
+}
+
+// consumePartition forwards messages and errors from one partition consumer to the
+// session. With end set, the partition is done after the message before end, or
+// once only transaction markers are left before it.
+func (mm *MessageManager) consumePartition(session *ConsumerSession, pc sarama.PartitionConsumer, end int64) {
+	defer pc.Close()
+
+	idle, idleC := newEndTimer(end)
+	if idle != nil {
+		defer idle.Stop()
+	}
 
 	for {
 		select {
-		case msg := <-session.Consumer.Messages():
+		case msg := <-pc.Messages():
 			if msg == nil {
 				return
 			}
 
-			// Convert to our message type
-			message := &types.Message{
-				Topic:     msg.Topic,
-				Partition: msg.Partition,
-				Offset:    msg.Offset,
-				Timestamp: msg.Timestamp,
-				Key:       string(msg.Key),
-				Value:     mm.formatMessageValue(msg.Value),
-				Headers:   make(map[string]string),
+			// The partition is done once it passes the end of the time window
+			if session.Until != nil && msg.Timestamp.After(*session.Until) {
+				return
+			}
+			if end >= 0 && msg.Offset >= end {
+				return
+			}
+			last := end >= 0 && msg.Offset+1 >= end
+			if idle != nil {
+				idle.Reset(partitionEndIdle)
+			}
+
+			if mm.metrics != nil {
+				mm.metrics.Observe(msg, pc.HighWaterMarkOffset())
 			}
 
-			// Convert headers
-			for _, header := range msg.Headers {
-				message.Headers[string(header.Key)] = string(header.Value)
+			message, err := mm.newMessage(msg, session.ValueEncoding)
+			if err != nil && mm.deadLetterTopic != "" {
+				// Messages that reached the dead letter topic are not shown; if that
+				// fails, the message is shown as-is and the failure reported
+				dlqErr := mm.deadLetter(msg, err)
+				if dlqErr == nil {
+					if last {
+						return
+					}
+					continue
+				}
+				select {
+				case session.Errors <- dlqErr:
+				case <-session.Stop:
+					return
+				}
 			}
 
 			select {
@@ -187,8 +932,11 @@ func (mm *MessageManager) consumeMessages(session *ConsumerSession) {
 			case <-session.Stop:
 				return
 			}
+			if last {
+				return
+			}
 
-		case err := <-session.Consumer.Errors():
+		case err := <-pc.Errors():
 			if err == nil {
 				return
 			}
@@ -199,18 +947,130 @@ func (mm *MessageManager) consumeMessages(session *ConsumerSession) {
 				return
 			}
 
+		case <-idleC:
+			if partitionDrained(pc, end) {
+				return
+			}
+			idle.Reset(partitionEndIdle)
+
 		case <-session.Stop:
 			return
 		}
 	}
 }
 
-// formatMessageValue attempts to format the message value for display
-func (mm *MessageManager) formatMessageValue(value []byte) string {
+// newMessage converts a consumed sarama message to our message type, rendering
+// the value in the given encoding. A key or value that cannot be deserialized is
+// rendered as-is and the deserialization error is returned with the message.
+func (mm *MessageManager) newMessage(msg *sarama.ConsumerMessage, encoding string) (*types.Message, error) {
+	key, keyErr := mm.decodeMessageKey(msg.Topic, msg.Key)
+	value, valueErr := mm.decodeMessageValue(msg.Topic, msg.Value, encoding)
+
+	message := &types.Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Timestamp: msg.Timestamp,
+		Key:       key,
+		Value:     value,
+		Headers:   make(map[string]string),
+	}
+
+	// Convert headers
+	for _, header := range msg.Headers {
+		message.Headers[string(header.Key)] = string(header.Value)
+	}
+
+	if keyErr != nil {
+		return message, keyErr
+	}
+	return message, valueErr
+}
+
+// decodeMessageKey decodes the message key with the configured key deserializer,
+// falling back to the raw key if it cannot be decoded
+func (mm *MessageManager) decodeMessageKey(topic string, key []byte) (string, error) {
+	if mm.keyDeserializer != nil && len(key) > 0 {
+		decoded, err := mm.keyDeserializer.Decode(topic, key)
+		if err == nil {
+			return decoded, nil
+		}
+		mm.logger.Warn("Failed to deserialize message key", "topic", topic, "error", err)
+		return string(key), fmt.Errorf("failed to deserialize key: %w", err)
+	}
+
+	return string(key), nil
+}
+
+// decodeMessageValue decodes the message value with the configured deserializer,
+// falling back to the value in the given encoding if it cannot be decoded
+func (mm *MessageManager) decodeMessageValue(topic string, value []byte, encoding string) (string, error) {
+	if mm.deserializer != nil && len(value) > 0 {
+		decoded, err := mm.deserializer.Decode(topic, value)
+		if err == nil {
+			return mm.formatMessageValue([]byte(decoded), ValueEncodingRaw), nil
+		}
+		mm.logger.Warn("Failed to deserialize message value", "topic", topic, "error", err)
+		return mm.formatMessageValue(value, encoding), fmt.Errorf("failed to deserialize value: %w", err)
+	}
+
+	return mm.formatMessageValue(value, encoding), nil
+}
+
+// deadLetter produces a message that could not be processed to the dead letter
+// topic, unchanged apart from headers describing where it came from and why it
+// failed
+func (mm *MessageManager) deadLetter(msg *sarama.ConsumerMessage, cause error) error {
+	out := &sarama.ProducerMessage{
+		Topic:     mm.deadLetterTopic,
+		Timestamp: msg.Timestamp,
+	}
+	if msg.Key != nil {
+		out.Key = sarama.ByteEncoder(msg.Key)
+	}
+	if msg.Value != nil {
+		out.Value = sarama.ByteEncoder(msg.Value)
+	}
+
+	out.Headers = make([]sarama.RecordHeader, 0, len(msg.Headers)+4)
+	for _, header := range msg.Headers {
+		if header != nil {
+			out.Headers = append(out.Headers, *header)
+		}
+	}
+	for _, header := range [][2]string{
+		{DeadLetterHeaderError, cause.Error()},
+		{DeadLetterHeaderTopic, msg.Topic},
+		{DeadLetterHeaderPartition, strconv.Itoa(int(msg.Partition))},
+		{DeadLetterHeaderOffset, strconv.FormatInt(msg.Offset, 10)},
+	} {
+		out.Headers = append(out.Headers, sarama.RecordHeader{Key: []byte(header[0]), Value: []byte(header[1])})
+	}
+
+	if _, _, err := mm.client.Producer.SendMessage(out); err != nil {
+		return fmt.Errorf("failed to produce message at partition %d offset %d to dead letter topic %s: %w",
+			msg.Partition, msg.Offset, mm.deadLetterTopic, err)
+	}
+
+	mm.logger.Info("Message sent to dead letter topic",
+		"topic", msg.Topic, "partition", msg.Partition, "offset", msg.Offset, "dlq", mm.deadLetterTopic, "error", cause)
+	return nil
+}
+
+// formatMessageValue attempts to format the message value for display.
+// Base64 and hex encodings render the bytes as-is so binary values survive display.
+func (mm *MessageManager) formatMessageValue(value []byte, encoding string) string {
 	if len(value) == 0 {
 		return ""
 	}
 
+	switch encoding {
+	case ValueEncodingBase64:
+		return base64.StdEncoding.EncodeToString(value)
+	case ValueEncodingHex:
+		return hex.EncodeToString(value)
+	}
+
 	// Try to parse as JSON first
 	var jsonObj interface{}
 	if err := json.Unmarshal(value, &jsonObj); err == nil {
@@ -224,38 +1084,76 @@ func (mm *MessageManager) formatMessageValue(value []byte) string {
 	return string(value)
 }
 
-// StopConsumer stops a specific consumer
-func (mm *MessageManager) StopConsumer(topic, groupID string, partition int32) error {
+// validateValueEncoding checks that a value encoding is supported. Empty means raw.
+func validateValueEncoding(encoding string) error {
+	switch encoding {
+	case "", ValueEncodingRaw, ValueEncodingBase64, ValueEncodingHex:
+		return nil
+	default:
+		return fmt.Errorf("unsupported value encoding: %s (must be raw, base64 or hex)", encoding)
+	}
+}
+
+// decodeValue parses a message value given in the specified encoding into bytes
+func decodeValue(value, encoding string) ([]byte, error) {
+	if err := validateValueEncoding(encoding); err != nil {
+		return nil, err
+	}
+
+	switch encoding {
+	case ValueEncodingBase64:
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 value: %w", err)
+		}
+		return decoded, nil
+	case ValueEncodingHex:
+		decoded, err := hex.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex value: %w", err)
+		}
+		return decoded, nil
+	default:
+		return []byte(value), nil
+	}
+}
+
+// StopConsumer signals a consumer session to stop. The session's goroutine
+// closes its channels and removes it once its partition consumers have finished.
+func (mm *MessageManager) StopConsumer(req *types.ConsumeRequest) error {
 	mm.mutex.Lock()
 	defer mm.mutex.Unlock()
 
-	sessionKey := fmt.Sprintf("%s-%s-%d", topic, groupID, partition)
-
-	session, exists := mm.consumers[sessionKey]
-	if !exists {
+	session, exists := mm.consumers[sessionKey(req)]
+	if !exists || session.stopping() {
 		return fmt.Errorf("consumer not found")
 	}
 
-	close(session.Stop)
-	delete(mm.consumers, sessionKey)
+	session.signalStop()
 
 	mm.logger.Info("Stopped consumer",
-		"topic", topic, "partition", partition, "group", groupID)
+		"topic", session.Topic, "partitions", session.Partitions, "group", session.GroupID)
 
 	return nil
 }
 
-// StopAllConsumers stops all active consumers
+// StopAllConsumers stops all active consumers and waits until every partition
+// consumer has shut down and the session channels are closed
 func (mm *MessageManager) StopAllConsumers() error {
 	mm.mutex.Lock()
-	defer mm.mutex.Unlock()
+	sessions := make([]*ConsumerSession, 0, len(mm.consumers))
+	for _, session := range mm.consumers {
+		session.signalStop()
+		sessions = append(sessions, session)
+	}
+	mm.mutex.Unlock()
 
-	for sessionKey, session := range mm.consumers {
-		close(session.Stop)
-		delete(mm.consumers, sessionKey)
+	// The session goroutines take the lock to clean up, so wait without holding it
+	for _, session := range sessions {
+		<-session.done
 	}
 
-	mm.logger.Info("Stopped all consumers")
+	mm.logger.Info("Stopped all consumers", "count", len(sessions))
 	return nil
 }
 
@@ -266,33 +1164,49 @@ func (mm *MessageManager) GetActiveConsumers() []*types.ConsumerInfo {
 
 	consumers := make([]*types.ConsumerInfo, 0, len(mm.consumers))
 	for _, session := range mm.consumers {
-		consumers = append(consumers, &types.ConsumerInfo{
+		if session.stopping() {
+			continue
+		}
+		info := &types.ConsumerInfo{
 			Topic:         session.Topic,
-			Partition:     session.Partition,
+			Partitions:    session.Partitions,
 			GroupID:       session.GroupID,
 			FromBeginning: session.FromBeginning,
-		})
+		}
+		if len(session.Partitions) > 0 {
+			info.Partition = session.Partitions[0]
+		}
+		consumers = append(consumers, info)
 	}
 
 	return consumers
 }
 
-// GetTopicMessages retrieves messages from a topic with pagination
+// GetTopicMessages reads up to req.Limit messages from a partition, starting at req.Offset when
+// it is set. The returned pagination carries the offset to request the next page from.
 func (mm *MessageManager) GetTopicMessages(ctx context.Context, req *types.GetMessagesRequest) (*types.MessageList, error) {
 	if !mm.client.IsConnected() {
 		return nil, fmt.Errorf("client not connected")
 	}
 
-	// This is a simplified implementation that would need to be enhanced
-	// for production use with proper offset management and pagination
+	if err := validateValueEncoding(req.ValueEncoding); err != nil {
+		return nil, err
+	}
 
-	var messages []*types.Message
 	var offset int64
-
-	if req.FromBeginning {
-		offset = sarama.OffsetOldest
+	if req.Offset != nil {
+		offset = *req.Offset
 	} else {
-		offset = sarama.OffsetNewest
+		var err error
+		offset, err = mm.startOffset(req.Topic, req.Partition, req.FromBeginning, req.Since)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultMessageLimit
 	}
 
 	// Create a temporary consumer for fetching messages
@@ -302,38 +1216,28 @@ func (mm *MessageManager) GetTopicMessages(ctx context.Context, req *types.GetMe
 	}
 	defer partitionConsumer.Close()
 
-	// Collect messages with timeout
-	timeout := time.After(5 * time.Second)
-	messageCount := 0
-	maxMessages := req.Limit
-	if maxMessages == 0 {
-		maxMessages = 100 // Default limit
-	}
+	messages := make([]*types.Message, 0, limit)
+	timeout := time.After(messageFetchTimeout)
 
-	for messageCount < maxMessages {
+collect:
+	for len(messages) < limit {
 		select {
 		case msg := <-partitionConsumer.Messages():
 			if msg == nil {
-				break
-			}
-
-			message := &types.Message{
-				Topic:     msg.Topic,
-				Partition: msg.Partition,
-				Offset:    msg.Offset,
-				Timestamp: msg.Timestamp,
-				Key:       string(msg.Key),
-				Value:     mm.formatMessageValue(msg.Value),
-				Headers:   make(map[string]string),
+				break collect
 			}
 
-			// Convert headers
-			for _, header := range msg.Headers {
-				message.Headers[string(header.Key)] = string(header.Value)
+			if req.Until != nil && msg.Timestamp.After(*req.Until) {
+				break collect
 			}
 
+			message, _ := mm.newMessage(msg, req.ValueEncoding)
 			messages = append(messages, message)
-			messageCount++
+
+			// Stop early once the end of the partition has been reached
+			if msg.Offset+1 >= partitionConsumer.HighWaterMarkOffset() {
+				break collect
+			}
 
 		case err := <-partitionConsumer.Errors():
 			if err != nil {
@@ -341,20 +1245,31 @@ func (mm *MessageManager) GetTopicMessages(ctx context.Context, req *types.GetMe
 			}
 
 		case <-timeout:
-			break
+			break collect
 
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		}
 	}
 
+	pagination := &types.Pagination{
+		CurrentPage: 1,
+		TotalPages:  1,
+		PageSize:    len(messages),
+		TotalItems:  len(messages),
+	}
+
+	// The next page starts after the last message read, or where this page started
+	if len(messages) > 0 {
+		next := messages[len(messages)-1].Offset + 1
+		pagination.NextOffset = &next
+	} else if req.Offset != nil {
+		next := *req.Offset
+		pagination.NextOffset = &next
+	}
+
 	return &types.MessageList{
-		Messages: messages,
-		Pagination: &types.Pagination{
-			CurrentPage: 1,
-			TotalPages:  1,
-			PageSize:    len(messages),
-			TotalItems:  len(messages),
-		},
+		Messages:   messages,
+		Pagination: pagination,
 	}, nil
 }
@@ -1,10 +1,22 @@
 package manager
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/nipunap/kim/internal/client"
+	"github.com/nipunap/kim/internal/metrics"
+	"github.com/nipunap/kim/internal/serde"
 	"github.com/nipunap/kim/internal/testutil"
 	"github.com/nipunap/kim/pkg/types"
 )
@@ -86,3 +98,861 @@ func TestMessageManagerProduceMessage(t *testing.T) {
 		t.Logf("ProduceMessage failed as expected in test environment: %v", err)
 	}
 }
+
+func TestMessageManagerProduceBatch(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.Producer().FailOnCall(1)
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	reqs := []types.ProduceRequest{
+		{Value: "one"},
+		{Value: "two"},
+		{Value: "three"},
+	}
+
+	produced, err := mm.ProduceBatch(context.Background(), "test-topic", reqs)
+	if err == nil {
+		t.Fatal("ProduceBatch should report the failed message")
+	}
+	if !strings.Contains(err.Error(), "message 2") {
+		t.Errorf("Error should identify the failed message, got: %v", err)
+	}
+	if produced != 2 {
+		t.Errorf("Expected 2 messages produced, got %d", produced)
+	}
+
+	messages := mock.Producer().Messages()
+	if len(messages) != 3 {
+		t.Fatalf("Expected all 3 messages to be attempted, got %d", len(messages))
+	}
+	for _, msg := range messages {
+		if msg.Topic != "test-topic" {
+			t.Errorf("Expected topic test-topic, got %s", msg.Topic)
+		}
+	}
+}
+
+func TestMessageManagerConsumeAllPartitions(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	p0 := mock.Consumer().AddMockPartition("test-topic", 0)
+	p1 := mock.Consumer().AddMockPartition("test-topic", 1)
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	req := &types.ConsumeRequest{
+		Topic:         "test-topic",
+		GroupID:       "test-group",
+		AllPartitions: true,
+	}
+
+	messages, _, err := mm.StartConsumer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+
+	p0.SendMockMessage("k0", "from partition 0")
+	p1.SendMockMessage("k1", "from partition 1")
+
+	seen := make(map[int32]string)
+	timeout := time.After(2 * time.Second)
+	for len(seen) < 2 {
+		select {
+		case msg := <-messages:
+			seen[msg.Partition] = msg.Value
+		case <-timeout:
+			t.Fatalf("Timed out waiting for messages, got %v", seen)
+		}
+	}
+
+	if seen[0] != "from partition 0" || seen[1] != "from partition 1" {
+		t.Errorf("Unexpected messages: %v", seen)
+	}
+
+	active := mm.GetActiveConsumers()
+	if len(active) != 1 || len(active[0].Partitions) != 2 {
+		t.Fatalf("Expected one session over two partitions, got %+v", active)
+	}
+	if active[0].Partition != active[0].Partitions[0] {
+		t.Errorf("Expected the deprecated partition field to be the first partition, got %+v", active[0])
+	}
+
+	// A single stop shuts down every partition consumer
+	if err := mm.StopConsumer(req); err != nil {
+		t.Fatalf("StopConsumer failed: %v", err)
+	}
+
+	select {
+	case _, ok := <-messages:
+		if ok {
+			t.Error("Messages channel should be closed after stop")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Messages channel was not closed after stop")
+	}
+
+	if !p0.Closed() || !p1.Closed() {
+		t.Error("All partition consumers should be closed after stop")
+	}
+}
+
+func TestMessageManagerStopAllConsumers(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	orders := mock.Consumer().AddMockPartition("orders", 0)
+	payments := mock.Consumer().AddMockPartition("payments", 0)
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	ordersMessages, _, err := mm.StartConsumer(context.Background(), &types.ConsumeRequest{Topic: "orders", GroupID: "test-group"})
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+	paymentsMessages, _, err := mm.StartConsumer(context.Background(), &types.ConsumeRequest{Topic: "payments", GroupID: "test-group"})
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+
+	if err := mm.StopAllConsumers(); err != nil {
+		t.Fatalf("StopAllConsumers failed: %v", err)
+	}
+
+	// StopAllConsumers waits for the sessions to drain, so both channels are already closed
+	for name, messages := range map[string]<-chan *types.Message{"orders": ordersMessages, "payments": paymentsMessages} {
+		select {
+		case _, ok := <-messages:
+			if ok {
+				t.Errorf("%s messages channel should be closed", name)
+			}
+		default:
+			t.Errorf("%s messages channel was not closed when StopAllConsumers returned", name)
+		}
+	}
+
+	if !orders.Closed() || !payments.Closed() {
+		t.Error("All partition consumers should be closed")
+	}
+	if active := mm.GetActiveConsumers(); len(active) != 0 {
+		t.Errorf("Expected no active consumers, got %d", len(active))
+	}
+}
+
+func TestMessageManagerStartStopConcurrently(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	// Start and stop sessions from several goroutines while messages arrive;
+	// run with -race to catch unsynchronized cleanup
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			req := &types.ConsumeRequest{Topic: "test-topic", GroupID: fmt.Sprintf("group-%d", worker%2)}
+			for i := 0; i < 25; i++ {
+				messages, _, err := mm.StartConsumer(context.Background(), req)
+				if err != nil {
+					t.Errorf("StartConsumer failed: %v", err)
+					return
+				}
+
+				// Another worker may have stopped the shared session already
+				mm.StopConsumer(req)
+				mm.StopConsumer(req)
+
+				for range messages {
+				}
+			}
+		}(worker)
+	}
+
+	for i := 0; i < 50; i++ {
+		partition.SendMockMessage("", fmt.Sprintf("message-%d", i))
+	}
+	wg.Wait()
+
+	if err := mm.StopAllConsumers(); err != nil {
+		t.Fatalf("StopAllConsumers failed: %v", err)
+	}
+	if active := mm.GetActiveConsumers(); len(active) != 0 {
+		t.Errorf("Expected no active consumers, got %d", len(active))
+	}
+}
+
+func TestMessageManagerConsumeMetrics(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+
+	consumerMetrics := metrics.NewConsumerMetrics()
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+	mm.SetMetrics(consumerMetrics)
+
+	req := &types.ConsumeRequest{
+		Topic:   "test-topic",
+		GroupID: "test-group",
+	}
+
+	messages, _, err := mm.StartConsumer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+
+	const count = 5
+	for i := 0; i < count; i++ {
+		partition.SendMockMessage("", fmt.Sprintf("message-%d", i))
+	}
+	for i := 0; i < count; i++ {
+		select {
+		case <-messages:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for message %d", i)
+		}
+	}
+
+	if err := mm.StopConsumer(req); err != nil {
+		t.Fatalf("StopConsumer failed: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	consumerMetrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	expected := fmt.Sprintf(`messages_consumed_total{partition="0",topic="test-topic"} %d`, count)
+	if !strings.Contains(recorder.Body.String(), expected) {
+		t.Errorf("Expected %q in metrics output:\n%s", expected, recorder.Body.String())
+	}
+}
+
+func TestMessageManagerGetTopicMessagesPagination(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+	for i := 0; i < 5; i++ {
+		partition.SendMockMessage("", fmt.Sprintf("message-%d", i))
+	}
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	// First page starts at the requested offset
+	offset := int64(1)
+	page, err := mm.GetTopicMessages(context.Background(), &types.GetMessagesRequest{
+		Topic:  "test-topic",
+		Limit:  2,
+		Offset: &offset,
+	})
+	if err != nil {
+		t.Fatalf("GetTopicMessages failed: %v", err)
+	}
+
+	if len(page.Messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(page.Messages))
+	}
+	if page.Messages[0].Offset != 1 || page.Messages[1].Offset != 2 {
+		t.Errorf("Expected offsets 1 and 2, got %d and %d", page.Messages[0].Offset, page.Messages[1].Offset)
+	}
+	if page.Pagination.NextOffset == nil || *page.Pagination.NextOffset != 3 {
+		t.Fatalf("Expected next offset 3, got %v", page.Pagination.NextOffset)
+	}
+
+	// Second page stops at the end of the partition
+	page, err = mm.GetTopicMessages(context.Background(), &types.GetMessagesRequest{
+		Topic:  "test-topic",
+		Limit:  10,
+		Offset: page.Pagination.NextOffset,
+	})
+	if err != nil {
+		t.Fatalf("GetTopicMessages failed: %v", err)
+	}
+
+	if len(page.Messages) != 2 {
+		t.Fatalf("Expected the remaining 2 messages, got %d", len(page.Messages))
+	}
+	if page.Messages[1].Value != "message-4" {
+		t.Errorf("Expected last message 'message-4', got %s", page.Messages[1].Value)
+	}
+	if *page.Pagination.NextOffset != 5 {
+		t.Errorf("Expected next offset 5, got %d", *page.Pagination.NextOffset)
+	}
+}
+
+func TestMessageManagerTimeWindow(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		partition.SendMockMessageAt("", fmt.Sprintf("message-%d", i), start.Add(time.Duration(i)*time.Hour))
+	}
+
+	since := start.Add(time.Hour)
+	until := start.Add(3 * time.Hour)
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	// Messages before --since and after --until are excluded
+	page, err := mm.GetTopicMessages(context.Background(), &types.GetMessagesRequest{
+		Topic: "test-topic",
+		Limit: 10,
+		Since: &since,
+		Until: &until,
+	})
+	if err != nil {
+		t.Fatalf("GetTopicMessages failed: %v", err)
+	}
+
+	var values []string
+	for _, msg := range page.Messages {
+		values = append(values, msg.Value)
+	}
+	expected := []string{"message-1", "message-2", "message-3"}
+	if strings.Join(values, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected messages %v, got %v", expected, values)
+	}
+
+	// The consumer closes once the partition passes --until
+	req := &types.ConsumeRequest{
+		Topic:   "test-topic",
+		GroupID: "test-group",
+		Since:   &since,
+		Until:   &until,
+	}
+
+	messages, _, err := mm.StartConsumer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+
+	values = nil
+	timeout := time.After(2 * time.Second)
+	for done := false; !done; {
+		select {
+		case msg := <-messages:
+			if msg == nil {
+				done = true
+				break
+			}
+			values = append(values, msg.Value)
+		case <-timeout:
+			t.Fatalf("Timed out waiting for the consumer to close, got %v", values)
+		}
+	}
+	if strings.Join(values, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected consumed messages %v, got %v", expected, values)
+	}
+}
+
+func TestMessageManagerTimeWindowEndsAtPartitionEnd(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, partition := range []int32{0, 1} {
+		pc := mock.Consumer().AddMockPartition("test-topic", partition)
+		for i := 0; i < 3; i++ {
+			pc.SendMockMessageAt("", fmt.Sprintf("message-%d-%d", partition, i), start.Add(time.Duration(i)*time.Hour))
+		}
+	}
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	consume := func(since, until time.Time) []string {
+		t.Helper()
+		messages, _, err := mm.StartConsumer(context.Background(), &types.ConsumeRequest{
+			Topic:      "test-topic",
+			GroupID:    "test-group",
+			Partitions: []int32{0, 1},
+			Since:      &since,
+			Until:      &until,
+		})
+		if err != nil {
+			t.Fatalf("StartConsumer failed: %v", err)
+		}
+
+		var values []string
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case msg := <-messages:
+				if msg == nil {
+					sort.Strings(values)
+					return values
+				}
+				values = append(values, msg.Value)
+			case <-timeout:
+				t.Fatalf("Timed out waiting for the consumer to close, got %v", values)
+			}
+		}
+	}
+
+	// No message comes after --until, so each partition ends at its last message
+	values := consume(start.Add(time.Hour), start.Add(10*time.Hour))
+	expected := []string{"message-0-1", "message-0-2", "message-1-1", "message-1-2"}
+	if strings.Join(values, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected messages %v, got %v", expected, values)
+	}
+
+	// No message is at or after --since, so there is nothing to read
+	if values := consume(start.Add(5*time.Hour), start.Add(10*time.Hour)); len(values) != 0 {
+		t.Errorf("Expected no messages, got %v", values)
+	}
+}
+
+func TestMessageManagerTimeWindowTransactionalPartition(t *testing.T) {
+	shortPartitionEndIdle(t)
+
+	logger := testutil.TestLogger()
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+	partition.SendMockMessageAt("", "message-0", start)
+	partition.SendMockMessageAt("", "message-1", start.Add(time.Hour))
+	// The log ends with a commit marker, past the last data message
+	partition.SendMockControlRecord()
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	since := start
+	until := start.Add(10 * time.Hour)
+	messages, _, err := mm.StartConsumer(context.Background(), &types.ConsumeRequest{
+		Topic:      "test-topic",
+		GroupID:    "test-group",
+		Partitions: []int32{0},
+		Since:      &since,
+		Until:      &until,
+	})
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+
+	var values []string
+	timeout := time.After(2 * time.Second)
+	for done := false; !done; {
+		select {
+		case msg := <-messages:
+			if msg == nil {
+				done = true
+				break
+			}
+			values = append(values, msg.Value)
+		case <-timeout:
+			t.Fatalf("Timed out waiting for the consumer to close at the transaction marker, got %v", values)
+		}
+	}
+	if strings.Join(values, ",") != "message-0,message-1" {
+		t.Errorf("Expected both data messages, got %v", values)
+	}
+}
+
+// upperDeserializer decodes values prefixed with "enc:" by upper-casing the rest
+type upperDeserializer struct{}
+
+func (upperDeserializer) Decode(topic string, data []byte) (string, error) {
+	value := string(data)
+	if !strings.HasPrefix(value, "enc:") {
+		return "", fmt.Errorf("not encoded")
+	}
+	return strings.ToUpper(strings.TrimPrefix(value, "enc:")), nil
+}
+
+func TestMessageManagerDeserializer(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+	partition.SendMockMessage("k1", "enc:hello")
+	partition.SendMockMessage("k2", "plain")
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+	mm.SetDeserializer(upperDeserializer{})
+
+	req := &types.ConsumeRequest{
+		Topic:         "test-topic",
+		GroupID:       "test-group",
+		Partition:     0,
+		FromBeginning: true,
+	}
+
+	messages, _, err := mm.StartConsumer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+	defer mm.StopConsumer(req)
+
+	// Values that fail to decode fall back to the raw value
+	expected := []string{"HELLO", "plain"}
+	for _, want := range expected {
+		select {
+		case msg := <-messages:
+			if msg.Value != want {
+				t.Errorf("Expected value %q, got %q", want, msg.Value)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for message %q", want)
+		}
+	}
+}
+
+func TestMessageManagerDeadLetterTopic(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+	partition.SendMockMessage("k1", "enc:hello")
+	partition.SendMockMessage("k2", "\x00\xffcorrupt")
+	partition.SendMockMessage("k3", "enc:world")
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+	mm.SetDeserializer(upperDeserializer{})
+	mm.SetDeadLetterTopic("dead-letters")
+
+	req := &types.ConsumeRequest{
+		Topic:         "test-topic",
+		GroupID:       "test-group",
+		Partition:     0,
+		FromBeginning: true,
+	}
+
+	messages, _, err := mm.StartConsumer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+	defer mm.StopConsumer(req)
+
+	// The message that fails to deserialize is not delivered
+	for _, want := range []string{"HELLO", "WORLD"} {
+		select {
+		case msg := <-messages:
+			if msg.Value != want {
+				t.Errorf("Expected value %q, got %q", want, msg.Value)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for message %q", want)
+		}
+	}
+
+	sent := mock.Producer().Messages()
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 message in the dead letter topic, got %d", len(sent))
+	}
+	dead := sent[0]
+	key, _ := dead.Key.Encode()
+	value, _ := dead.Value.Encode()
+	if dead.Topic != "dead-letters" || string(key) != "k2" || string(value) != "\x00\xffcorrupt" {
+		t.Errorf("Expected the original bytes in dead-letters, got topic %s, key %q, value %q", dead.Topic, key, value)
+	}
+
+	headers := make(map[string]string)
+	for _, header := range dead.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	if headers[DeadLetterHeaderTopic] != "test-topic" || headers[DeadLetterHeaderPartition] != "0" || headers[DeadLetterHeaderOffset] != "1" {
+		t.Errorf("Expected the source of the message in the headers, got %v", headers)
+	}
+	if !strings.Contains(headers[DeadLetterHeaderError], "not encoded") {
+		t.Errorf("Expected the deserialization error in the headers, got %q", headers[DeadLetterHeaderError])
+	}
+}
+
+func TestMessageManagerKeyDeserializer(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	partition := mock.Consumer().AddMockPartition("test-topic", 0)
+	partition.SendMockMessage(string([]byte{0, 0, 0, 0, 0, 0, 0x30, 0x39}), "plain value")
+	partition.SendMockMessage("short", "other value")
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+	mm.SetKeyDeserializer(serde.Int64Deserializer{})
+
+	req := &types.ConsumeRequest{
+		Topic:         "test-topic",
+		GroupID:       "test-group",
+		Partition:     0,
+		FromBeginning: true,
+	}
+
+	messages, _, err := mm.StartConsumer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+	defer mm.StopConsumer(req)
+
+	// Keys are decoded independently of values; undecodable keys fall back to the raw key
+	expected := []struct{ key, value string }{
+		{"12345", "plain value"},
+		{"short", "other value"},
+	}
+	for _, want := range expected {
+		select {
+		case msg := <-messages:
+			if msg.Key != want.key || msg.Value != want.value {
+				t.Errorf("Expected key %q and value %q, got %q and %q", want.key, want.value, msg.Key, msg.Value)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for message with key %q", want.key)
+		}
+	}
+}
+
+func TestMessageManagerValueEncodingRoundTrip(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	// Bytes that are not valid UTF-8 and would be mangled as a string
+	payload := []byte{0x00, 0xff, 0xfe, 0x80, 0x7f, 0x0a}
+	encoded := base64.StdEncoding.EncodeToString(payload)
+
+	_, err := mm.ProduceMessage(context.Background(), &types.ProduceRequest{
+		Topic:         "binary-topic",
+		Value:         encoded,
+		ValueEncoding: ValueEncodingBase64,
+	})
+	if err != nil {
+		t.Fatalf("ProduceMessage failed: %v", err)
+	}
+
+	produced := mock.Producer().Messages()
+	if len(produced) != 1 {
+		t.Fatalf("Expected 1 produced message, got %d", len(produced))
+	}
+	value, _ := produced[0].Value.Encode()
+	if !bytes.Equal(value, payload) {
+		t.Fatalf("Expected decoded payload %v, got %v", payload, value)
+	}
+
+	// Consume the produced bytes back and render them as base64
+	partition := mock.Consumer().AddMockPartition("binary-topic", 0)
+	partition.SendMockMessage("", string(value))
+
+	req := &types.ConsumeRequest{
+		Topic:         "binary-topic",
+		GroupID:       "test-group",
+		FromBeginning: true,
+		ValueEncoding: ValueEncodingBase64,
+	}
+
+	messages, _, err := mm.StartConsumer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartConsumer failed: %v", err)
+	}
+	defer mm.StopConsumer(req)
+
+	select {
+	case msg := <-messages:
+		if msg.Value != encoded {
+			t.Errorf("Expected value %q, got %q", encoded, msg.Value)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for message")
+	}
+}
+
+func TestMessageManagerValueEncodingErrors(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	tests := []struct {
+		name string
+		req  *types.ProduceRequest
+	}{
+		{"invalid base64", &types.ProduceRequest{Topic: "t", Value: "not base64!", ValueEncoding: ValueEncodingBase64}},
+		{"invalid hex", &types.ProduceRequest{Topic: "t", Value: "zz", ValueEncoding: ValueEncodingHex}},
+		{"unknown encoding", &types.ProduceRequest{Topic: "t", Value: "v", ValueEncoding: "rot13"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := mm.ProduceMessage(context.Background(), tt.req); err == nil {
+				t.Error("ProduceMessage should fail")
+			}
+		})
+	}
+
+	if len(mock.Producer().Messages()) != 0 {
+		t.Error("No message should be sent when the value cannot be decoded")
+	}
+
+	_, _, err := mm.StartConsumer(context.Background(), &types.ConsumeRequest{Topic: "t", ValueEncoding: "rot13"})
+	if err == nil {
+		t.Error("StartConsumer should reject an unknown value encoding")
+	}
+}
+
+func TestFormatMessageValueHex(t *testing.T) {
+	mm := &MessageManager{}
+
+	if got := mm.formatMessageValue([]byte{0xde, 0xad, 0xbe, 0xef}, ValueEncodingHex); got != "deadbeef" {
+		t.Errorf("Expected deadbeef, got %s", got)
+	}
+}
+
+func TestMessageManagerPartitionForKey(t *testing.T) {
+	logger := testutil.TestLogger()
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 6, 1)
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	// The hash partitioner is deterministic for a fixed partition count
+	expected := map[string]int32{"user123": 4, "order-42": 2, "a": 0}
+	for key, want := range expected {
+		partition, err := mm.PartitionForKey(context.Background(), "orders", key)
+		if err != nil {
+			t.Fatalf("PartitionForKey(%s) failed: %v", key, err)
+		}
+		if partition != want {
+			t.Errorf("Expected key %s to map to partition %d, got %d", key, want, partition)
+		}
+	}
+
+	if _, err := mm.PartitionForKey(context.Background(), "missing", "user123"); err == nil {
+		t.Error("PartitionForKey should fail for a missing topic")
+	}
+	if _, err := mm.PartitionForKey(context.Background(), "orders", ""); err == nil {
+		t.Error("PartitionForKey should require a key")
+	}
+	if len(mock.Producer().Messages()) != 0 {
+		t.Errorf("PartitionForKey should not produce, got %d messages", len(mock.Producer().Messages()))
+	}
+}
+
+// shortPartitionEndIdle shortens the wait before an idle partition is checked for its end
+func shortPartitionEndIdle(t *testing.T) {
+	t.Helper()
+	old := partitionEndIdle
+	partitionEndIdle = 10 * time.Millisecond
+	t.Cleanup(func() { partitionEndIdle = old })
+}
+
+func TestMessageManagerReplayTransactionalPartition(t *testing.T) {
+	shortPartitionEndIdle(t)
+
+	logger := testutil.TestLogger()
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders-v2", 1, 1)
+	partition := mock.Consumer().AddMockPartition("orders", 0)
+	partition.SendMockMessage("key-0", "value-0")
+	partition.SendMockMessage("key-1", "value-1")
+	// The commit marker is the last offset and is never delivered
+	partition.SendMockControlRecord()
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	done := make(chan struct{})
+	var result *types.ReplayResult
+	var err error
+	go func() {
+		defer close(done)
+		result, err = mm.Replay(context.Background(), &types.ReplayRequest{
+			Source:        "orders",
+			Destination:   "orders-v2",
+			FromBeginning: true,
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Replay did not finish at the transaction marker")
+	}
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if result.Replayed != 2 {
+		t.Errorf("Expected 2 messages to be replayed, got %d", result.Replayed)
+	}
+}
+
+func TestMessageManagerReplay(t *testing.T) {
+	logger := testutil.TestLogger()
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders-v2", 2, 1)
+	partition := mock.Consumer().AddMockPartition("orders", 1)
+	for i := 0; i < 3; i++ {
+		partition.SendMockMessage(fmt.Sprintf("key-%d", i), fmt.Sprintf("value-%d", i))
+	}
+
+	mm := NewMessageManager(mock.KafkaClient(), logger)
+
+	result, err := mm.Replay(context.Background(), &types.ReplayRequest{
+		Source:        "orders",
+		Destination:   "orders-v2",
+		FromBeginning: true,
+	})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if result.Replayed != 3 {
+		t.Errorf("Expected 3 messages to be replayed, got %d", result.Replayed)
+	}
+
+	sent := mock.Producer().Messages()
+	if len(sent) != 3 {
+		t.Fatalf("Expected 3 SendMessage calls, got %d", len(sent))
+	}
+	for i, msg := range sent {
+		key, _ := msg.Key.Encode()
+		value, _ := msg.Value.Encode()
+		if msg.Topic != "orders-v2" || string(key) != fmt.Sprintf("key-%d", i) || string(value) != fmt.Sprintf("value-%d", i) {
+			t.Errorf("Unexpected message %d: topic %s, key %s, value %s", i, msg.Topic, key, value)
+		}
+	}
+
+	// Partitions are preserved with a dedicated manual producer
+	result, err = mm.Replay(context.Background(), &types.ReplayRequest{
+		Source:             "orders",
+		Destination:        "orders-v2",
+		FromBeginning:      true,
+		Max:                2,
+		PreservePartitions: true,
+	})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if result.Replayed != 2 {
+		t.Errorf("Expected --max to stop the replay after 2 messages, got %d", result.Replayed)
+	}
+	if configs := mock.ProducerConfigs(); len(configs) != 1 {
+		t.Errorf("Expected a dedicated producer to be created, got %d", len(configs))
+	}
+	for _, msg := range mock.Producer().Messages()[3:] {
+		if msg.Partition != 1 {
+			t.Errorf("Expected partition 1 to be preserved, got %d", msg.Partition)
+		}
+	}
+
+	// Following new messages stops quietly when canceled but reports a deadline
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mm.Replay(ctx, &types.ReplayRequest{Source: "orders", Destination: "orders-v2"}); err != nil {
+		t.Errorf("Expected a canceled replay to stop without an error, got %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := mm.Replay(ctx, &types.ReplayRequest{Source: "orders", Destination: "orders-v2"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if _, err := mm.Replay(context.Background(), &types.ReplayRequest{Source: "orders", Destination: "orders"}); err == nil {
+		t.Error("Replay should fail when source and destination are the same")
+	}
+	if _, err := mm.Replay(context.Background(), &types.ReplayRequest{Source: "missing", Destination: "orders-v2"}); err == nil {
+		t.Error("Replay should fail for a missing source topic")
+	}
+}
@@ -0,0 +1,244 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nipunap/kim/internal/client"
+	"github.com/nipunap/kim/internal/logger"
+	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
+)
+
+// DefaultQuotaEntity is the entity name that refers to the default quota for
+// all users or client IDs
+const DefaultQuotaEntity = "<default>"
+
+// QuotaKeys are the quota types that can be set on a user or client ID
+var QuotaKeys = []string{"producer_byte_rate", "consumer_byte_rate", "request_percentage"}
+
+// QuotaManager manages Kafka client and user quotas
+type QuotaManager struct {
+	client *client.Client
+	logger *logger.Logger
+}
+
+// NewQuotaManager creates a new quota manager
+func NewQuotaManager(client *client.Client, logger *logger.Logger) *QuotaManager {
+	return &QuotaManager{
+		client: client,
+		logger: logger,
+	}
+}
+
+// ListQuotas returns the quotas of all entities matching the filter. A nil or
+// empty filter returns every user and client ID quota.
+func (qm *QuotaManager) ListQuotas(ctx context.Context, filter *types.QuotaEntity) ([]*types.QuotaEntry, error) {
+	if !qm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	var components []sarama.QuotaFilterComponent
+	if filter != nil {
+		for _, component := range quotaEntityComponents(filter) {
+			components = append(components, sarama.QuotaFilterComponent{
+				EntityType: component.EntityType,
+				MatchType:  component.MatchType,
+				Match:      component.Name,
+			})
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := qm.client.AdminClient.DescribeClientQuotas(components, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe quotas: %w", err)
+	}
+
+	quotas := make([]*types.QuotaEntry, 0, len(entries))
+	for _, entry := range entries {
+		quota := &types.QuotaEntry{Values: entry.Values}
+		for _, component := range entry.Entity {
+			name := component.Name
+			if component.MatchType == sarama.QuotaMatchDefault {
+				name = DefaultQuotaEntity
+			}
+			switch component.EntityType {
+			case sarama.QuotaEntityUser:
+				quota.User = name
+			case sarama.QuotaEntityClientID:
+				quota.ClientID = name
+			}
+		}
+		quotas = append(quotas, quota)
+	}
+
+	sort.Slice(quotas, func(i, j int) bool {
+		if quotas[i].User != quotas[j].User {
+			return quotas[i].User < quotas[j].User
+		}
+		return quotas[i].ClientID < quotas[j].ClientID
+	})
+
+	return quotas, nil
+}
+
+// SetQuotas sets the given quota values on an entity, leaving its other quotas unchanged
+func (qm *QuotaManager) SetQuotas(ctx context.Context, entity *types.QuotaEntity, values map[string]float64) error {
+	if !qm.client.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	if err := validateQuotaEntity(entity); err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("no quota values given")
+	}
+
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		if err := validateQuotaKey(key); err != nil {
+			return err
+		}
+		if value < 0 {
+			return fmt.Errorf("quota %s cannot be negative", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	components := quotaEntityComponents(entity)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		op := sarama.ClientQuotasOp{Key: key, Value: values[key]}
+		if err := qm.client.AdminClient.AlterClientQuotas(components, op, false); err != nil {
+			return fmt.Errorf("failed to set quota %s: %w", key, err)
+		}
+	}
+
+	qm.logger.Info("Quotas set", "user", entity.User, "client_id", entity.ClientID, "quotas", len(keys))
+	return nil
+}
+
+// DeleteQuotas removes quotas from an entity. If no keys are given, all quotas
+// configured on the entity are removed.
+func (qm *QuotaManager) DeleteQuotas(ctx context.Context, entity *types.QuotaEntity, keys []string) error {
+	if !qm.client.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	if err := validateQuotaEntity(entity); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := validateQuotaKey(key); err != nil {
+			return err
+		}
+	}
+
+	components := quotaEntityComponents(entity)
+
+	if len(keys) == 0 {
+		// Look up the quotas configured on exactly this entity
+		filter := make([]sarama.QuotaFilterComponent, 0, len(components))
+		for _, component := range components {
+			filter = append(filter, sarama.QuotaFilterComponent{
+				EntityType: component.EntityType,
+				MatchType:  component.MatchType,
+				Match:      component.Name,
+			})
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entries, err := qm.client.AdminClient.DescribeClientQuotas(filter, true)
+		if err != nil {
+			return fmt.Errorf("failed to describe quotas: %w", err)
+		}
+		for _, entry := range entries {
+			for key := range entry.Values {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no quotas found for %s", describeQuotaEntity(entity))
+		}
+		sort.Strings(keys)
+	}
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		op := sarama.ClientQuotasOp{Key: key, Remove: true}
+		if err := qm.client.AdminClient.AlterClientQuotas(components, op, false); err != nil {
+			return fmt.Errorf("failed to delete quota %s: %w", key, err)
+		}
+	}
+
+	qm.logger.Info("Quotas deleted", "user", entity.User, "client_id", entity.ClientID, "quotas", len(keys))
+	return nil
+}
+
+// quotaEntityComponents converts an entity into sarama entity components
+func quotaEntityComponents(entity *types.QuotaEntity) []sarama.QuotaEntityComponent {
+	var components []sarama.QuotaEntityComponent
+
+	add := func(entityType sarama.QuotaEntityType, name string) {
+		if name == "" {
+			return
+		}
+		component := sarama.QuotaEntityComponent{EntityType: entityType, MatchType: sarama.QuotaMatchExact, Name: name}
+		if name == DefaultQuotaEntity {
+			component.MatchType = sarama.QuotaMatchDefault
+			component.Name = ""
+		}
+		components = append(components, component)
+	}
+	add(sarama.QuotaEntityUser, entity.User)
+	add(sarama.QuotaEntityClientID, entity.ClientID)
+
+	return components
+}
+
+// validateQuotaEntity checks that an entity names a user, a client ID or both
+func validateQuotaEntity(entity *types.QuotaEntity) error {
+	if entity == nil || (entity.User == "" && entity.ClientID == "") {
+		return fmt.Errorf("a user or client ID is required")
+	}
+	return nil
+}
+
+// validateQuotaKey checks that key is a supported quota type
+func validateQuotaKey(key string) error {
+	for _, valid := range QuotaKeys {
+		if key == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid quota type '%s': must be one of %s", key, strings.Join(QuotaKeys, ", "))
+}
+
+// describeQuotaEntity returns a readable name for an entity
+func describeQuotaEntity(entity *types.QuotaEntity) string {
+	var parts []string
+	if entity.User != "" {
+		parts = append(parts, fmt.Sprintf("user '%s'", entity.User))
+	}
+	if entity.ClientID != "" {
+		parts = append(parts, fmt.Sprintf("client ID '%s'", entity.ClientID))
+	}
+	return strings.Join(parts, " and ")
+}
@@ -0,0 +1,136 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nipunap/kim/internal/testutil"
+	"github.com/nipunap/kim/pkg/types"
+)
+
+func TestQuotaManagerSetAndListQuotas(t *testing.T) {
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	qm := NewQuotaManager(mock.KafkaClient(), testutil.TestLogger())
+
+	alice := &types.QuotaEntity{User: "alice"}
+	if err := qm.SetQuotas(context.Background(), alice, map[string]float64{"producer_byte_rate": 1048576}); err != nil {
+		t.Fatalf("SetQuotas failed: %v", err)
+	}
+	defaults := &types.QuotaEntity{ClientID: DefaultQuotaEntity}
+	if err := qm.SetQuotas(context.Background(), defaults, map[string]float64{"consumer_byte_rate": 2048}); err != nil {
+		t.Fatalf("SetQuotas failed: %v", err)
+	}
+
+	quotas, err := qm.ListQuotas(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListQuotas failed: %v", err)
+	}
+	if len(quotas) != 2 {
+		t.Fatalf("Expected 2 quota entries, got %d", len(quotas))
+	}
+
+	// Entries are sorted by user, so the client ID default comes first
+	if quotas[0].ClientID != DefaultQuotaEntity || quotas[0].Values["consumer_byte_rate"] != 2048 {
+		t.Errorf("Unexpected default client ID quota: %+v", quotas[0])
+	}
+	if quotas[1].User != "alice" || quotas[1].Values["producer_byte_rate"] != 1048576 {
+		t.Errorf("Unexpected alice quota: %+v", quotas[1])
+	}
+
+	// Filtering by user
+	quotas, err = qm.ListQuotas(context.Background(), alice)
+	if err != nil {
+		t.Fatalf("ListQuotas failed: %v", err)
+	}
+	if len(quotas) != 1 || quotas[0].User != "alice" {
+		t.Errorf("Expected only alice's quota, got %+v", quotas)
+	}
+}
+
+func TestQuotaManagerSetQuotasValidation(t *testing.T) {
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	qm := NewQuotaManager(mock.KafkaClient(), testutil.TestLogger())
+
+	if err := qm.SetQuotas(context.Background(), &types.QuotaEntity{}, map[string]float64{"producer_byte_rate": 1}); err == nil {
+		t.Error("SetQuotas should require a user or client ID")
+	}
+	if err := qm.SetQuotas(context.Background(), &types.QuotaEntity{User: "alice"}, map[string]float64{"fetch_rate": 1}); err == nil {
+		t.Error("SetQuotas should reject unknown quota types")
+	}
+	if err := qm.SetQuotas(context.Background(), &types.QuotaEntity{User: "alice"}, map[string]float64{"producer_byte_rate": -1}); err == nil {
+		t.Error("SetQuotas should reject negative values")
+	}
+}
+
+func TestQuotaManagerDeleteQuotas(t *testing.T) {
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	qm := NewQuotaManager(mock.KafkaClient(), testutil.TestLogger())
+
+	alice := &types.QuotaEntity{User: "alice"}
+	values := map[string]float64{"producer_byte_rate": 1024, "consumer_byte_rate": 2048}
+	if err := qm.SetQuotas(context.Background(), alice, values); err != nil {
+		t.Fatalf("SetQuotas failed: %v", err)
+	}
+
+	// Delete a single quota type
+	if err := qm.DeleteQuotas(context.Background(), alice, []string{"producer_byte_rate"}); err != nil {
+		t.Fatalf("DeleteQuotas failed: %v", err)
+	}
+	quotas, _ := qm.ListQuotas(context.Background(), alice)
+	if len(quotas) != 1 || len(quotas[0].Values) != 1 || quotas[0].Values["consumer_byte_rate"] != 2048 {
+		t.Errorf("Expected only the consumer quota to remain, got %+v", quotas)
+	}
+
+	// Delete the remaining quotas
+	if err := qm.DeleteQuotas(context.Background(), alice, nil); err != nil {
+		t.Fatalf("DeleteQuotas failed: %v", err)
+	}
+	quotas, _ = qm.ListQuotas(context.Background(), nil)
+	if len(quotas) != 0 {
+		t.Errorf("Expected no quotas, got %+v", quotas)
+	}
+
+	if err := qm.DeleteQuotas(context.Background(), alice, nil); err == nil {
+		t.Error("Deleting quotas from an entity without quotas should fail")
+	}
+}
+
+func TestQuotaManagerCanceledContext(t *testing.T) {
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	qm := NewQuotaManager(mock.KafkaClient(), testutil.TestLogger())
+
+	alice := &types.QuotaEntity{User: "alice"}
+	if err := qm.SetQuotas(context.Background(), alice, map[string]float64{"producer_byte_rate": 1024}); err != nil {
+		t.Fatalf("SetQuotas failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	operations := map[string]func() error{
+		"ListQuotas": func() error {
+			_, err := qm.ListQuotas(ctx, nil)
+			return err
+		},
+		"SetQuotas": func() error {
+			return qm.SetQuotas(ctx, alice, map[string]float64{"producer_byte_rate": 2048})
+		},
+		"DeleteQuotas": func() error {
+			return qm.DeleteQuotas(ctx, alice, nil)
+		},
+	}
+	for name, operation := range operations {
+		if err := operation(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+
+	quotas, err := qm.ListQuotas(context.Background(), alice)
+	if err != nil {
+		t.Fatalf("ListQuotas failed: %v", err)
+	}
+	if len(quotas) != 1 || quotas[0].Values["producer_byte_rate"] != 1024 {
+		t.Errorf("Expected the quota to be left alone, got %+v", quotas)
+	}
+}
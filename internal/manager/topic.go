@@ -3,9 +3,11 @@ package manager
 import (
 	"context"
 	"fmt"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nipunap/kim/internal/client"
@@ -29,16 +31,57 @@ func NewTopicManager(client *client.Client, logger *logger.Logger) *TopicManager
 	}
 }
 
+// describeTopicsBatchSize is the number of topics described per metadata request
+const describeTopicsBatchSize = 100
+
+// describeAllTopics returns the metadata of every topic. Topic names are listed
+// first because describing an empty list does not reliably return all topics.
+func (tm *TopicManager) describeAllTopics(ctx context.Context) ([]*sarama.TopicMetadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	topicDetails, err := tm.client.AdminClient.ListTopics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	names := make([]string, 0, len(topicDetails))
+	for name := range topicDetails {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metadata := make([]*sarama.TopicMetadata, 0, len(names))
+	for start := 0; start < len(names); start += describeTopicsBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		end := start + describeTopicsBatchSize
+		if end > len(names) {
+			end = len(names)
+		}
+
+		batch, err := tm.client.AdminClient.DescribeTopics(names[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe topics: %w", err)
+		}
+		metadata = append(metadata, batch...)
+	}
+
+	return metadata, nil
+}
+
 // ListTopics returns a paginated list of topics
 func (tm *TopicManager) ListTopics(ctx context.Context, opts *types.ListOptions) (*types.TopicList, error) {
 	if !tm.client.IsConnected() {
 		return nil, fmt.Errorf("client not connected")
 	}
 
-	// Get topic metadata
-	metadata, err := tm.client.AdminClient.DescribeTopics(nil)
+	metadata, err := tm.describeAllTopics(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe topics: %w", err)
+		return nil, err
 	}
 
 	// Convert to topic info
@@ -61,17 +104,62 @@ func (tm *TopicManager) ListTopics(ctx context.Context, opts *types.ListOptions)
 			topic.ReplicationFactor = int32(len(meta.Partitions[0].Replicas))
 		}
 
+		// Apply internal topic filter
+		switch opts.Internal {
+		case types.InternalInclude:
+		case types.InternalOnly:
+			if !meta.IsInternal {
+				continue
+			}
+		default:
+			if meta.IsInternal {
+				continue
+			}
+		}
+
 		// Apply pattern filter if specified
 		if opts.Pattern != "" && !matchesPattern(meta.Name, opts.Pattern) {
 			continue
 		}
+		if matchesAnyPattern(meta.Name, opts.Exclude) {
+			continue
+		}
+
+		// Apply partition count range if specified
+		if opts.MinPartitions > 0 && topic.Partitions < opts.MinPartitions {
+			continue
+		}
+		if opts.MaxPartitions > 0 && topic.Partitions > opts.MaxPartitions {
+			continue
+		}
 
 		topics = append(topics, topic)
 	}
 
+	// Sorting by size needs the size of every topic, not just the current page
+	sortBySize := opts.SortBy == "size" || opts.SortBy == "messages"
+	if sortBySize {
+		if !opts.WithSize {
+			return nil, fmt.Errorf("sorting by %s requires topic sizes (use --with-size)", opts.SortBy)
+		}
+		if err := tm.addTopicSizes(ctx, topics, metadata); err != nil {
+			return nil, err
+		}
+	}
+
 	// Sort topics
 	sort.Slice(topics, func(i, j int) bool {
 		switch opts.SortBy {
+		case "size":
+			if opts.Order == "desc" {
+				return estimate(topics[i].SizeBytes) > estimate(topics[j].SizeBytes)
+			}
+			return estimate(topics[i].SizeBytes) < estimate(topics[j].SizeBytes)
+		case "messages":
+			if opts.Order == "desc" {
+				return estimate(topics[i].MessageCount) > estimate(topics[j].MessageCount)
+			}
+			return estimate(topics[i].MessageCount) < estimate(topics[j].MessageCount)
 		case "partitions":
 			if opts.Order == "desc" {
 				return topics[i].Partitions > topics[j].Partitions
@@ -105,6 +193,13 @@ func (tm *TopicManager) ListTopics(ctx context.Context, opts *types.ListOptions)
 
 	paginatedTopics := topics[start:end]
 
+	// Sizes need a request per partition, so only the current page is sized
+	if opts.WithSize && !sortBySize {
+		if err := tm.addTopicSizes(ctx, paginatedTopics, metadata); err != nil {
+			return nil, err
+		}
+	}
+
 	return &types.TopicList{
 		Topics: paginatedTopics,
 		Pagination: &types.Pagination{
@@ -116,17 +211,132 @@ func (tm *TopicManager) ListTopics(ctx context.Context, opts *types.ListOptions)
 	}, nil
 }
 
-// DescribeTopic returns detailed information about a specific topic
-func (tm *TopicManager) DescribeTopic(ctx context.Context, topicName string) (*types.TopicDetails, error) {
+// addTopicSizes sets the estimated message count and size of each topic. The
+// message count is the number of offsets between the low and high watermarks of
+// each partition, which includes compacted and aborted records. The size is the
+// size of the leader replicas, and is left unset if the brokers cannot report it.
+// Only cancellation of ctx is returned as an error.
+func (tm *TopicManager) addTopicSizes(ctx context.Context, topics []*types.TopicInfo, metadata []*sarama.TopicMetadata) error {
+	metadataByName := make(map[string]*sarama.TopicMetadata, len(metadata))
+	for _, meta := range metadata {
+		metadataByName[meta.Name] = meta
+	}
+
+	sizes, err := tm.leaderLogSizes(metadataByName)
+	if err != nil {
+		tm.logger.Warn("Failed to describe log dirs, topic sizes unavailable", "error", err)
+	}
+
+	for _, topic := range topics {
+		meta, ok := metadataByName[topic.Name]
+		if !ok {
+			continue
+		}
+
+		var count int64
+		for _, partition := range meta.Partitions {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			oldest, newest, err := tm.partitionOffsets(topic.Name, partition.ID)
+			if err != nil {
+				tm.logger.Warn("Failed to get partition offsets",
+					"topic", topic.Name, "partition", partition.ID, "error", err)
+				continue
+			}
+			count += newest - oldest
+		}
+		topic.MessageCount = &count
+
+		if size, ok := sizes[topic.Name]; ok {
+			topic.SizeBytes = &size
+		}
+	}
+
+	return nil
+}
+
+// estimate returns an estimated topic size for sorting. Unknown sizes sort
+// before every known size.
+func estimate(size *int64) int64 {
+	if size == nil {
+		return -1
+	}
+	return *size
+}
+
+// partitionOffsets returns the low and high watermarks of a partition
+func (tm *TopicManager) partitionOffsets(topic string, partition int32) (int64, int64, error) {
+	oldest, err := tm.client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, 0, err
+	}
+	newest, err := tm.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, 0, err
+	}
+	return oldest, newest, nil
+}
+
+// leaderLogSizes returns the total size in bytes of each topic's leader
+// replicas, as reported by the brokers' log dirs
+func (tm *TopicManager) leaderLogSizes(metadata map[string]*sarama.TopicMetadata) (map[string]int64, error) {
+	brokers, _, err := tm.client.AdminClient.DescribeCluster()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+
+	brokerIDs := make([]int32, 0, len(brokers))
+	for _, broker := range brokers {
+		brokerIDs = append(brokerIDs, broker.ID())
+	}
+
+	logDirs, err := tm.client.AdminClient.DescribeLogDirs(brokerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map each partition to its leader so replicas are not counted more than once
+	leaders := make(map[string]map[int32]int32)
+	for name, meta := range metadata {
+		leaders[name] = make(map[int32]int32, len(meta.Partitions))
+		for _, partition := range meta.Partitions {
+			leaders[name][partition.ID] = partition.Leader
+		}
+	}
+
+	sizes := make(map[string]int64)
+	for brokerID, dirs := range logDirs {
+		for _, dir := range dirs {
+			for _, topic := range dir.Topics {
+				for _, partition := range topic.Partitions {
+					if leader, ok := leaders[topic.Topic][partition.PartitionID]; ok && leader == brokerID {
+						sizes[topic.Topic] += partition.Size
+					}
+				}
+			}
+		}
+	}
+	return sizes, nil
+}
+
+// DescribeTopic returns detailed information about a specific topic. opts may be nil.
+func (tm *TopicManager) DescribeTopic(ctx context.Context, topicName string, opts *types.DescribeTopicOptions) (*types.TopicDetails, error) {
 	if !tm.client.IsConnected() {
 		return nil, fmt.Errorf("client not connected")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Get topic metadata
 	metadata, err := tm.client.AdminClient.DescribeTopics([]string{topicName})
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe topic: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	if len(metadata) == 0 {
 		return nil, fmt.Errorf("topic %s not found", topicName)
@@ -147,6 +357,9 @@ func (tm *TopicManager) DescribeTopic(ctx context.Context, topicName string) (*t
 	if err != nil {
 		tm.logger.Warn("Failed to get topic configuration", "topic", topicName, "error", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Build topic details
 	details := &types.TopicDetails{
@@ -172,7 +385,32 @@ func (tm *TopicManager) DescribeTopic(ctx context.Context, topicName string) (*t
 			OfflineReplicas: partition.OfflineReplicas,
 		}
 
+		// Offsets need two requests per partition, so they are only fetched on request
+		if opts != nil && opts.WithOffsets {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			oldest, newest, err := tm.partitionOffsets(topicName, partition.ID)
+			if err != nil {
+				tm.logger.Warn("Failed to get partition offsets",
+					"topic", topicName, "partition", partition.ID, "error", err)
+			} else {
+				count := newest - oldest
+				partitionInfo.EarliestOffset = &oldest
+				partitionInfo.LatestOffset = &newest
+				partitionInfo.MessageCount = &count
+			}
+		}
+
 		details.PartitionDetails = append(details.PartitionDetails, partitionInfo)
+
+		// Flag partitions that need attention
+		if len(partition.Isr) < len(partition.Replicas) {
+			details.UnderReplicated = true
+		}
+		if len(partition.OfflineReplicas) > 0 || partition.Leader < 0 {
+			details.OfflinePartitions = append(details.OfflinePartitions, partition.ID)
+		}
 	}
 
 	// Add configuration details
@@ -185,11 +423,102 @@ func (tm *TopicManager) DescribeTopic(ctx context.Context, topicName string) (*t
 	return details, nil
 }
 
+// describeTopicsWorkers bounds the number of topics DescribeTopics describes at once
+const describeTopicsWorkers = 8
+
+// DescribeTopics describes several topics concurrently. Results are returned in the
+// order of names; a failure to describe one topic is recorded in its result and
+// does not affect the others. opts may be nil.
+func (tm *TopicManager) DescribeTopics(ctx context.Context, names []string, opts *types.DescribeTopicOptions) []*types.DescribeTopicResult {
+	results := make([]*types.DescribeTopicResult, len(names))
+	indexes := make(chan int)
+
+	workers := describeTopicsWorkers
+	if len(names) < workers {
+		workers = len(names)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := &types.DescribeTopicResult{Name: names[i]}
+				details, err := tm.DescribeTopic(ctx, names[i], opts)
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Details = details
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+	for i := range names {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// DescribeTopicConfig returns the configuration of a topic along with the source
+// and humanized form of each value
+func (tm *TopicManager) DescribeTopicConfig(ctx context.Context, topicName string) (*types.TopicConfig, error) {
+	if !tm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	configResource := sarama.ConfigResource{
+		Type: sarama.TopicResource,
+		Name: topicName,
+	}
+
+	entries, err := tm.client.AdminClient.DescribeConfig(configResource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe topic config: %w", err)
+	}
+
+	topicConfig := &types.TopicConfig{
+		Topic:   topicName,
+		Configs: make(map[string]*types.ConfigEntry, len(entries)),
+	}
+
+	for _, entry := range entries {
+		configEntry := &types.ConfigEntry{
+			Value:     entry.Value,
+			Source:    entry.Source.String(),
+			Default:   entry.Default || entry.Source == sarama.SourceDefault,
+			ReadOnly:  entry.ReadOnly,
+			Sensitive: entry.Sensitive,
+		}
+
+		if !entry.Sensitive {
+			if formatted := tm.FormatConfigValue(entry.Name, entry.Value); formatted != entry.Value {
+				configEntry.Formatted = formatted
+			}
+		}
+
+		topicConfig.Configs[entry.Name] = configEntry
+	}
+
+	return topicConfig, nil
+}
+
 // CreateTopic creates a new topic
 func (tm *TopicManager) CreateTopic(ctx context.Context, req *types.CreateTopicRequest) error {
 	if !tm.client.IsConnected() {
 		return fmt.Errorf("client not connected")
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	topicDetail := &sarama.TopicDetail{
 		NumPartitions:     req.Partitions,
@@ -202,11 +531,15 @@ func (tm *TopicManager) CreateTopic(ctx context.Context, req *types.CreateTopicR
 		topicDetail.ConfigEntries[key] = &value
 	}
 
-	err := tm.client.AdminClient.CreateTopic(req.Name, topicDetail, false)
+	err := tm.client.AdminClient.CreateTopic(req.Name, topicDetail, req.ValidateOnly)
 	if err != nil {
 		return fmt.Errorf("failed to create topic: %w", err)
 	}
 
+	if req.ValidateOnly {
+		tm.logger.Info("Topic validated successfully", "topic", req.Name)
+		return nil
+	}
 	tm.logger.Info("Topic created successfully", "topic", req.Name)
 	return nil
 }
@@ -216,6 +549,9 @@ func (tm *TopicManager) DeleteTopic(ctx context.Context, topicName string) error
 	if !tm.client.IsConnected() {
 		return fmt.Errorf("client not connected")
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	err := tm.client.AdminClient.DeleteTopic(topicName)
 	if err != nil {
@@ -226,11 +562,305 @@ func (tm *TopicManager) DeleteTopic(ctx context.Context, topicName string) error
 	return nil
 }
 
+// matchesAnyPattern reports whether a name matches any of the glob patterns
+func matchesAnyPattern(str string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchesPattern(str, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchTopics returns the names of the non-internal topics matching a glob
+// pattern, sorted by name
+func (tm *TopicManager) MatchTopics(ctx context.Context, pattern string) ([]string, error) {
+	if !tm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	metadata, err := tm.describeAllTopics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, meta := range metadata {
+		if !meta.IsInternal && matchesPattern(meta.Name, pattern) {
+			names = append(names, meta.Name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// DeleteTopics deletes several topics, continuing past failures. The outcome
+// of each deletion is returned in the order of names.
+func (tm *TopicManager) DeleteTopics(ctx context.Context, names []string) []*types.DeleteTopicResult {
+	results := make([]*types.DeleteTopicResult, 0, len(names))
+	for _, name := range names {
+		result := &types.DeleteTopicResult{Topic: name}
+		if err := tm.DeleteTopic(ctx, name); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Exists reports whether a topic exists
+func (tm *TopicManager) Exists(ctx context.Context, topicName string) (bool, error) {
+	if !tm.client.IsConnected() {
+		return false, fmt.Errorf("client not connected")
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	topics, err := tm.client.AdminClient.ListTopics()
+	if err != nil {
+		return false, fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	_, exists := topics[topicName]
+	return exists, nil
+}
+
+// DiffTopics compares the partition layout and configuration of topics a and b
+func (tm *TopicManager) DiffTopics(ctx context.Context, a, b string) (*types.TopicDiff, error) {
+	detailsA, err := tm.DescribeTopic(ctx, a, nil)
+	if err != nil {
+		return nil, err
+	}
+	detailsB, err := tm.DescribeTopic(ctx, b, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &types.TopicDiff{
+		TopicA:    a,
+		TopicB:    b,
+		Structure: make([]*types.DiffEntry, 0),
+		Configs:   diffValues(detailsA.Configs, detailsB.Configs),
+	}
+	if detailsA.Partitions != detailsB.Partitions {
+		diff.Structure = append(diff.Structure, newDiffEntry("partitions",
+			strconv.Itoa(int(detailsA.Partitions)), strconv.Itoa(int(detailsB.Partitions))))
+	}
+	if detailsA.ReplicationFactor != detailsB.ReplicationFactor {
+		diff.Structure = append(diff.Structure, newDiffEntry("replication_factor",
+			strconv.Itoa(int(detailsA.ReplicationFactor)), strconv.Itoa(int(detailsB.ReplicationFactor))))
+	}
+
+	return diff, nil
+}
+
+// diffValues returns the keys whose values differ between a and b, sorted by key
+func diffValues(a, b map[string]string) []*types.DiffEntry {
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for key := range a {
+		keys[key] = struct{}{}
+	}
+	for key := range b {
+		keys[key] = struct{}{}
+	}
+
+	entries := make([]*types.DiffEntry, 0)
+	for key := range keys {
+		valueA, inA := a[key]
+		valueB, inB := b[key]
+		if inA && inB && valueA == valueB {
+			continue
+		}
+		entry := &types.DiffEntry{Key: key}
+		if inA {
+			entry.A = &valueA
+		}
+		if inB {
+			entry.B = &valueB
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+func newDiffEntry(key, a, b string) *types.DiffEntry {
+	return &types.DiffEntry{Key: key, A: &a, B: &b}
+}
+
+// CopyConfig copies the config overrides of topic src to topic dst, creating
+// dst if it does not exist. With copyLayout a new dst also gets the partition
+// count and replication factor of src; otherwise the broker defaults apply.
+// It returns the copied configs.
+func (tm *TopicManager) CopyConfig(ctx context.Context, src, dst string, copyLayout bool) (map[string]string, error) {
+	if !tm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if src == dst {
+		return nil, fmt.Errorf("source and destination topics must differ")
+	}
+
+	topics, err := tm.client.AdminClient.ListTopics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+	srcDetail, exists := topics[src]
+	if !exists {
+		return nil, fmt.Errorf("topic %s not found", src)
+	}
+	_, dstExists := topics[dst]
+	if dstExists && copyLayout {
+		return nil, fmt.Errorf("topic %s already exists, its layout cannot be copied", dst)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entries, err := tm.client.AdminClient.DescribeConfig(sarama.ConfigResource{
+		Type: sarama.TopicResource,
+		Name: src,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe topic config: %w", err)
+	}
+
+	// Only values set on the topic itself are copied
+	overrides := make(map[string]string)
+	for _, entry := range entries {
+		if entry.ReadOnly || entry.Default || entry.Sensitive {
+			continue
+		}
+		switch entry.Source {
+		case sarama.SourceDefault, sarama.SourceStaticBroker, sarama.SourceDynamicBroker, sarama.SourceDynamicDefaultBroker:
+			continue
+		}
+		overrides[entry.Name] = entry.Value
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if dstExists {
+		if len(overrides) == 0 {
+			return overrides, nil
+		}
+		alterEntries := make(map[string]sarama.IncrementalAlterConfigsEntry, len(overrides))
+		for name, value := range overrides {
+			value := value
+			alterEntries[name] = sarama.IncrementalAlterConfigsEntry{
+				Operation: sarama.IncrementalAlterConfigsOperationSet,
+				Value:     &value,
+			}
+		}
+		if err := tm.client.AdminClient.IncrementalAlterConfig(sarama.TopicResource, dst, alterEntries, false); err != nil {
+			return nil, fmt.Errorf("failed to alter topic config: %w", err)
+		}
+		tm.logger.Info("Topic config copied", "source", src, "destination", dst, "configs", len(overrides))
+		return overrides, nil
+	}
+
+	// -1 lets the brokers choose the partition count and replication factor
+	topicDetail := &sarama.TopicDetail{
+		NumPartitions:     -1,
+		ReplicationFactor: -1,
+		ConfigEntries:     make(map[string]*string, len(overrides)),
+	}
+	if copyLayout {
+		topicDetail.NumPartitions = srcDetail.NumPartitions
+		topicDetail.ReplicationFactor = srcDetail.ReplicationFactor
+	}
+	for name, value := range overrides {
+		value := value
+		topicDetail.ConfigEntries[name] = &value
+	}
+
+	if err := tm.client.AdminClient.CreateTopic(dst, topicDetail, false); err != nil {
+		return nil, fmt.Errorf("failed to create topic: %w", err)
+	}
+
+	tm.logger.Info("Topic created from config", "source", src, "destination", dst, "configs", len(overrides))
+	return overrides, nil
+}
+
+// TruncateTopic deletes every record currently in the given partitions of a
+// topic, or in all partitions if none are given, without deleting the topic. It
+// returns the offset each partition was truncated to.
+func (tm *TopicManager) TruncateTopic(ctx context.Context, topicName string, partitions []int32) (map[int32]int64, error) {
+	if !tm.client.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	metadata, err := tm.client.AdminClient.DescribeTopics([]string{topicName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe topic: %w", err)
+	}
+	if len(metadata) == 0 || metadata[0].Err == sarama.ErrUnknownTopicOrPartition {
+		return nil, fmt.Errorf("topic %s not found", topicName)
+	}
+	topicMeta := metadata[0]
+	if topicMeta.Err != sarama.ErrNoError {
+		return nil, fmt.Errorf("error describing topic %s: %v", topicName, topicMeta.Err)
+	}
+
+	existing := make(map[int32]bool, len(topicMeta.Partitions))
+	for _, partition := range topicMeta.Partitions {
+		existing[partition.ID] = true
+	}
+	if len(partitions) == 0 {
+		for _, partition := range topicMeta.Partitions {
+			partitions = append(partitions, partition.ID)
+		}
+	}
+
+	// Records are deleted up to the high watermark of each partition
+	offsets := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		if !existing[partition] {
+			return nil, fmt.Errorf("partition %d of topic %s does not exist", partition, topicName)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		offset, err := tm.client.GetOffset(topicName, partition, sarama.OffsetNewest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get offset of partition %d: %w", partition, err)
+		}
+		offsets[partition] = offset
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := tm.client.AdminClient.DeleteRecords(topicName, offsets); err != nil {
+		return nil, fmt.Errorf("failed to delete records: %w", err)
+	}
+
+	tm.logger.Info("Topic truncated", "topic", topicName, "partitions", len(offsets))
+	return offsets, nil
+}
+
 // GetTopicOffsets returns the latest offsets for all partitions of a topic
 func (tm *TopicManager) GetTopicOffsets(ctx context.Context, topicName string) (map[int32]int64, error) {
 	if !tm.client.IsConnected() {
 		return nil, fmt.Errorf("client not connected")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Get topic metadata to find partitions
 	metadata, err := tm.client.AdminClient.DescribeTopics([]string{topicName})
@@ -290,6 +920,16 @@ func (tm *TopicManager) FormatConfigValue(key, value string) string {
 
 // formatTimeMs formats milliseconds into human-readable time
 func (tm *TopicManager) formatTimeMs(value string) string {
+	return humanizeTimeMs(value)
+}
+
+// formatBytes formats bytes into human-readable size
+func (tm *TopicManager) formatBytes(value string) string {
+	return humanizeBytes(value)
+}
+
+// humanizeTimeMs formats a millisecond value into human-readable time
+func humanizeTimeMs(value string) string {
 	ms, err := strconv.ParseInt(value, 10, 64)
 	if err != nil {
 		return value
@@ -320,8 +960,8 @@ func (tm *TopicManager) formatTimeMs(value string) string {
 	}
 }
 
-// formatBytes formats bytes into human-readable size
-func (tm *TopicManager) formatBytes(value string) string {
+// humanizeBytes formats a byte count into human-readable size
+func humanizeBytes(value string) string {
 	bytes, err := strconv.ParseInt(value, 10, 64)
 	if err != nil {
 		return value
@@ -335,29 +975,27 @@ func (tm *TopicManager) formatBytes(value string) string {
 	}
 
 	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
-	}
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
 
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
+	// Track the unit index directly, stopping at the largest unit so values
+	// beyond it are shown in PB rather than indexing past the end
+	size := float64(bytes)
+	idx := 0
+	for size >= unit && idx < len(units)-1 {
+		size /= unit
+		idx++
 	}
 
-	units := []string{"B", "KB", "MB", "GB", "TB"}
-	return fmt.Sprintf("%.2f %s", float64(bytes)/float64(div), units[exp+1])
+	if idx == 0 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	return fmt.Sprintf("%.2f %s", size, units[idx])
 }
 
-// matchesPattern checks if a string matches a wildcard pattern
+// matchesPattern reports whether a name matches a glob pattern, where * matches
+// any run of characters, ? a single character and [...] a character class.
+// Malformed patterns match nothing.
 func matchesPattern(str, pattern string) bool {
-	// Simple wildcard matching - supports * and ?
-	// This is a simplified implementation
-	if pattern == "*" {
-		return true
-	}
-
-	// For now, just check if the pattern is contained in the string
-	// In a full implementation, you'd want proper glob matching
-	return strings.Contains(strings.ToLower(str), strings.ToLower(strings.ReplaceAll(pattern, "*", "")))
+	matched, err := path.Match(pattern, str)
+	return err == nil && matched
 }
@@ -2,11 +2,16 @@ package manager
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/testutil"
 	"github.com/nipunap/kim/pkg/types"
+
+	"github.com/IBM/sarama"
 )
 
 func TestNewTopicManager(t *testing.T) {
@@ -68,7 +73,7 @@ func TestTopicManagerDescribeTopic(t *testing.T) {
 	tm := NewTopicManager(c, logger)
 
 	// Test describe topic - this will fail if no Kafka is running, but that's expected
-	_, err = tm.DescribeTopic(context.Background(), "test-topic")
+	_, err = tm.DescribeTopic(context.Background(), "test-topic", nil)
 	// We expect this to fail in test environment without Kafka
 	if err == nil {
 		t.Log("DescribeTopic succeeded (Kafka must be running)")
@@ -128,3 +133,678 @@ func TestTopicManagerDeleteTopic(t *testing.T) {
 		t.Logf("DeleteTopic failed as expected in test environment: %v", err)
 	}
 }
+
+func TestTopicManagerDescribeTopicConfig(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockConfig(sarama.TopicResource, "orders",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "604800000", Source: sarama.SourceTopic},
+		sarama.ConfigEntry{Name: "cleanup.policy", Value: "delete", Default: true, Source: sarama.SourceDefault},
+		sarama.ConfigEntry{Name: "min.insync.replicas", Value: "1", Default: true, Source: sarama.SourceDefault},
+	)
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	topicConfig, err := tm.DescribeTopicConfig(context.Background(), "orders")
+	if err != nil {
+		t.Fatalf("DescribeTopicConfig failed: %v", err)
+	}
+
+	retention := topicConfig.Configs["retention.ms"]
+	if retention == nil || retention.Value != "604800000" || retention.Formatted != "7 days 0 hours" {
+		t.Errorf("Unexpected retention.ms entry: %+v", retention)
+	}
+	if retention != nil && retention.Default {
+		t.Error("Topic level entry should be reported as overridden")
+	}
+
+	if !topicConfig.Configs["cleanup.policy"].Default {
+		t.Error("Default entry should be reported as default")
+	}
+	if formatted := topicConfig.Configs["min.insync.replicas"].Formatted; formatted != "" {
+		t.Errorf("Unformatted entry should have no formatted value, got '%s'", formatted)
+	}
+}
+
+func TestTopicManagerFormatBytes(t *testing.T) {
+	tm := &TopicManager{}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"0", "0 B"},
+		{"-1", "unlimited"},
+		{"1023", "1023 B"},
+		{"1024", "1.00 KB"},
+		{"1073741824", "1.00 GB"},
+		{"5497558138880", "5.00 TB"},
+		{"1125899906842624", "1.00 PB"},
+		{"9223372036854775807", "8192.00 PB"},
+		{"not-a-number", "not-a-number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := tm.formatBytes(tt.value); got != tt.want {
+				t.Errorf("formatBytes(%s) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopicManagerDescribeTopicHealth(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("healthy", 2, 3)
+	mock.AddMockTopic("degraded", 3, 3)
+
+	meta, _ := mock.MockTopic("degraded")
+	meta.Partitions[1].Isr = []int32{0, 1}
+	meta.Partitions[2].OfflineReplicas = []int32{2}
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	details, err := tm.DescribeTopic(context.Background(), "degraded", nil)
+	if err != nil {
+		t.Fatalf("DescribeTopic failed: %v", err)
+	}
+	if !details.UnderReplicated {
+		t.Error("Topic with fewer in-sync replicas than replicas should be under-replicated")
+	}
+	if len(details.OfflinePartitions) != 1 || details.OfflinePartitions[0] != 2 {
+		t.Errorf("Expected offline partitions [2], got %v", details.OfflinePartitions)
+	}
+
+	details, err = tm.DescribeTopic(context.Background(), "healthy", nil)
+	if err != nil {
+		t.Fatalf("DescribeTopic failed: %v", err)
+	}
+	if details.UnderReplicated || len(details.OfflinePartitions) != 0 {
+		t.Errorf("Healthy topic should not be flagged, got under-replicated %v, offline %v",
+			details.UnderReplicated, details.OfflinePartitions)
+	}
+}
+
+func TestTopicManagerListTopicsPartitionRange(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders-small", 1, 1)
+	mock.AddMockTopic("orders-medium", 6, 1)
+	mock.AddMockTopic("orders-large", 50, 1)
+	mock.AddMockTopic("payments-medium", 8, 1)
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	list := func(opts *types.ListOptions) []string {
+		t.Helper()
+		opts.Page, opts.PageSize = 1, 100
+		topicList, err := tm.ListTopics(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("ListTopics failed: %v", err)
+		}
+		var names []string
+		for _, topic := range topicList.Topics {
+			names = append(names, topic.Name)
+		}
+		return names
+	}
+
+	if got := strings.Join(list(&types.ListOptions{MinPartitions: 2, MaxPartitions: 10}), ","); got != "orders-medium,payments-medium" {
+		t.Errorf("Expected topics with 2-10 partitions, got %s", got)
+	}
+	if got := strings.Join(list(&types.ListOptions{MinPartitions: 8}), ","); got != "orders-large,payments-medium" {
+		t.Errorf("Expected topics with at least 8 partitions, got %s", got)
+	}
+
+	// Combined with a pattern both filters must match
+	if got := strings.Join(list(&types.ListOptions{Pattern: "orders*", MaxPartitions: 10}), ","); got != "orders-medium,orders-small" {
+		t.Errorf("Expected orders topics with at most 10 partitions, got %s", got)
+	}
+}
+
+func TestTopicManagerListTopicsExclude(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	for _, name := range []string{"orders", "orders-tmp", "payments", "tmp-scratch", "connect-offsets"} {
+		mock.AddMockTopic(name, 1, 1)
+	}
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	list := func(opts *types.ListOptions) string {
+		t.Helper()
+		opts.Page, opts.PageSize = 1, 100
+		topicList, err := tm.ListTopics(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("ListTopics failed: %v", err)
+		}
+		var names []string
+		for _, topic := range topicList.Topics {
+			names = append(names, topic.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := list(&types.ListOptions{Exclude: []string{"tmp-*", "*-tmp", "connect-*"}}); got != "orders,payments" {
+		t.Errorf("Expected excluded topics to be hidden, got %s", got)
+	}
+
+	// Excludes are applied after the include pattern
+	if got := list(&types.ListOptions{Pattern: "orders*", Exclude: []string{"*-tmp"}}); got != "orders" {
+		t.Errorf("Expected only orders, got %s", got)
+	}
+}
+
+func TestTopicManagerListTopicsDeadline(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 50, 1)
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+
+	_, err := tm.ListTopics(ctx, &types.ListOptions{Page: 1, PageSize: 10, WithSize: true})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestTopicManagerCanceledContext(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 3, 1)
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	operations := map[string]func() error{
+		"ListTopics": func() error {
+			_, err := tm.ListTopics(ctx, &types.ListOptions{Page: 1, PageSize: 10})
+			return err
+		},
+		"DescribeTopic": func() error {
+			_, err := tm.DescribeTopic(ctx, "orders", &types.DescribeTopicOptions{WithOffsets: true})
+			return err
+		},
+		"DescribeTopicConfig": func() error {
+			_, err := tm.DescribeTopicConfig(ctx, "orders")
+			return err
+		},
+		"CreateTopic": func() error {
+			return tm.CreateTopic(ctx, &types.CreateTopicRequest{Name: "payments", Partitions: 1, ReplicationFactor: 1})
+		},
+		"DeleteTopic": func() error {
+			return tm.DeleteTopic(ctx, "orders")
+		},
+	}
+	for name, operation := range operations {
+		if err := operation(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+
+	if calls := mock.DescribeTopicsCalls(); len(calls) != 0 {
+		t.Errorf("Expected no topics to be described, got %v", calls)
+	}
+	if _, exists := mock.MockTopic("payments"); exists {
+		t.Error("Expected the topic not to be created")
+	}
+	if _, exists := mock.MockTopic("orders"); !exists {
+		t.Error("Expected the topic not to be deleted")
+	}
+}
+
+func TestTopicManagerListTopicsInternalFilter(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 3, 1)
+	mock.AddMockTopic("__consumer_offsets", 50, 1)
+	if meta, exists := mock.MockTopic("__consumer_offsets"); exists {
+		meta.IsInternal = true
+	}
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	list := func(internal string) string {
+		t.Helper()
+		topicList, err := tm.ListTopics(context.Background(), &types.ListOptions{Page: 1, PageSize: 100, Internal: internal})
+		if err != nil {
+			t.Fatalf("ListTopics failed: %v", err)
+		}
+		var names []string
+		for _, topic := range topicList.Topics {
+			names = append(names, topic.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		internal string
+		expected string
+	}{
+		{"", "orders"},
+		{types.InternalExclude, "orders"},
+		{types.InternalInclude, "__consumer_offsets,orders"},
+		{types.InternalOnly, "__consumer_offsets"},
+	}
+	for _, tt := range tests {
+		if got := list(tt.internal); got != tt.expected {
+			t.Errorf("Internal %q: expected %s, got %s", tt.internal, tt.expected, got)
+		}
+	}
+}
+
+func TestTopicManagerListTopicsWithoutDescribeAll(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 6, 3)
+	mock.AddMockTopic("payments", 3, 2)
+	mock.AddMockTopic("__consumer_offsets", 50, 3)
+	if meta, exists := mock.MockTopic("__consumer_offsets"); exists {
+		meta.IsInternal = true
+	}
+
+	// DescribeTopics(nil) returns nothing, but ListTopics knows every topic
+	mock.SetNoDescribeAllTopics(true)
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	topicList, err := tm.ListTopics(context.Background(), &types.ListOptions{Page: 1, PageSize: 10, Internal: types.InternalInclude})
+	if err != nil {
+		t.Fatalf("ListTopics failed: %v", err)
+	}
+
+	if len(topicList.Topics) != 3 {
+		t.Fatalf("Expected 3 topics, got %d", len(topicList.Topics))
+	}
+
+	byName := make(map[string]*types.TopicInfo)
+	for _, topic := range topicList.Topics {
+		byName[topic.Name] = topic
+	}
+	if orders := byName["orders"]; orders == nil || orders.Partitions != 6 || orders.ReplicationFactor != 3 {
+		t.Errorf("Unexpected orders topic: %+v", orders)
+	}
+	if payments := byName["payments"]; payments == nil || payments.ReplicationFactor != 2 {
+		t.Errorf("Unexpected payments topic: %+v", payments)
+	}
+	if offsets := byName["__consumer_offsets"]; offsets == nil || !offsets.Internal {
+		t.Errorf("Expected __consumer_offsets to be internal: %+v", offsets)
+	}
+
+	for _, call := range mock.DescribeTopicsCalls() {
+		if len(call) == 0 {
+			t.Error("ListTopics should describe topics by name")
+		}
+	}
+}
+
+func TestTopicManagerListTopicsWithSize(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockBroker(0, "broker-0:9092")
+	mock.AddMockBroker(1, "broker-1:9092")
+	mock.AddMockTopic("orders", 2, 2)
+	mock.AddMockTopic("payments", 1, 1)
+
+	// orders: partition 0 has offsets 3-10, partition 1 has offsets 0-5
+	p0 := mock.Consumer().AddMockPartition("orders", 0)
+	for i := 0; i < 10; i++ {
+		p0.SendMockMessage("", "value")
+	}
+	p0.SetLogStartOffset(3)
+	p1 := mock.Consumer().AddMockPartition("orders", 1)
+	for i := 0; i < 5; i++ {
+		p1.SendMockMessage("", "value")
+	}
+	mock.Consumer().AddMockPartition("payments", 0)
+
+	// Broker 0 leads every partition; follower replicas must not be counted
+	mock.AddMockLogDir(0, "orders", 0, 700)
+	mock.AddMockLogDir(0, "orders", 1, 500)
+	mock.AddMockLogDir(1, "orders", 0, 700)
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	topicList, err := tm.ListTopics(context.Background(), &types.ListOptions{Page: 1, PageSize: 10, WithSize: true})
+	if err != nil {
+		t.Fatalf("ListTopics failed: %v", err)
+	}
+
+	orders, payments := topicList.Topics[0], topicList.Topics[1]
+	if orders.MessageCount == nil || *orders.MessageCount != 12 {
+		t.Errorf("Expected 12 messages in orders, got %v", orders.MessageCount)
+	}
+	if orders.SizeBytes == nil || *orders.SizeBytes != 1200 {
+		t.Errorf("Expected 1200 bytes in orders, got %v", orders.SizeBytes)
+	}
+	if payments.MessageCount == nil || *payments.MessageCount != 0 {
+		t.Errorf("Expected no messages in payments, got %v", payments.MessageCount)
+	}
+	if payments.SizeBytes != nil {
+		t.Errorf("Expected no size for payments without log dirs, got %d", *payments.SizeBytes)
+	}
+
+	// Sizes are only computed when requested
+	topicList, err = tm.ListTopics(context.Background(), &types.ListOptions{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListTopics failed: %v", err)
+	}
+	if topicList.Topics[0].MessageCount != nil || topicList.Topics[0].SizeBytes != nil {
+		t.Errorf("Expected no size without WithSize, got %+v", topicList.Topics[0])
+	}
+}
+
+func TestTopicManagerListTopicsSortByMessages(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	counts := map[string]int{"orders": 5, "payments": 12, "users": 1}
+	for name, count := range counts {
+		mock.AddMockTopic(name, 1, 1)
+		p0 := mock.Consumer().AddMockPartition(name, 0)
+		for i := 0; i < count; i++ {
+			p0.SendMockMessage("", "value")
+		}
+	}
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	// Sizing all topics before paging puts the biggest topics on the first page
+	topicList, err := tm.ListTopics(context.Background(), &types.ListOptions{
+		Page:     1,
+		PageSize: 2,
+		SortBy:   "messages",
+		Order:    "desc",
+		WithSize: true,
+	})
+	if err != nil {
+		t.Fatalf("ListTopics failed: %v", err)
+	}
+
+	if len(topicList.Topics) != 2 {
+		t.Fatalf("Expected 2 topics on the first page, got %d", len(topicList.Topics))
+	}
+	for i, want := range []string{"payments", "orders"} {
+		if topicList.Topics[i].Name != want {
+			t.Errorf("Expected topic %d to be %s, got %s", i, want, topicList.Topics[i].Name)
+		}
+	}
+	if count := topicList.Topics[0].MessageCount; count == nil || *count != 12 {
+		t.Errorf("Expected 12 messages in payments, got %v", count)
+	}
+
+	// Sorting by size without sizes is an error
+	_, err = tm.ListTopics(context.Background(), &types.ListOptions{Page: 1, PageSize: 10, SortBy: "size"})
+	if err == nil || !strings.Contains(err.Error(), "--with-size") {
+		t.Errorf("Expected an error mentioning --with-size, got %v", err)
+	}
+}
+
+func TestTopicManagerTruncateTopic(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 2, 1)
+	p0 := mock.Consumer().AddMockPartition("orders", 0)
+	for i := 0; i < 5; i++ {
+		p0.SendMockMessage("", "value")
+	}
+	p1 := mock.Consumer().AddMockPartition("orders", 1)
+	p1.SendMockMessage("", "value")
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	// Only the requested partition is truncated
+	offsets, err := tm.TruncateTopic(context.Background(), "orders", []int32{1})
+	if err != nil {
+		t.Fatalf("TruncateTopic failed: %v", err)
+	}
+	if len(offsets) != 1 || offsets[1] != 1 {
+		t.Errorf("Expected partition 1 truncated to offset 1, got %v", offsets)
+	}
+
+	// Without partitions every partition is truncated to its latest offset
+	if _, err := tm.TruncateTopic(context.Background(), "orders", nil); err != nil {
+		t.Fatalf("TruncateTopic failed: %v", err)
+	}
+
+	calls := mock.DeleteRecordsCalls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 DeleteRecords calls, got %d", len(calls))
+	}
+	if calls[1].Topic != "orders" || len(calls[1].Offsets) != 2 || calls[1].Offsets[0] != 5 || calls[1].Offsets[1] != 1 {
+		t.Errorf("Expected records deleted up to offsets 5 and 1, got %+v", calls[1])
+	}
+
+	oldest, err := mock.GetOffset("orders", 0, sarama.OffsetOldest)
+	if err != nil || oldest != 5 {
+		t.Errorf("Expected partition 0 to start at offset 5, got %d (%v)", oldest, err)
+	}
+
+	if _, err := tm.TruncateTopic(context.Background(), "orders", []int32{7}); err == nil {
+		t.Error("Expected an error for a missing partition")
+	}
+	if _, err := tm.TruncateTopic(context.Background(), "missing", nil); err == nil {
+		t.Error("Expected an error for a missing topic")
+	}
+	if len(mock.DeleteRecordsCalls()) != 2 {
+		t.Error("Expected no records to be deleted for invalid requests")
+	}
+}
+
+func TestTopicManagerCopyConfig(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 6, 3)
+	mock.AddMockConfig(sarama.TopicResource, "orders",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "86400000", Source: sarama.SourceTopic},
+		sarama.ConfigEntry{Name: "cleanup.policy", Value: "delete", Default: true, Source: sarama.SourceDefault},
+		sarama.ConfigEntry{Name: "min.insync.replicas", Value: "2", Source: sarama.SourceStaticBroker},
+		sarama.ConfigEntry{Name: "message.format.version", Value: "3.0", ReadOnly: true, Source: sarama.SourceTopic},
+	)
+	mock.AddMockTopic("payments", 1, 1)
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	// A new topic is created with the source's overrides and layout
+	copied, err := tm.CopyConfig(context.Background(), "orders", "orders-copy", true)
+	if err != nil {
+		t.Fatalf("CopyConfig failed: %v", err)
+	}
+	if len(copied) != 1 || copied["retention.ms"] != "86400000" {
+		t.Errorf("Expected only the retention override to be copied, got %v", copied)
+	}
+
+	calls := mock.CreateTopicCalls()
+	if len(calls) != 1 || calls[0].Topic != "orders-copy" {
+		t.Fatalf("Expected orders-copy to be created, got %+v", calls)
+	}
+	detail := calls[0].Detail
+	if detail.NumPartitions != 6 || detail.ReplicationFactor != 3 {
+		t.Errorf("Expected 6 partitions and replication factor 3, got %d and %d", detail.NumPartitions, detail.ReplicationFactor)
+	}
+	if len(detail.ConfigEntries) != 1 || detail.ConfigEntries["retention.ms"] == nil || *detail.ConfigEntries["retention.ms"] != "86400000" {
+		t.Errorf("Expected orders-copy to be created with the retention override, got %v", detail.ConfigEntries)
+	}
+
+	// Without the layout the broker defaults apply
+	if _, err := tm.CopyConfig(context.Background(), "orders", "orders-default", false); err != nil {
+		t.Fatalf("CopyConfig failed: %v", err)
+	}
+	if detail := mock.CreateTopicCalls()[1].Detail; detail.NumPartitions != -1 || detail.ReplicationFactor != -1 {
+		t.Errorf("Expected broker defaults, got %d partitions and replication factor %d", detail.NumPartitions, detail.ReplicationFactor)
+	}
+
+	// An existing topic is altered
+	if _, err := tm.CopyConfig(context.Background(), "orders", "payments", false); err != nil {
+		t.Fatalf("CopyConfig failed: %v", err)
+	}
+	config, err := tm.DescribeTopicConfig(context.Background(), "payments")
+	if err != nil {
+		t.Fatalf("DescribeTopicConfig failed: %v", err)
+	}
+	if entry := config.Configs["retention.ms"]; entry == nil || entry.Value != "86400000" {
+		t.Errorf("Expected payments to get the retention override, got %+v", config.Configs)
+	}
+
+	if _, err := tm.CopyConfig(context.Background(), "orders", "payments", true); err == nil {
+		t.Error("Expected an error copying the layout to an existing topic")
+	}
+	if _, err := tm.CopyConfig(context.Background(), "missing", "other", false); err == nil {
+		t.Error("Expected an error for a missing source topic")
+	}
+}
+
+func TestTopicManagerDiffTopics(t *testing.T) {
+	logger := testutil.TestLogger()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders-staging", 3, 1)
+	mock.AddMockConfig(sarama.TopicResource, "orders-staging",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "86400000", Source: sarama.SourceTopic},
+		sarama.ConfigEntry{Name: "cleanup.policy", Value: "delete", Default: true, Source: sarama.SourceDefault},
+	)
+	mock.AddMockTopic("orders-prod", 3, 1)
+	mock.AddMockConfig(sarama.TopicResource, "orders-prod",
+		sarama.ConfigEntry{Name: "retention.ms", Value: "604800000", Source: sarama.SourceTopic},
+		sarama.ConfigEntry{Name: "cleanup.policy", Value: "delete", Default: true, Source: sarama.SourceDefault},
+	)
+	mock.AddMockTopic("payments", 6, 3)
+
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	diff, err := tm.DiffTopics(context.Background(), "orders-staging", "orders-prod")
+	if err != nil {
+		t.Fatalf("DiffTopics failed: %v", err)
+	}
+	if len(diff.Structure) != 0 {
+		t.Errorf("Expected no structural differences, got %d", len(diff.Structure))
+	}
+	if len(diff.Configs) != 1 {
+		t.Fatalf("Expected exactly one differing config, got %d", len(diff.Configs))
+	}
+	entry := diff.Configs[0]
+	if entry.Key != "retention.ms" || entry.A == nil || *entry.A != "86400000" || entry.B == nil || *entry.B != "604800000" {
+		t.Errorf("Expected retention.ms to differ, got %+v", entry)
+	}
+
+	// Layout differences and keys set on one side only
+	diff, err = tm.DiffTopics(context.Background(), "orders-staging", "payments")
+	if err != nil {
+		t.Fatalf("DiffTopics failed: %v", err)
+	}
+	if len(diff.Structure) != 2 || diff.Structure[0].Key != "partitions" || diff.Structure[1].Key != "replication_factor" {
+		t.Errorf("Expected partitions and replication factor to differ, got %d entries", len(diff.Structure))
+	}
+	for _, entry := range diff.Configs {
+		if entry.A == nil || entry.B != nil {
+			t.Errorf("Expected %s to be set on the first topic only", entry.Key)
+		}
+	}
+
+	if _, err := tm.DiffTopics(context.Background(), "orders-staging", "missing"); err == nil {
+		t.Error("Expected an error for a missing topic")
+	}
+}
+
+func TestTopicManagerExists(t *testing.T) {
+	logger := testutil.TestLogger()
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 3, 1)
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	exists, err := tm.Exists(context.Background(), "orders")
+	if err != nil || !exists {
+		t.Errorf("Expected orders to exist, got %t (error: %v)", exists, err)
+	}
+
+	exists, err = tm.Exists(context.Background(), "missing")
+	if err != nil || exists {
+		t.Errorf("Expected missing not to exist, got %t (error: %v)", exists, err)
+	}
+
+	mock.SetShouldFailOps(true)
+	if _, err := tm.Exists(context.Background(), "orders"); err == nil {
+		t.Error("Exists should fail when topics cannot be listed")
+	}
+}
+
+func TestTopicManagerDescribeTopics(t *testing.T) {
+	logger := testutil.TestLogger()
+	mock := testutil.NewMockClient(testutil.TestProfile(), logger)
+	mock.AddMockTopic("orders", 3, 1)
+	mock.AddMockTopic("payments", 2, 1)
+	mock.AddMockTopic("shipments", 1, 1)
+	tm := NewTopicManager(mock.KafkaClient(), logger)
+
+	names := []string{"orders", "payments", "missing", "shipments"}
+	results := tm.DescribeTopics(context.Background(), names, nil)
+
+	if len(results) != len(names) {
+		t.Fatalf("Expected %d results, got %d", len(names), len(results))
+	}
+
+	// Results keep the requested order and the missing topic does not affect the others
+	expectedPartitions := map[string]int32{"orders": 3, "payments": 2, "shipments": 1}
+	for i, result := range results {
+		if result.Name != names[i] {
+			t.Errorf("Result %d: expected topic %s, got %s", i, names[i], result.Name)
+		}
+		if result.Name == "missing" {
+			if result.Error == "" || result.Details != nil {
+				t.Errorf("Expected an error for the missing topic, got %+v", result)
+			}
+			continue
+		}
+		if result.Error != "" || result.Details == nil {
+			t.Fatalf("Describe of %s failed: %s", result.Name, result.Error)
+		}
+		if result.Details.Partitions != expectedPartitions[result.Name] {
+			t.Errorf("%s: expected %d partitions, got %d", result.Name, expectedPartitions[result.Name], result.Details.Partitions)
+		}
+	}
+
+	if calls := mock.DescribeTopicsCalls(); len(calls) != len(names) {
+		t.Errorf("Expected one describe call per topic, got %d", len(calls))
+	}
+}
+
+func TestMatchesPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		expected bool
+	}{
+		{"tmp-orders", "tmp-*", true},
+		{"orders-tmp-1", "tmp-*", false},
+		{"orders", "orders", true},
+		{"orders-v2", "orders", false},
+		{"orders-1", "orders-?", true},
+		{"orders-12", "orders-?", false},
+		{"orders-1", "orders-[0-9]", true},
+		{"orders", "[", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesPattern(tt.name, tt.pattern); got != tt.expected {
+			t.Errorf("matchesPattern(%q, %q) = %t, want %t", tt.name, tt.pattern, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nipunap/kim/internal/logger"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// shutdownTimeout bounds how long Shutdown waits for in-flight scrapes
+const shutdownTimeout = 5 * time.Second
+
+// ConsumerMetrics tracks consume throughput and lag per topic partition for
+// export to Prometheus
+type ConsumerMetrics struct {
+	registry *prometheus.Registry
+	messages *prometheus.CounterVec
+	bytes    *prometheus.CounterVec
+	lag      *prometheus.GaugeVec
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewConsumerMetrics creates consumer metrics in their own registry
+func NewConsumerMetrics() *ConsumerMetrics {
+	labels := []string{"topic", "partition"}
+
+	m := &ConsumerMetrics{
+		registry: prometheus.NewRegistry(),
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "messages_consumed_total",
+			Help: "Number of messages consumed.",
+		}, labels),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bytes_consumed_total",
+			Help: "Number of key and value bytes consumed.",
+		}, labels),
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "consumer_lag",
+			Help: "Messages between the last consumed offset and the high water mark.",
+		}, labels),
+	}
+	m.registry.MustRegister(m.messages, m.bytes, m.lag)
+
+	return m
+}
+
+// Observe records a consumed message and the partition's high water mark
+func (m *ConsumerMetrics) Observe(msg *sarama.ConsumerMessage, highWaterMark int64) {
+	topic, partition := msg.Topic, strconv.Itoa(int(msg.Partition))
+
+	m.messages.WithLabelValues(topic, partition).Inc()
+	m.bytes.WithLabelValues(topic, partition).Add(float64(len(msg.Key) + len(msg.Value)))
+
+	lag := highWaterMark - msg.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+	m.lag.WithLabelValues(topic, partition).Set(float64(lag))
+}
+
+// Handler returns an HTTP handler serving the metrics in the Prometheus format
+func (m *ConsumerMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Serve starts serving the metrics on /metrics at addr. Listen errors, such as
+// the port being in use, are returned before Serve returns.
+func (m *ConsumerMetrics) Serve(addr string, log *logger.Logger) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	m.listener = listener
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	m.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := m.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	log.Info("Serving metrics", "address", listener.Addr().String())
+	return nil
+}
+
+// Shutdown stops the metrics server started by Serve
+func (m *ConsumerMetrics) Shutdown() error {
+	if m.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return m.server.Shutdown(ctx)
+}
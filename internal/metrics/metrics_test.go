@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nipunap/kim/internal/logger"
+
+	"github.com/IBM/sarama"
+)
+
+// scrape returns the metrics served by handler in the Prometheus text format
+func scrape(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return recorder.Body.String()
+}
+
+func TestConsumerMetricsObserve(t *testing.T) {
+	m := NewConsumerMetrics()
+
+	for offset := int64(0); offset < 3; offset++ {
+		m.Observe(&sarama.ConsumerMessage{
+			Topic:     "orders",
+			Partition: 1,
+			Offset:    offset,
+			Key:       []byte("k"),
+			Value:     []byte("value"),
+		}, 10)
+	}
+
+	output := scrape(t, m.Handler())
+
+	expected := []string{
+		`messages_consumed_total{partition="1",topic="orders"} 3`,
+		`bytes_consumed_total{partition="1",topic="orders"} 18`,
+		// The last consumed offset is 2, so offsets 3..9 are still to be read
+		`consumer_lag{partition="1",topic="orders"} 7`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected %q in metrics output:\n%s", line, output)
+		}
+	}
+}
+
+func TestConsumerMetricsServe(t *testing.T) {
+	m := NewConsumerMetrics()
+	if err := m.Serve("127.0.0.1:0", logger.New()); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	defer m.Shutdown()
+
+	m.Observe(&sarama.ConsumerMessage{Topic: "orders", Partition: 0, Offset: 0}, 1)
+
+	resp, err := http.Get("http://" + m.listener.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatalf("Failed to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `messages_consumed_total{partition="0",topic="orders"} 1`) {
+		t.Errorf("Unexpected metrics output:\n%s", body)
+	}
+}
@@ -0,0 +1,71 @@
+package serde
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// AvroDeserializer decodes Avro messages using schemas from a schema registry
+type AvroDeserializer struct {
+	registry *RegistryClient
+	codecs   map[uint32]*goavro.Codec
+	mutex    sync.Mutex
+}
+
+// NewAvroDeserializer creates a new Avro deserializer for the given registry URL
+func NewAvroDeserializer(registryURL string) *AvroDeserializer {
+	return &AvroDeserializer{
+		registry: NewRegistryClient(registryURL),
+		codecs:   make(map[uint32]*goavro.Codec),
+	}
+}
+
+// Decode decodes an Avro message into its JSON representation
+func (ad *AvroDeserializer) Decode(topic string, data []byte) (string, error) {
+	schemaID, payload, err := splitWireFormat(data)
+	if err != nil {
+		return "", err
+	}
+
+	codec, err := ad.codec(schemaID)
+	if err != nil {
+		return "", err
+	}
+
+	native, _, err := codec.NativeFromBinary(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode Avro message from topic %s: %w", topic, err)
+	}
+
+	textual, err := codec.TextualFromNative(nil, native)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert Avro message to JSON: %w", err)
+	}
+
+	return string(textual), nil
+}
+
+// codec returns the cached codec for a schema ID, fetching the schema on first use
+func (ad *AvroDeserializer) codec(id uint32) (*goavro.Codec, error) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+
+	if codec, exists := ad.codecs[id]; exists {
+		return codec, nil
+	}
+
+	schema, err := ad.registry.GetSchema(id)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Avro schema %d: %w", id, err)
+	}
+
+	ad.codecs[id] = codec
+	return codec, nil
+}
@@ -0,0 +1,113 @@
+package serde
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+const testUserSchema = `{
+  "type": "record",
+  "name": "User",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "age", "type": "int"}
+  ]
+}`
+
+// newFakeRegistry starts a schema registry serving a single schema under ID 1
+func newFakeRegistry(t *testing.T, requests *int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		if r.URL.Path != "/schemas/ids/1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"schema": testUserSchema})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// encodeAvro encodes a record in the Confluent wire format
+func encodeAvro(t *testing.T, schemaID uint32, record map[string]interface{}) []byte {
+	t.Helper()
+
+	codec, err := goavro.NewCodec(testUserSchema)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	data := make([]byte, 5)
+	binary.BigEndian.PutUint32(data[1:], schemaID)
+
+	data, err = codec.BinaryFromNative(data, record)
+	if err != nil {
+		t.Fatalf("Failed to encode record: %v", err)
+	}
+
+	return data
+}
+
+func TestAvroDeserializerDecode(t *testing.T) {
+	var requests int32
+	server := newFakeRegistry(t, &requests)
+
+	deserializer := NewAvroDeserializer(server.URL + "/")
+	data := encodeAvro(t, 1, map[string]interface{}{"name": "alice", "age": 30})
+
+	for i := 0; i < 2; i++ {
+		decoded, err := deserializer.Decode("users", data)
+		if err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(decoded), &record); err != nil {
+			t.Fatalf("Decoded value is not JSON: %v (%s)", err, decoded)
+		}
+		if record["name"] != "alice" || record["age"] != float64(30) {
+			t.Errorf("Unexpected decoded record: %v", record)
+		}
+	}
+
+	// The schema is cached after the first lookup
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected 1 registry request, got %d", got)
+	}
+}
+
+func TestAvroDeserializerDecodeErrors(t *testing.T) {
+	var requests int32
+	server := newFakeRegistry(t, &requests)
+
+	deserializer := NewAvroDeserializer(server.URL)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"too short", []byte{0, 0, 1}},
+		{"wrong magic byte", []byte{1, 0, 0, 0, 1, 2}},
+		{"unknown schema", encodeAvro(t, 2, map[string]interface{}{"name": "bob", "age": 1})},
+		{"truncated payload", encodeAvro(t, 1, map[string]interface{}{"name": "bob", "age": 1})[:6]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := deserializer.Decode("users", tt.data); err == nil {
+				t.Error("Decode should fail")
+			}
+		})
+	}
+}
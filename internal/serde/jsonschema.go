@@ -0,0 +1,57 @@
+package serde
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// JSONSchemaValidator validates JSON message values against a JSON Schema
+type JSONSchemaValidator struct {
+	schema *jsonschema.Schema
+}
+
+// NewJSONSchemaValidator creates a validator for the JSON Schema in schemaFile
+func NewJSONSchemaValidator(schemaFile string) (*JSONSchemaValidator, error) {
+	schema, err := jsonschema.Compile(schemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema %s: %w", schemaFile, err)
+	}
+
+	return &JSONSchemaValidator{schema: schema}, nil
+}
+
+// Validate checks that value is JSON matching the schema. Validation errors
+// name the location of the offending field as a JSON pointer.
+func (jv *JSONSchemaValidator) Validate(value string) error {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Errorf("value is not valid JSON: %w", err)
+	}
+
+	if err := jv.schema.Validate(document); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+
+		leaf := validationErr
+		for len(leaf.Causes) > 0 {
+			leaf = leaf.Causes[0]
+		}
+
+		location := leaf.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		return fmt.Errorf("value does not match schema at %s: %s", location, leaf.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package serde
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testUserJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "name": {"type": "string"},
+    "age": {"type": "integer", "minimum": 0}
+  },
+  "required": ["name", "age"]
+}`
+
+// writeJSONSchema writes the test user schema to a temporary file
+func writeJSONSchema(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "user.json")
+	if err := os.WriteFile(path, []byte(testUserJSONSchema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	return path
+}
+
+func TestJSONSchemaValidatorValidate(t *testing.T) {
+	validator, err := NewJSONSchemaValidator(writeJSONSchema(t))
+	if err != nil {
+		t.Fatalf("NewJSONSchemaValidator failed: %v", err)
+	}
+
+	if err := validator.Validate(`{"name": "alice", "age": 30}`); err != nil {
+		t.Errorf("Valid payload rejected: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		location string
+	}{
+		{"wrong type", `{"name": "alice", "age": "thirty"}`, "/age"},
+		{"below minimum", `{"name": "alice", "age": -1}`, "/age"},
+		{"missing field", `{"name": "alice"}`, "at /:"},
+		{"not JSON", `name=alice`, "not valid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.value)
+			if err == nil {
+				t.Fatal("Validate should fail")
+			}
+			if !strings.Contains(err.Error(), tt.location) {
+				t.Errorf("Expected error to contain %q, got %q", tt.location, err.Error())
+			}
+		})
+	}
+}
+
+func TestNewJSONSchemaValidatorErrors(t *testing.T) {
+	if _, err := NewJSONSchemaValidator(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing schema file")
+	}
+
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(path, []byte(`{"type": 5}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if _, err := NewJSONSchemaValidator(path); err == nil {
+		t.Error("Expected error for invalid schema")
+	}
+}
@@ -0,0 +1,41 @@
+package serde
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// StringDeserializer renders data as a UTF-8 string
+type StringDeserializer struct{}
+
+// Decode returns data as a string
+func (StringDeserializer) Decode(topic string, data []byte) (string, error) {
+	return string(data), nil
+}
+
+// Int32Deserializer decodes 4-byte big-endian signed integers, as written by
+// the Java IntegerSerializer
+type Int32Deserializer struct{}
+
+// Decode decodes a 4-byte big-endian integer
+func (Int32Deserializer) Decode(topic string, data []byte) (string, error) {
+	if len(data) != 4 {
+		return "", fmt.Errorf("int32 requires 4 bytes, got %d", len(data))
+	}
+
+	return strconv.FormatInt(int64(int32(binary.BigEndian.Uint32(data))), 10), nil
+}
+
+// Int64Deserializer decodes 8-byte big-endian signed integers, as written by
+// the Java LongSerializer
+type Int64Deserializer struct{}
+
+// Decode decodes an 8-byte big-endian integer
+func (Int64Deserializer) Decode(topic string, data []byte) (string, error) {
+	if len(data) != 8 {
+		return "", fmt.Errorf("int64 requires 8 bytes, got %d", len(data))
+	}
+
+	return strconv.FormatInt(int64(binary.BigEndian.Uint64(data)), 10), nil
+}
@@ -0,0 +1,39 @@
+package serde
+
+import "testing"
+
+func TestPrimitiveDeserializers(t *testing.T) {
+	tests := []struct {
+		name         string
+		deserializer Deserializer
+		data         []byte
+		expected     string
+	}{
+		{"string", StringDeserializer{}, []byte("user123"), "user123"},
+		{"int32", Int32Deserializer{}, []byte{0x00, 0x00, 0x01, 0x00}, "256"},
+		{"negative int32", Int32Deserializer{}, []byte{0xff, 0xff, 0xff, 0xfe}, "-2"},
+		{"int64", Int64Deserializer{}, []byte{0, 0, 0, 0, 0, 0, 0x30, 0x39}, "12345"},
+		{"negative int64", Int64Deserializer{}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := tt.deserializer.Decode("users", tt.data)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if decoded != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, decoded)
+			}
+		})
+	}
+}
+
+func TestIntDeserializersRejectWrongLength(t *testing.T) {
+	if _, err := (Int32Deserializer{}).Decode("users", []byte{0, 1}); err == nil {
+		t.Error("Int32Deserializer should reject 2 bytes")
+	}
+	if _, err := (Int64Deserializer{}).Decode("users", []byte{0, 0, 0, 1}); err == nil {
+		t.Error("Int64Deserializer should reject 4 bytes")
+	}
+}
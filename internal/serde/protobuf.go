@@ -0,0 +1,64 @@
+package serde
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtobufDeserializer decodes Protobuf messages using a compiled descriptor set
+type ProtobufDeserializer struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+// NewProtobufDeserializer creates a Protobuf deserializer for a message type in a
+// FileDescriptorSet, as produced by `protoc --include_imports --descriptor_set_out`
+func NewProtobufDeserializer(descriptorFile, messageName string) (*ProtobufDeserializer, error) {
+	data, err := os.ReadFile(descriptorFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor set: %w", err)
+	}
+
+	var descriptorSet descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &descriptorSet); err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor set: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&descriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(messageName))
+	if err != nil {
+		return nil, fmt.Errorf("message %s not found in descriptor set: %w", messageName, err)
+	}
+
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message type", messageName)
+	}
+
+	return &ProtobufDeserializer{descriptor: messageDescriptor}, nil
+}
+
+// Decode decodes a Protobuf message into its JSON representation
+func (pd *ProtobufDeserializer) Decode(topic string, data []byte) (string, error) {
+	message := dynamicpb.NewMessage(pd.descriptor)
+	if err := proto.Unmarshal(data, message); err != nil {
+		return "", fmt.Errorf("failed to decode Protobuf message from topic %s: %w", topic, err)
+	}
+
+	textual, err := protojson.Marshal(message)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert Protobuf message to JSON: %w", err)
+	}
+
+	return string(textual), nil
+}
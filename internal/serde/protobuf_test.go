@@ -0,0 +1,94 @@
+package serde
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// writeDescriptorSet writes a descriptor set containing example.User { string name = 1; int32 age = 2; }
+func writeDescriptorSet(t *testing.T) string {
+	t.Helper()
+
+	descriptorSet := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("user.proto"),
+			Package: proto.String("example"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{
+						Name:     proto.String("name"),
+						JsonName: proto.String("name"),
+						Number:   proto.Int32(1),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					},
+					{
+						Name:     proto.String("age"),
+						JsonName: proto.String("age"),
+						Number:   proto.Int32(2),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+					},
+				},
+			}},
+		}},
+	}
+
+	data, err := proto.Marshal(descriptorSet)
+	if err != nil {
+		t.Fatalf("Failed to marshal descriptor set: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "user.desc")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write descriptor set: %v", err)
+	}
+
+	return path
+}
+
+func TestProtobufDeserializerDecode(t *testing.T) {
+	deserializer, err := NewProtobufDeserializer(writeDescriptorSet(t), "example.User")
+	if err != nil {
+		t.Fatalf("NewProtobufDeserializer failed: %v", err)
+	}
+
+	// name: "alice" (field 1, length delimited), age: 30 (field 2, varint)
+	payload := []byte{0x0a, 0x05, 'a', 'l', 'i', 'c', 'e', 0x10, 0x1e}
+
+	decoded, err := deserializer.Decode("users", payload)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(decoded), &record); err != nil {
+		t.Fatalf("Decoded value is not JSON: %v (%s)", err, decoded)
+	}
+	if record["name"] != "alice" || record["age"] != float64(30) {
+		t.Errorf("Unexpected decoded record: %v", record)
+	}
+
+	// Truncated payloads cannot be decoded
+	if _, err := deserializer.Decode("users", payload[:4]); err == nil {
+		t.Error("Decode should fail for a truncated payload")
+	}
+}
+
+func TestNewProtobufDeserializerErrors(t *testing.T) {
+	path := writeDescriptorSet(t)
+
+	if _, err := NewProtobufDeserializer(path, "example.Missing"); err == nil {
+		t.Error("Expected error for unknown message type")
+	}
+	if _, err := NewProtobufDeserializer(filepath.Join(t.TempDir(), "missing.desc"), "example.User"); err == nil {
+		t.Error("Expected error for missing descriptor file")
+	}
+}
@@ -0,0 +1,48 @@
+package serde
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// registryTimeout bounds each request to the schema registry
+const registryTimeout = 10 * time.Second
+
+// RegistryClient fetches schemas from a Confluent compatible schema registry
+type RegistryClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewRegistryClient creates a new schema registry client
+func NewRegistryClient(url string) *RegistryClient {
+	return &RegistryClient{
+		url:        strings.TrimRight(url, "/"),
+		httpClient: &http.Client{Timeout: registryTimeout},
+	}
+}
+
+// GetSchema returns the schema registered under the given ID
+func (rc *RegistryClient) GetSchema(id uint32) (string, error) {
+	resp, err := rc.httpClient.Get(fmt.Sprintf("%s/schemas/ids/%d", rc.url, id))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch schema %d: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch schema %d: registry returned %s", id, resp.Status)
+	}
+
+	var body struct {
+		Schema string `json:"schema"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse schema %d: %w", id, err)
+	}
+
+	return body.Schema, nil
+}
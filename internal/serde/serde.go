@@ -0,0 +1,27 @@
+package serde
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// magicByte is the first byte of every message in the Confluent wire format
+const magicByte = 0
+
+// Deserializer decodes raw message bytes into a human readable form
+type Deserializer interface {
+	Decode(topic string, data []byte) (string, error)
+}
+
+// splitWireFormat returns the schema ID and payload of a message encoded
+// in the Confluent wire format (magic byte followed by a 4-byte schema ID)
+func splitWireFormat(data []byte) (uint32, []byte, error) {
+	if len(data) < 5 {
+		return 0, nil, fmt.Errorf("message too short for schema registry framing: %d bytes", len(data))
+	}
+	if data[0] != magicByte {
+		return 0, nil, fmt.Errorf("unknown magic byte: %d", data[0])
+	}
+
+	return binary.BigEndian.Uint32(data[1:5]), data[5:], nil
+}
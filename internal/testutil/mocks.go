@@ -1,10 +1,16 @@
 package testutil
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/nipunap/kim/internal/client"
 	"github.com/nipunap/kim/internal/config"
 	"github.com/nipunap/kim/internal/logger"
 	"github.com/nipunap/kim/pkg/types"
@@ -12,25 +18,52 @@ import (
 	"github.com/IBM/sarama"
 )
 
-// MockClient implements a mock Kafka client for testing
+// MockClient implements a mock Kafka client for testing. It also satisfies
+// sarama.ClusterAdmin so it can back a *client.Client; admin methods that are
+// not overridden here panic when called.
 type MockClient struct {
-	connected      bool
-	profile        *config.Profile
-	logger         *logger.Logger
-	topics         map[string]*sarama.TopicMetadata
-	groups         map[string]*sarama.GroupDescription
-	shouldFailPing bool
-	shouldFailOps  bool
+	sarama.ClusterAdmin
+	connected       bool
+	profile         *config.Profile
+	logger          *logger.Logger
+	topics          map[string]*sarama.TopicMetadata
+	groups          map[string]*sarama.GroupDescription
+	brokers         []*sarama.Broker
+	configs         map[string][]sarama.ConfigEntry
+	groupOffsets    map[string]map[string]map[int32]int64
+	commits         []MockCommit
+	createTopics    []MockCreateTopic
+	deleteRecords   []MockDeleteRecords
+	elections       []MockElection
+	describeCalls   [][]string
+	describeMutex   sync.Mutex
+	logDirs         map[int32][]sarama.DescribeLogDirsResponseDirMetadata
+	noDescribeAll   bool
+	coordinators    map[string]int32
+	acls            []MockACL
+	quotas          []sarama.DescribeClientQuotasEntry
+	producer        *MockProducer
+	producerConfigs []*sarama.Config
+	consumer        *MockConsumer
+	controllerID    int32
+	shouldFailPing  bool
+	shouldFailOps   bool
 }
 
 // NewMockClient creates a new mock client
 func NewMockClient(profile *config.Profile, log *logger.Logger) *MockClient {
 	return &MockClient{
-		connected: false,
-		profile:   profile,
-		logger:    log,
-		topics:    make(map[string]*sarama.TopicMetadata),
-		groups:    make(map[string]*sarama.GroupDescription),
+		connected:    false,
+		profile:      profile,
+		logger:       log,
+		topics:       make(map[string]*sarama.TopicMetadata),
+		groups:       make(map[string]*sarama.GroupDescription),
+		configs:      make(map[string][]sarama.ConfigEntry),
+		producer:     NewMockProducer(),
+		consumer:     NewMockConsumer(),
+		groupOffsets: make(map[string]map[string]map[int32]int64),
+		coordinators: make(map[string]int32),
+		logDirs:      make(map[int32][]sarama.DescribeLogDirsResponseDirMetadata),
 	}
 }
 
@@ -70,7 +103,77 @@ func (m *MockClient) GetProfile() *config.Profile {
 	return m.profile
 }
 
+// KafkaClient returns a connected *client.Client whose admin client is this mock
+func (m *MockClient) KafkaClient() *client.Client {
+	m.connected = true
+	// Match the producer defaults of a connected client
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	c := client.NewClient(m.profile, config, m, m.consumer, m.producer, m.logger)
+	c.Offsets = m
+	c.SetProducerFactory(func(config *sarama.Config) (sarama.SyncProducer, error) {
+		m.producerConfigs = append(m.producerConfigs, config)
+		return m.producer, nil
+	})
+	return c
+}
+
+// ProducerConfigs returns the configurations of the producers created by
+// clients from this mock, in creation order
+func (m *MockClient) ProducerConfigs() []*sarama.Config {
+	return m.producerConfigs
+}
+
+// Producer returns the mock producer used by clients created from this mock
+func (m *MockClient) Producer() *MockProducer {
+	return m.producer
+}
+
+// Consumer returns the mock consumer used by clients created from this mock
+func (m *MockClient) Consumer() *MockConsumer {
+	return m.consumer
+}
+
+// ClientManager returns a client manager that always hands out this mock
+func (m *MockClient) ClientManager() *client.Manager {
+	return client.NewManagerWithFactory(m.logger, func(profile *config.Profile) (*client.Client, error) {
+		if m.shouldFailOps {
+			return nil, errors.New("mock connection failed")
+		}
+		return m.KafkaClient(), nil
+	})
+}
+
+// Close simulates closing the admin client
+func (m *MockClient) Close() error {
+	m.connected = false
+	return nil
+}
+
 // Mock admin client methods
+func (m *MockClient) DescribeCluster() ([]*sarama.Broker, int32, error) {
+	if m.shouldFailPing {
+		return nil, -1, errors.New("mock ping failed")
+	}
+	if m.shouldFailOps {
+		return nil, -1, errors.New("mock describe cluster failed")
+	}
+	return m.brokers, m.controllerID, nil
+}
+
+// DescribeConfig returns the mock config entries registered for a resource
+func (m *MockClient) DescribeConfig(resource sarama.ConfigResource) ([]sarama.ConfigEntry, error) {
+	if m.shouldFailOps {
+		return nil, errors.New("mock describe config failed")
+	}
+	return m.configs[mockConfigKey(resource.Type, resource.Name)], nil
+}
+
+// Controller is not backed by a real broker connection in the mock
+func (m *MockClient) Controller() (*sarama.Broker, error) {
+	return nil, errors.New("mock controller not available")
+}
+
 func (m *MockClient) ListTopics() (map[string]sarama.TopicDetail, error) {
 	if m.shouldFailOps {
 		return nil, errors.New("mock list topics failed")
@@ -91,6 +194,21 @@ func (m *MockClient) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, e
 		return nil, errors.New("mock describe topics failed")
 	}
 
+	m.describeMutex.Lock()
+	m.describeCalls = append(m.describeCalls, topics)
+	m.describeMutex.Unlock()
+
+	// Like sarama, an empty topic list describes every topic
+	if len(topics) == 0 {
+		if m.noDescribeAll {
+			return nil, nil
+		}
+		for name := range m.topics {
+			topics = append(topics, name)
+		}
+		sort.Strings(topics)
+	}
+
 	var result []*sarama.TopicMetadata
 	for _, topicName := range topics {
 		if meta, exists := m.topics[topicName]; exists {
@@ -100,6 +218,177 @@ func (m *MockClient) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, e
 	return result, nil
 }
 
+// CreateTopic adds a mock topic with the requested partitions and replication factor
+func (m *MockClient) CreateTopic(topic string, detail *sarama.TopicDetail, validateOnly bool) error {
+	m.createTopics = append(m.createTopics, MockCreateTopic{Topic: topic, Detail: detail, ValidateOnly: validateOnly})
+	if m.shouldFailOps {
+		return errors.New("mock create topic failed")
+	}
+	if _, exists := m.topics[topic]; exists {
+		return sarama.ErrTopicAlreadyExists
+	}
+	if !validateOnly {
+		// -1 selects the broker default, which is 1 for the mock
+		partitions, replicationFactor := int(detail.NumPartitions), int(detail.ReplicationFactor)
+		if partitions < 0 {
+			partitions = 1
+		}
+		if replicationFactor < 0 {
+			replicationFactor = 1
+		}
+		m.AddMockTopic(topic, partitions, replicationFactor)
+		m.setMockTopicConfigs(topic, detail.ConfigEntries)
+	}
+	return nil
+}
+
+// IncrementalAlterConfig sets or deletes config entries of a mock resource
+func (m *MockClient) IncrementalAlterConfig(resourceType sarama.ConfigResourceType, name string, entries map[string]sarama.IncrementalAlterConfigsEntry, validateOnly bool) error {
+	if m.shouldFailOps {
+		return errors.New("mock alter config failed")
+	}
+	if validateOnly {
+		return nil
+	}
+
+	key := mockConfigKey(resourceType, name)
+	for configName, entry := range entries {
+		kept := m.configs[key][:0]
+		for _, existing := range m.configs[key] {
+			if existing.Name != configName {
+				kept = append(kept, existing)
+			}
+		}
+		m.configs[key] = kept
+
+		if entry.Operation == sarama.IncrementalAlterConfigsOperationSet && entry.Value != nil {
+			m.configs[key] = append(m.configs[key], sarama.ConfigEntry{
+				Name:   configName,
+				Value:  *entry.Value,
+				Source: sarama.SourceTopic,
+			})
+		}
+	}
+	return nil
+}
+
+// setMockTopicConfigs registers topic config overrides given at creation
+func (m *MockClient) setMockTopicConfigs(topic string, entries map[string]*string) {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	key := mockConfigKey(sarama.TopicResource, topic)
+	for _, name := range names {
+		if entries[name] == nil {
+			continue
+		}
+		m.configs[key] = append(m.configs[key], sarama.ConfigEntry{
+			Name:   name,
+			Value:  *entries[name],
+			Source: sarama.SourceTopic,
+		})
+	}
+}
+
+// MockCreateTopic records a call to CreateTopic
+type MockCreateTopic struct {
+	Topic        string
+	Detail       *sarama.TopicDetail
+	ValidateOnly bool
+}
+
+// CreateTopicCalls returns the recorded CreateTopic calls
+func (m *MockClient) CreateTopicCalls() []MockCreateTopic {
+	return m.createTopics
+}
+
+// DeleteTopic removes a mock topic
+func (m *MockClient) DeleteTopic(topic string) error {
+	if m.shouldFailOps {
+		return errors.New("mock delete topic failed")
+	}
+	if _, exists := m.topics[topic]; !exists {
+		return sarama.ErrUnknownTopicOrPartition
+	}
+	delete(m.topics, topic)
+	return nil
+}
+
+// DeleteRecords records the call and moves the log start offset of each
+// partition of the mock consumer
+func (m *MockClient) DeleteRecords(topic string, partitionOffsets map[int32]int64) error {
+	m.deleteRecords = append(m.deleteRecords, MockDeleteRecords{Topic: topic, Offsets: partitionOffsets})
+	if m.shouldFailOps {
+		return errors.New("mock delete records failed")
+	}
+	if _, exists := m.topics[topic]; !exists {
+		return sarama.ErrUnknownTopicOrPartition
+	}
+	for partition, offset := range partitionOffsets {
+		m.consumer.mutex.Lock()
+		pc, exists := m.consumer.partitions[topic][partition]
+		m.consumer.mutex.Unlock()
+		if exists {
+			pc.SetLogStartOffset(offset)
+		}
+	}
+	return nil
+}
+
+// MockDeleteRecords records a call to DeleteRecords
+type MockDeleteRecords struct {
+	Topic   string
+	Offsets map[int32]int64
+}
+
+// DeleteRecordsCalls returns the recorded DeleteRecords calls
+func (m *MockClient) DeleteRecordsCalls() []MockDeleteRecords {
+	return m.deleteRecords
+}
+
+// ElectLeaders records the election and moves leadership of each partition to
+// its preferred replica. Partitions already led by it report
+// ErrElectionNotNeeded, like a broker.
+func (m *MockClient) ElectLeaders(electionType sarama.ElectionType, partitions map[string][]int32) (map[string]map[int32]*sarama.PartitionResult, error) {
+	m.elections = append(m.elections, MockElection{Type: electionType, Partitions: partitions})
+	if m.shouldFailOps {
+		return nil, errors.New("mock elect leaders failed")
+	}
+
+	results := make(map[string]map[int32]*sarama.PartitionResult, len(partitions))
+	for topic, ids := range partitions {
+		results[topic] = make(map[int32]*sarama.PartitionResult, len(ids))
+		for _, id := range ids {
+			result := &sarama.PartitionResult{ErrorCode: sarama.ErrUnknownTopicOrPartition}
+			if meta, exists := m.topics[topic]; exists && int(id) < len(meta.Partitions) {
+				partition := meta.Partitions[id]
+				if partition.Leader == partition.Replicas[0] {
+					result.ErrorCode = sarama.ErrElectionNotNeeded
+				} else {
+					partition.Leader = partition.Replicas[0]
+					result.ErrorCode = sarama.ErrNoError
+				}
+			}
+			results[topic][id] = result
+		}
+	}
+	return results, nil
+}
+
+// MockElection records a call to ElectLeaders
+type MockElection struct {
+	Type       sarama.ElectionType
+	Partitions map[string][]int32
+}
+
+// Elections returns the recorded ElectLeaders calls
+func (m *MockClient) Elections() []MockElection {
+	return m.elections
+}
+
 func (m *MockClient) ListConsumerGroups() (map[string]string, error) {
 	if m.shouldFailOps {
 		return nil, errors.New("mock list groups failed")
@@ -148,6 +437,12 @@ func (m *MockClient) AddMockTopic(name string, partitions int, replicationFactor
 	}
 }
 
+// MockTopic returns the metadata of a mock topic
+func (m *MockClient) MockTopic(name string) (*sarama.TopicMetadata, bool) {
+	meta, exists := m.topics[name]
+	return meta, exists
+}
+
 func (m *MockClient) AddMockGroup(groupID, state, protocolType string, memberCount int) {
 	members := make(map[string]*sarama.GroupMemberDescription)
 	for i := 0; i < memberCount; i++ {
@@ -168,6 +463,206 @@ func (m *MockClient) AddMockGroup(groupID, state, protocolType string, memberCou
 	}
 }
 
+// SetMockMemberAssignment assigns topic partitions to a member of a mock group,
+// encoded like the assignment of a consumer group member
+func (m *MockClient) SetMockMemberAssignment(groupID, memberID string, topics map[string][]int32) {
+	desc, exists := m.groups[groupID]
+	if !exists {
+		return
+	}
+	member, exists := desc.Members[memberID]
+	if !exists {
+		return
+	}
+
+	names := make([]string, 0, len(topics))
+	for topic := range topics {
+		names = append(names, topic)
+	}
+	sort.Strings(names)
+
+	// version, topic array of (name, partition array), null user data
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, int16(0))
+	binary.Write(&buf, binary.BigEndian, int32(len(names)))
+	for _, topic := range names {
+		binary.Write(&buf, binary.BigEndian, int16(len(topic)))
+		buf.WriteString(topic)
+		binary.Write(&buf, binary.BigEndian, int32(len(topics[topic])))
+		for _, partition := range topics[topic] {
+			binary.Write(&buf, binary.BigEndian, partition)
+		}
+	}
+	binary.Write(&buf, binary.BigEndian, int32(-1))
+
+	member.MemberAssignment = buf.Bytes()
+}
+
+// DeleteConsumerGroup removes a mock group. Like Kafka, groups with members cannot be deleted.
+func (m *MockClient) DeleteConsumerGroup(group string) error {
+	if m.shouldFailOps {
+		return errors.New("mock delete group failed")
+	}
+
+	desc, exists := m.groups[group]
+	if !exists {
+		return sarama.ErrGroupIDNotFound
+	}
+	if len(desc.Members) > 0 {
+		return sarama.ErrNonEmptyGroup
+	}
+
+	delete(m.groups, group)
+	delete(m.groupOffsets, group)
+	return nil
+}
+
+// MockGroup returns the description of a mock group
+func (m *MockClient) MockGroup(groupID string) (*sarama.GroupDescription, bool) {
+	desc, exists := m.groups[groupID]
+	return desc, exists
+}
+
+// AddMockGroupOffset records a committed offset of a consumer group
+func (m *MockClient) AddMockGroupOffset(groupID, topic string, partition int32, offset int64) {
+	if m.groupOffsets[groupID] == nil {
+		m.groupOffsets[groupID] = make(map[string]map[int32]int64)
+	}
+	if m.groupOffsets[groupID][topic] == nil {
+		m.groupOffsets[groupID][topic] = make(map[int32]int64)
+	}
+	m.groupOffsets[groupID][topic][partition] = offset
+}
+
+// ListConsumerGroupOffsets returns the committed offsets of a group. A nil
+// topicPartitions returns all committed offsets.
+func (m *MockClient) ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	if m.shouldFailOps {
+		return nil, errors.New("mock list consumer group offsets failed")
+	}
+
+	response := &sarama.OffsetFetchResponse{
+		Blocks: make(map[string]map[int32]*sarama.OffsetFetchResponseBlock),
+	}
+
+	for topic, partitions := range m.groupOffsets[group] {
+		if topicPartitions != nil {
+			if _, requested := topicPartitions[topic]; !requested {
+				continue
+			}
+		}
+
+		response.Blocks[topic] = make(map[int32]*sarama.OffsetFetchResponseBlock)
+		for partition, offset := range partitions {
+			response.Blocks[topic][partition] = &sarama.OffsetFetchResponseBlock{Offset: offset}
+		}
+	}
+
+	return response, nil
+}
+
+// MockCommit records a call to CommitOffsets
+type MockCommit struct {
+	Group   string
+	Offsets map[string]map[int32]int64
+}
+
+// CommitOffsets records the commit and stores the offsets as the group's committed offsets
+func (m *MockClient) CommitOffsets(group string, offsets map[string]map[int32]int64) error {
+	if m.shouldFailOps {
+		return errors.New("mock commit offsets failed")
+	}
+
+	m.commits = append(m.commits, MockCommit{Group: group, Offsets: offsets})
+	for topic, partitions := range offsets {
+		for partition, offset := range partitions {
+			m.AddMockGroupOffset(group, topic, partition, offset)
+		}
+	}
+	return nil
+}
+
+// GetOffset resolves offsets from the mock consumer's partition logs
+func (m *MockClient) GetOffset(topic string, partition int32, timestamp int64) (int64, error) {
+	if m.shouldFailOps {
+		return -1, errors.New("mock get offset failed")
+	}
+	return m.consumer.GetOffset(topic, partition, timestamp)
+}
+
+// SetMockCoordinator makes a broker added with AddMockBroker the coordinator of a group
+func (m *MockClient) SetMockCoordinator(groupID string, brokerID int32) {
+	m.coordinators[groupID] = brokerID
+}
+
+// Coordinator returns the broker set with SetMockCoordinator
+func (m *MockClient) Coordinator(group string) (*sarama.Broker, error) {
+	if m.shouldFailOps {
+		return nil, errors.New("mock coordinator lookup failed")
+	}
+
+	brokerID, exists := m.coordinators[group]
+	if !exists {
+		return nil, sarama.ErrConsumerCoordinatorNotAvailable
+	}
+	for _, broker := range m.brokers {
+		if broker.ID() == brokerID {
+			return broker, nil
+		}
+	}
+	return nil, sarama.ErrBrokerNotAvailable
+}
+
+// Commits returns the recorded CommitOffsets calls
+func (m *MockClient) Commits() []MockCommit {
+	return m.commits
+}
+
+func (m *MockClient) AddMockBroker(id int32, addr string) {
+	metadata := &sarama.MetadataResponse{}
+	metadata.AddBroker(addr, id)
+	m.brokers = append(m.brokers, metadata.Brokers...)
+}
+
+// AddMockLogDir records the size of a partition replica in a broker's log dir
+func (m *MockClient) AddMockLogDir(brokerID int32, topic string, partition int32, size int64) {
+	m.logDirs[brokerID] = append(m.logDirs[brokerID], sarama.DescribeLogDirsResponseDirMetadata{
+		Path: "/var/lib/kafka/data",
+		Topics: []sarama.DescribeLogDirsResponseTopic{{
+			Topic:      topic,
+			Partitions: []sarama.DescribeLogDirsResponsePartition{{PartitionID: partition, Size: size}},
+		}},
+	})
+}
+
+// DescribeLogDirs returns the log dirs added with AddMockLogDir for the given brokers
+func (m *MockClient) DescribeLogDirs(brokerIDs []int32) (map[int32][]sarama.DescribeLogDirsResponseDirMetadata, error) {
+	if m.shouldFailOps {
+		return nil, errors.New("mock describe log dirs failed")
+	}
+
+	result := make(map[int32][]sarama.DescribeLogDirsResponseDirMetadata)
+	for _, id := range brokerIDs {
+		if dirs, exists := m.logDirs[id]; exists {
+			result[id] = dirs
+		}
+	}
+	return result, nil
+}
+
+func (m *MockClient) AddMockConfig(resourceType sarama.ConfigResourceType, name string, entries ...sarama.ConfigEntry) {
+	key := mockConfigKey(resourceType, name)
+	m.configs[key] = append(m.configs[key], entries...)
+}
+
+func mockConfigKey(resourceType sarama.ConfigResourceType, name string) string {
+	return fmt.Sprintf("%d/%s", resourceType, name)
+}
+
+func (m *MockClient) SetControllerID(id int32) {
+	m.controllerID = id
+}
+
 func (m *MockClient) SetShouldFailPing(fail bool) {
 	m.shouldFailPing = fail
 }
@@ -176,6 +671,578 @@ func (m *MockClient) SetShouldFailOps(fail bool) {
 	m.shouldFailOps = fail
 }
 
+// SetNoDescribeAllTopics makes DescribeTopics with an empty list return no
+// topics, as some brokers and sarama versions do
+func (m *MockClient) SetNoDescribeAllTopics(noDescribeAll bool) {
+	m.noDescribeAll = noDescribeAll
+}
+
+// DescribeTopicsCalls returns the topic names passed to each DescribeTopics call
+func (m *MockClient) DescribeTopicsCalls() [][]string {
+	m.describeMutex.Lock()
+	defer m.describeMutex.Unlock()
+	return m.describeCalls
+}
+
+// MockACL is an ACL stored by the mock client
+type MockACL struct {
+	Resource sarama.Resource
+	Acl      sarama.Acl
+}
+
+// AddMockACL stores an ACL as if it had been created on the cluster
+func (m *MockClient) AddMockACL(resource sarama.Resource, acl sarama.Acl) {
+	m.acls = append(m.acls, MockACL{Resource: resource, Acl: acl})
+}
+
+// MockACLs returns the ACLs stored by the mock client
+func (m *MockClient) MockACLs() []MockACL {
+	return m.acls
+}
+
+// ListAcls returns the stored ACLs matching the filter
+func (m *MockClient) ListAcls(filter sarama.AclFilter) ([]sarama.ResourceAcls, error) {
+	if m.shouldFailOps {
+		return nil, errors.New("mock list ACLs failed")
+	}
+
+	var result []sarama.ResourceAcls
+	for _, stored := range m.acls {
+		if mockACLMatches(filter, stored) {
+			acl := stored.Acl
+			result = append(result, sarama.ResourceAcls{Resource: stored.Resource, Acls: []*sarama.Acl{&acl}})
+		}
+	}
+	return result, nil
+}
+
+// CreateACL stores an ACL
+func (m *MockClient) CreateACL(resource sarama.Resource, acl sarama.Acl) error {
+	if m.shouldFailOps {
+		return errors.New("mock create ACL failed")
+	}
+
+	m.AddMockACL(resource, acl)
+	return nil
+}
+
+// DeleteACL removes the stored ACLs matching the filter and returns them
+func (m *MockClient) DeleteACL(filter sarama.AclFilter, validateOnly bool) ([]sarama.MatchingAcl, error) {
+	if m.shouldFailOps {
+		return nil, errors.New("mock delete ACL failed")
+	}
+
+	var (
+		matched []sarama.MatchingAcl
+		kept    []MockACL
+	)
+	for _, stored := range m.acls {
+		if mockACLMatches(filter, stored) {
+			matched = append(matched, sarama.MatchingAcl{Resource: stored.Resource, Acl: stored.Acl})
+			continue
+		}
+		kept = append(kept, stored)
+	}
+	if !validateOnly {
+		m.acls = kept
+	}
+	return matched, nil
+}
+
+// mockACLMatches applies the broker's ACL filter semantics to a stored ACL
+func mockACLMatches(filter sarama.AclFilter, stored MockACL) bool {
+	resource, acl := stored.Resource, stored.Acl
+
+	if filter.ResourceType != sarama.AclResourceAny && filter.ResourceType != resource.ResourceType {
+		return false
+	}
+	if filter.Principal != nil && *filter.Principal != acl.Principal {
+		return false
+	}
+	if filter.Host != nil && *filter.Host != acl.Host {
+		return false
+	}
+	if filter.Operation != sarama.AclOperationAny && filter.Operation != acl.Operation {
+		return false
+	}
+	if filter.PermissionType != sarama.AclPermissionAny && filter.PermissionType != acl.PermissionType {
+		return false
+	}
+
+	switch filter.ResourcePatternTypeFilter {
+	case sarama.AclPatternAny:
+		return filter.ResourceName == nil || *filter.ResourceName == resource.ResourceName
+	case sarama.AclPatternMatch:
+		if filter.ResourceName == nil {
+			return true
+		}
+		name := *filter.ResourceName
+		switch resource.ResourcePatternType {
+		case sarama.AclPatternLiteral:
+			return resource.ResourceName == name || resource.ResourceName == "*"
+		case sarama.AclPatternPrefixed:
+			return strings.HasPrefix(name, resource.ResourceName)
+		}
+		return false
+	default:
+		return filter.ResourcePatternTypeFilter == resource.ResourcePatternType &&
+			(filter.ResourceName == nil || *filter.ResourceName == resource.ResourceName)
+	}
+}
+
+// DescribeClientQuotas returns the stored quotas of entities matching the filter
+func (m *MockClient) DescribeClientQuotas(components []sarama.QuotaFilterComponent, strict bool) ([]sarama.DescribeClientQuotasEntry, error) {
+	if m.shouldFailOps {
+		return nil, errors.New("mock describe client quotas failed")
+	}
+
+	var result []sarama.DescribeClientQuotasEntry
+	for _, entry := range m.quotas {
+		if mockQuotaMatches(components, strict, entry.Entity) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+// AlterClientQuotas sets or removes a quota value of an entity
+func (m *MockClient) AlterClientQuotas(entity []sarama.QuotaEntityComponent, op sarama.ClientQuotasOp, validateOnly bool) error {
+	if m.shouldFailOps {
+		return errors.New("mock alter client quotas failed")
+	}
+	if validateOnly {
+		return nil
+	}
+
+	for i, entry := range m.quotas {
+		if !mockSameQuotaEntity(entry.Entity, entity) {
+			continue
+		}
+		if op.Remove {
+			delete(entry.Values, op.Key)
+			if len(entry.Values) == 0 {
+				m.quotas = append(m.quotas[:i], m.quotas[i+1:]...)
+			}
+		} else {
+			entry.Values[op.Key] = op.Value
+		}
+		return nil
+	}
+
+	if !op.Remove {
+		m.quotas = append(m.quotas, sarama.DescribeClientQuotasEntry{
+			Entity: entity,
+			Values: map[string]float64{op.Key: op.Value},
+		})
+	}
+	return nil
+}
+
+// mockQuotaMatches applies the broker's quota filter semantics to a stored entity
+func mockQuotaMatches(components []sarama.QuotaFilterComponent, strict bool, entity []sarama.QuotaEntityComponent) bool {
+	if strict && len(components) != len(entity) {
+		return false
+	}
+
+	for _, filter := range components {
+		matched := false
+		for _, component := range entity {
+			if component.EntityType != filter.EntityType {
+				continue
+			}
+			switch filter.MatchType {
+			case sarama.QuotaMatchAny:
+				matched = true
+			case sarama.QuotaMatchDefault:
+				matched = component.MatchType == sarama.QuotaMatchDefault
+			default:
+				matched = component.MatchType == sarama.QuotaMatchExact && component.Name == filter.Match
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// mockSameQuotaEntity reports whether two entities have the same components
+func mockSameQuotaEntity(a, b []sarama.QuotaEntityComponent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, componentA := range a {
+		found := false
+		for _, componentB := range b {
+			if componentA == componentB {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MockProducer implements sarama.SyncProducer and records the messages sent to it.
+// Transactional methods are not overridden and panic when called.
+type MockProducer struct {
+	sarama.SyncProducer
+	messages   []*sarama.ProducerMessage
+	nextOffset int64
+	failOn     map[int]bool
+	mutex      sync.Mutex
+}
+
+// NewMockProducer creates a new mock producer
+func NewMockProducer() *MockProducer {
+	return &MockProducer{
+		failOn: make(map[int]bool),
+	}
+}
+
+// SendMessage records the message and assigns it the next offset
+func (p *MockProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	call := len(p.messages)
+	p.messages = append(p.messages, msg)
+	if p.failOn[call] {
+		return -1, -1, errors.New("mock produce failed")
+	}
+
+	offset := p.nextOffset
+	p.nextOffset++
+	return msg.Partition, offset, nil
+}
+
+// SendMessages records all messages
+func (p *MockProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	for _, msg := range msgs {
+		if _, _, err := p.SendMessage(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close simulates closing the producer
+func (p *MockProducer) Close() error {
+	return nil
+}
+
+// Messages returns the messages passed to SendMessage, including failed ones
+func (p *MockProducer) Messages() []*sarama.ProducerMessage {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]*sarama.ProducerMessage(nil), p.messages...)
+}
+
+// FailOnCall makes the n-th call (zero based) to SendMessage fail
+func (p *MockProducer) FailOnCall(n int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.failOn[n] = true
+}
+
+// MockConsumer implements sarama.Consumer on top of registered mock partitions.
+// Methods that are not overridden here panic when called.
+type MockConsumer struct {
+	sarama.Consumer
+	partitions map[string]map[int32]*MockPartitionConsumer
+	mutex      sync.Mutex
+}
+
+// NewMockConsumer creates a new mock consumer
+func NewMockConsumer() *MockConsumer {
+	return &MockConsumer{
+		partitions: make(map[string]map[int32]*MockPartitionConsumer),
+	}
+}
+
+// AddMockPartition registers a partition and returns its partition consumer for pushing messages
+func (c *MockConsumer) AddMockPartition(topic string, partition int32) *MockPartitionConsumer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.partitions[topic] == nil {
+		c.partitions[topic] = make(map[int32]*MockPartitionConsumer)
+	}
+
+	pc := NewMockPartitionConsumer(topic, partition)
+	c.partitions[topic][partition] = pc
+	return pc
+}
+
+// Topics returns the registered topics
+func (c *MockConsumer) Topics() ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	topics := make([]string, 0, len(c.partitions))
+	for topic := range c.partitions {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics, nil
+}
+
+// Partitions returns the registered partitions of a topic
+func (c *MockConsumer) Partitions(topic string) ([]int32, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	partitions, exists := c.partitions[topic]
+	if !exists {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+
+	ids := make([]int32, 0, len(partitions))
+	for id := range partitions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// ConsumePartition opens the registered partition consumer at the requested offset
+func (c *MockConsumer) ConsumePartition(topic string, partition int32, offset int64) (sarama.PartitionConsumer, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	pc, exists := c.partitions[topic][partition]
+	if !exists {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+
+	pc.start(offset)
+	return pc, nil
+}
+
+// GetOffset resolves an offset in a registered partition log, like sarama.Client.GetOffset
+func (c *MockConsumer) GetOffset(topic string, partition int32, timestamp int64) (int64, error) {
+	c.mutex.Lock()
+	pc, exists := c.partitions[topic][partition]
+	c.mutex.Unlock()
+	if !exists {
+		return -1, sarama.ErrUnknownTopicOrPartition
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	switch timestamp {
+	case sarama.OffsetOldest:
+		return pc.logStart, nil
+	case sarama.OffsetNewest:
+		return int64(len(pc.log)), nil
+	}
+
+	for _, msg := range pc.log {
+		if !pc.markers[msg.Offset] && msg.Timestamp.UnixMilli() >= timestamp {
+			return msg.Offset, nil
+		}
+	}
+	return -1, nil
+}
+
+// Close simulates closing the consumer
+func (c *MockConsumer) Close() error {
+	return nil
+}
+
+// MockPartitionConsumer implements sarama.PartitionConsumer over an in-memory partition log.
+// Methods that are not overridden here panic when called.
+type MockPartitionConsumer struct {
+	sarama.PartitionConsumer
+	Topic       string
+	Partition   int32
+	log         []*sarama.ConsumerMessage
+	messages    chan *sarama.ConsumerMessage
+	errors      chan *sarama.ConsumerError
+	startOffset int64
+	logStart    int64
+	markers     map[int64]bool // offsets of control records, which are never delivered
+	started     bool
+	closed      bool
+	mutex       sync.Mutex
+}
+
+// NewMockPartitionConsumer creates a new mock partition consumer
+func NewMockPartitionConsumer(topic string, partition int32) *MockPartitionConsumer {
+	return &MockPartitionConsumer{
+		Topic:     topic,
+		Partition: partition,
+		messages:  make(chan *sarama.ConsumerMessage, 100),
+		errors:    make(chan *sarama.ConsumerError, 10),
+	}
+}
+
+// start (re)opens the partition consumer at an offset and replays the log from there
+func (pc *MockPartitionConsumer) start(offset int64) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	switch offset {
+	case sarama.OffsetOldest:
+		offset = 0
+	case sarama.OffsetNewest:
+		offset = int64(len(pc.log))
+	}
+
+	pc.startOffset = offset
+	pc.started = true
+	pc.messages = make(chan *sarama.ConsumerMessage, 100)
+	pc.errors = make(chan *sarama.ConsumerError, 10)
+	pc.closed = false
+
+	for _, msg := range pc.log {
+		if msg.Offset >= offset && !pc.markers[msg.Offset] {
+			pc.deliver(msg)
+		}
+	}
+}
+
+// deliver pushes a message to the channel without blocking; callers hold the mutex
+func (pc *MockPartitionConsumer) deliver(msg *sarama.ConsumerMessage) {
+	select {
+	case pc.messages <- msg:
+	default:
+		// Channel full, drop message
+	}
+}
+
+// StartOffset returns the offset the partition consumer was started from
+func (pc *MockPartitionConsumer) StartOffset() int64 {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	return pc.startOffset
+}
+
+// SendMockMessage appends a message to the partition log and delivers it to an open consumer
+func (pc *MockPartitionConsumer) SendMockMessage(key, value string) {
+	pc.SendMockMessageAt(key, value, time.Now())
+}
+
+// SendMockMessageAt is like SendMockMessage with an explicit message timestamp
+func (pc *MockPartitionConsumer) SendMockMessageAt(key, value string, timestamp time.Time) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	msg := &sarama.ConsumerMessage{
+		Topic:     pc.Topic,
+		Partition: pc.Partition,
+		Offset:    int64(len(pc.log)),
+		Key:       []byte(key),
+		Value:     []byte(value),
+		Timestamp: timestamp,
+	}
+	pc.log = append(pc.log, msg)
+
+	if !pc.closed && msg.Offset >= pc.startOffset {
+		pc.deliver(msg)
+	}
+}
+
+// SendMockControlRecord appends a transaction marker to the partition log. Like
+// a real control record it takes an offset but is never delivered.
+func (pc *MockPartitionConsumer) SendMockControlRecord() {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	offset := int64(len(pc.log))
+	pc.log = append(pc.log, &sarama.ConsumerMessage{
+		Topic:     pc.Topic,
+		Partition: pc.Partition,
+		Offset:    offset,
+		Timestamp: time.Now(),
+	})
+	if pc.markers == nil {
+		pc.markers = make(map[int64]bool)
+	}
+	pc.markers[offset] = true
+}
+
+// SetLogStartOffset sets the oldest offset reported by GetOffset, as if retention
+// had deleted the messages before it
+func (pc *MockPartitionConsumer) SetLogStartOffset(offset int64) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.logStart = offset
+}
+
+// SendMockError delivers a consumer error
+func (pc *MockPartitionConsumer) SendMockError(err error) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	if pc.closed {
+		return
+	}
+
+	select {
+	case pc.errors <- &sarama.ConsumerError{Topic: pc.Topic, Partition: pc.Partition, Err: err}:
+	default:
+		// Channel full, drop error
+	}
+}
+
+// Messages returns the message channel
+func (pc *MockPartitionConsumer) Messages() <-chan *sarama.ConsumerMessage {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	return pc.messages
+}
+
+// Errors returns the error channel
+func (pc *MockPartitionConsumer) Errors() <-chan *sarama.ConsumerError {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	return pc.errors
+}
+
+// HighWaterMarkOffset returns the offset of the next message written to the partition
+func (pc *MockPartitionConsumer) HighWaterMarkOffset() int64 {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	return int64(len(pc.log))
+}
+
+// AsyncClose closes the message and error channels
+func (pc *MockPartitionConsumer) AsyncClose() {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	if pc.closed {
+		return
+	}
+	pc.closed = true
+	close(pc.messages)
+	close(pc.errors)
+}
+
+// Close closes the message and error channels
+func (pc *MockPartitionConsumer) Close() error {
+	pc.AsyncClose()
+	return nil
+}
+
+// Started reports whether the partition consumer has been opened by ConsumePartition
+func (pc *MockPartitionConsumer) Started() bool {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	return pc.started
+}
+
+// Closed reports whether the partition consumer has been closed
+func (pc *MockPartitionConsumer) Closed() bool {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	return pc.closed
+}
+
 // MockConsumerSession represents a mock consumer session
 type MockConsumerSession struct {
 	Topic     string
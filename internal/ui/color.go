@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/nipunap/kim/pkg/types"
+
+	"golang.org/x/term"
+)
+
+// isTerminal reports whether stdout is a terminal. Tests replace it to force
+// colored output.
+var isTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// palette holds the ANSI codes of a color scheme
+type palette struct {
+	header string
+	good   string
+	bad    string
+	warn   string
+}
+
+// palettes maps color scheme names to their codes. Unknown schemes use default.
+var palettes = map[string]palette{
+	"default": {header: "1", good: "32", bad: "31", warn: "33"},
+	"dark":    {header: "1;97", good: "92", bad: "91", warn: "93"},
+	"light":   {header: "1;34", good: "32", bad: "31", warn: "35"},
+}
+
+// colors applies a color scheme to table output
+type colors struct {
+	palette palette
+	enabled bool
+}
+
+// newColors creates colors for the display options. Colors are disabled for the
+// "none" scheme and when stdout is not a terminal.
+func newColors(opts *types.DisplayOptions) *colors {
+	scheme := ""
+	if opts != nil {
+		scheme = opts.ColorScheme
+	}
+
+	if scheme == "none" || !isTerminal() {
+		return &colors{}
+	}
+
+	p, ok := palettes[scheme]
+	if !ok {
+		p = palettes["default"]
+	}
+	return &colors{palette: p, enabled: true}
+}
+
+// paint wraps s in the given ANSI code
+func (c *colors) paint(code, s string) string {
+	if !c.enabled || code == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// header colors a table header
+func (c *colors) header(s string) string {
+	return c.paint(c.palette.header, s)
+}
+
+// good colors a healthy value
+func (c *colors) good(s string) string {
+	return c.paint(c.palette.good, s)
+}
+
+// bad colors an unhealthy value
+func (c *colors) bad(s string) string {
+	return c.paint(c.palette.bad, s)
+}
+
+// warn colors a value that needs attention
+func (c *colors) warn(s string) string {
+	return c.paint(c.palette.warn, s)
+}
+
+// groupState colors a consumer group state. s may be padded, state is the raw value.
+func (c *colors) groupState(state, s string) string {
+	switch state {
+	case "Stable":
+		return c.good(s)
+	case "Empty", "Dead":
+		return c.bad(s)
+	case "PreparingRebalance", "CompletingRebalance":
+		return c.warn(s)
+	default:
+		return s
+	}
+}
+
+// lag colors a consumer lag that exceeds threshold. s may be padded, lag is the
+// raw value. A threshold of 0 disables highlighting.
+func (c *colors) lag(lag, threshold int64, s string) string {
+	if threshold > 0 && lag > threshold {
+		return c.bad(s)
+	}
+	return s
+}
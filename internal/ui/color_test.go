@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nipunap/kim/pkg/types"
+)
+
+// forceTerminal makes stdout look like a terminal for the duration of the test
+func forceTerminal(t *testing.T) {
+	t.Helper()
+	old := isTerminal
+	isTerminal = func() bool { return true }
+	t.Cleanup(func() { isTerminal = old })
+}
+
+func testGroupList() *types.GroupList {
+	return &types.GroupList{
+		Groups: []*types.GroupInfo{
+			{GroupID: "orders-service", State: "Stable", ProtocolType: "consumer", MemberCount: 2},
+			{GroupID: "billing-service", State: "Empty", ProtocolType: "consumer"},
+		},
+	}
+}
+
+func TestNoColorWhenNotTerminal(t *testing.T) {
+	// captureOutput redirects stdout to a pipe, which is not a terminal
+	output := captureOutput(func() {
+		if err := DisplayGroupList(testGroupList(), &types.DisplayOptions{Format: "table", ColorScheme: "default"}); err != nil {
+			t.Fatalf("DisplayGroupList failed: %v", err)
+		}
+	})
+
+	if strings.Contains(output, "\x1b[") {
+		t.Errorf("Expected no escape codes when stdout is not a terminal, got %q", output)
+	}
+}
+
+func TestColorizedTable(t *testing.T) {
+	forceTerminal(t)
+
+	output := captureOutput(func() {
+		if err := DisplayGroupList(testGroupList(), &types.DisplayOptions{Format: "table", ColorScheme: "default"}); err != nil {
+			t.Fatalf("DisplayGroupList failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "\x1b[1mGROUP ID") {
+		t.Errorf("Expected a bold header, got %q", output)
+	}
+	if !strings.Contains(output, "\x1b[32mStable") {
+		t.Errorf("Expected Stable groups in green, got %q", output)
+	}
+	if !strings.Contains(output, "\x1b[31mEmpty") {
+		t.Errorf("Expected Empty groups in red, got %q", output)
+	}
+}
+
+func TestColorSchemeNone(t *testing.T) {
+	forceTerminal(t)
+
+	output := captureOutput(func() {
+		if err := DisplayGroupList(testGroupList(), &types.DisplayOptions{Format: "table", ColorScheme: "none"}); err != nil {
+			t.Fatalf("DisplayGroupList failed: %v", err)
+		}
+	})
+
+	if strings.Contains(output, "\x1b[") {
+		t.Errorf("Expected no escape codes with the none scheme, got %q", output)
+	}
+}
+
+func TestUnderReplicatedPartitionHighlighted(t *testing.T) {
+	forceTerminal(t)
+
+	details := &types.TopicDetails{
+		Name:       "orders",
+		Partitions: 2,
+		PartitionDetails: []*types.PartitionInfo{
+			{ID: 0, Leader: 1, Replicas: []int32{1, 2}, InSyncReplicas: []int32{1, 2}},
+			{ID: 1, Leader: 1, Replicas: []int32{1, 2}, InSyncReplicas: []int32{1}},
+		},
+	}
+
+	output := captureOutput(func() {
+		if err := DisplayTopicDetails(details, &types.DisplayOptions{Format: "table"}); err != nil {
+			t.Fatalf("DisplayTopicDetails failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "\x1b[31m1 ") {
+		t.Errorf("Expected the under-replicated partition in red, got %q", output)
+	}
+	if strings.Contains(output, "\x1b[31m0 ") {
+		t.Errorf("Healthy partitions should not be highlighted, got %q", output)
+	}
+}
@@ -1,11 +1,15 @@
 package ui
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/nipunap/kim/pkg/types"
@@ -23,8 +27,10 @@ func DisplayTopicList(topicList *types.TopicList, opts *types.DisplayOptions) er
 		return displayJSON(topicList)
 	case "yaml":
 		return displayYAML(topicList)
+	case "go-template":
+		return displayTemplate(topicList, opts.Template)
 	case "table", "":
-		return displayTopicTable(topicList)
+		return displayTopicTable(topicList, newColors(opts))
 	default:
 		return fmt.Errorf("invalid format: %s", opts.Format)
 	}
@@ -37,8 +43,80 @@ func DisplayTopicDetails(details *types.TopicDetails, opts *types.DisplayOptions
 		return displayJSON(details)
 	case "yaml":
 		return displayYAML(details)
+	case "go-template":
+		return displayTemplate(details, opts.Template)
 	default:
-		return displayTopicDetailsTable(details)
+		return displayTopicDetailsTable(details, newColors(opts))
+	}
+}
+
+// DisplayDescribeTopicResults displays the results of describing several topics.
+// Tables show each topic in turn; other formats render the results as a list.
+func DisplayDescribeTopicResults(results []*types.DescribeTopicResult, opts *types.DisplayOptions) error {
+	switch opts.Format {
+	case "json":
+		return displayJSON(results)
+	case "yaml":
+		return displayYAML(results)
+	case "go-template":
+		return displayTemplate(results, opts.Template)
+	case "table", "":
+		c := newColors(opts)
+		for i, result := range results {
+			if i > 0 {
+				fmt.Println()
+			}
+			if result.Error != "" {
+				fmt.Printf("Topic: %s\n", result.Name)
+				fmt.Println(strings.Repeat("=", 50))
+				fmt.Printf("Error: %s\n", c.bad(result.Error))
+				continue
+			}
+			if err := displayTopicDetailsTable(result.Details, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayTopicConfig displays the configuration of a topic
+func DisplayTopicConfig(topicConfig *types.TopicConfig, opts *types.DisplayOptions) error {
+	if topicConfig == nil {
+		return fmt.Errorf("topic config cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(topicConfig)
+	case "yaml":
+		return displayYAML(topicConfig)
+	case "go-template":
+		return displayTemplate(topicConfig, opts.Template)
+	case "table", "":
+		return displayTopicConfigTable(topicConfig, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayTopicDiff displays the differences between two topics
+func DisplayTopicDiff(diff *types.TopicDiff, opts *types.DisplayOptions) error {
+	if diff == nil {
+		return fmt.Errorf("topic diff cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(diff)
+	case "yaml":
+		return displayYAML(diff)
+	case "go-template":
+		return displayTemplate(diff, opts.Template)
+	case "table", "":
+		return displayTopicDiffTable(diff, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
 	}
 }
 
@@ -52,8 +130,10 @@ func DisplayGroupList(groupList *types.GroupList, opts *types.DisplayOptions) er
 		return displayJSON(groupList)
 	case "yaml":
 		return displayYAML(groupList)
+	case "go-template":
+		return displayTemplate(groupList, opts.Template)
 	default:
-		return displayGroupTable(groupList)
+		return displayGroupTable(groupList, newColors(opts))
 	}
 }
 
@@ -64,23 +144,75 @@ func DisplayGroupDetails(details *types.GroupDetails, opts *types.DisplayOptions
 		return displayJSON(details)
 	case "yaml":
 		return displayYAML(details)
+	case "go-template":
+		return displayTemplate(details, opts.Template)
 	default:
-		return displayGroupDetailsTable(details)
+		return displayGroupDetailsTable(details, newColors(opts), opts.LagThreshold)
+	}
+}
+
+// DisplayGroupMembers displays the members of a consumer group
+func DisplayGroupMembers(members []*types.MemberInfo, opts *types.DisplayOptions) error {
+	if members == nil {
+		members = []*types.MemberInfo{}
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(members)
+	case "yaml":
+		return displayYAML(members)
+	case "go-template":
+		return displayTemplate(members, opts.Template)
+	case "table", "":
+		return displayGroupMembersTable(members, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
 	}
 }
 
 // DisplayMessage displays a single message
 func DisplayMessage(message *types.Message, opts *types.DisplayOptions) error {
+	return DisplayMessageTo(os.Stdout, message, opts)
+}
+
+// DisplayMessageTo displays a single message on w, such as a Pager
+func DisplayMessageTo(w io.Writer, message *types.Message, opts *types.DisplayOptions) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
 	switch opts.Format {
 	case "json":
-		return displayJSON(message)
+		return writeJSON(w, message)
+	case "jsonl":
+		return writeJSONLine(w, message)
 	case "yaml":
-		return displayYAML(message)
+		return writeYAML(w, message)
 	case "table", "":
-		return displayMessageTable(message)
+		return displayMessageTable(w, message)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayMessageList displays a page of messages
+func DisplayMessageList(messageList *types.MessageList, opts *types.DisplayOptions) error {
+	if messageList == nil {
+		return fmt.Errorf("message list cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(messageList)
+	case "jsonl":
+		for _, message := range messageList.Messages {
+			if err := displayJSONLine(message); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "yaml":
+		return displayYAML(messageList)
+	case "table", "":
+		return displayMessageListTable(messageList)
 	default:
 		return fmt.Errorf("invalid format: %s", opts.Format)
 	}
@@ -113,8 +245,162 @@ func DisplayProfileList(profiles []*types.ProfileInfo, opts *types.DisplayOption
 		return displayJSON(profiles)
 	case "yaml":
 		return displayYAML(profiles)
+	case "go-template":
+		return displayTemplate(profiles, opts.Template)
+	case "table", "":
+		return displayProfileTable(profiles, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayProfileDetails displays the full configuration of a profile
+func DisplayProfileDetails(details *types.ProfileDetails, opts *types.DisplayOptions) error {
+	if details == nil {
+		return fmt.Errorf("profile details cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(details)
+	case "yaml":
+		return displayYAML(details)
+	case "go-template":
+		return displayTemplate(details, opts.Template)
+	case "table", "":
+		return displayProfileDetailsTable(details)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayVersionInfo displays the kim build and Kafka client versions
+func DisplayVersionInfo(info *types.VersionInfo, opts *types.DisplayOptions) error {
+	if info == nil {
+		return fmt.Errorf("version info cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(info)
+	case "yaml":
+		return displayYAML(info)
+	case "go-template":
+		return displayTemplate(info, opts.Template)
 	case "table", "":
-		return displayProfileTable(profiles)
+		return displayVersionInfoTable(info)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayClusterInfo displays cluster information
+func DisplayClusterInfo(info *types.ClusterInfo, opts *types.DisplayOptions) error {
+	if info == nil {
+		return fmt.Errorf("cluster info cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(info)
+	case "yaml":
+		return displayYAML(info)
+	case "go-template":
+		return displayTemplate(info, opts.Template)
+	case "table", "":
+		return displayClusterInfoTable(info, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayClusterHealth displays the result of a cluster health check
+func DisplayClusterHealth(health *types.ClusterHealth, opts *types.DisplayOptions) error {
+	if health == nil {
+		return fmt.Errorf("cluster health cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(health)
+	case "yaml":
+		return displayYAML(health)
+	case "go-template":
+		return displayTemplate(health, opts.Template)
+	case "table", "":
+		return displayClusterHealthTable(health, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayLeaderElection displays the result of a preferred leader election
+func DisplayLeaderElection(election *types.LeaderElection, opts *types.DisplayOptions) error {
+	if election == nil {
+		return fmt.Errorf("leader election cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(election)
+	case "yaml":
+		return displayYAML(election)
+	case "go-template":
+		return displayTemplate(election, opts.Template)
+	case "table", "":
+		return displayLeaderElectionTable(election, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayBrokerConfig displays the configuration of a broker
+func DisplayBrokerConfig(brokerConfig *types.BrokerConfig, opts *types.DisplayOptions) error {
+	if brokerConfig == nil {
+		return fmt.Errorf("broker config cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(brokerConfig)
+	case "yaml":
+		return displayYAML(brokerConfig)
+	case "go-template":
+		return displayTemplate(brokerConfig, opts.Template)
+	case "table", "":
+		return displayBrokerConfigTable(brokerConfig, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayACLList displays a list of ACLs
+func DisplayACLList(acls []*types.ACLBinding, opts *types.DisplayOptions) error {
+	if acls == nil {
+		return fmt.Errorf("ACLs cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(acls)
+	case "yaml":
+		return displayYAML(acls)
+	case "go-template":
+		return displayTemplate(acls, opts.Template)
+	case "table", "":
+		return displayACLTable(acls, newColors(opts))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// DisplayQuotaList displays a list of client and user quotas
+func DisplayQuotaList(quotas []*types.QuotaEntry, opts *types.DisplayOptions) error {
+	if quotas == nil {
+		return fmt.Errorf("quotas cannot be nil")
+	}
+	switch opts.Format {
+	case "json":
+		return displayJSON(quotas)
+	case "yaml":
+		return displayYAML(quotas)
+	case "go-template":
+		return displayTemplate(quotas, opts.Template)
+	case "table", "":
+		return displayQuotaTable(quotas, newColors(opts))
 	default:
 		return fmt.Errorf("invalid format: %s", opts.Format)
 	}
@@ -122,28 +408,83 @@ func DisplayProfileList(profiles []*types.ProfileInfo, opts *types.DisplayOption
 
 // displayJSON displays data as JSON
 func displayJSON(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
+	return writeJSON(os.Stdout, data)
+}
+
+// displayJSONLine displays data as a single line of compact JSON
+func displayJSONLine(data interface{}) error {
+	return writeJSONLine(os.Stdout, data)
 }
 
 // displayYAML displays data as YAML
 func displayYAML(data interface{}) error {
-	encoder := yaml.NewEncoder(os.Stdout)
+	return writeYAML(os.Stdout, data)
+}
+
+// writeJSON writes data to w as indented JSON
+func writeJSON(w io.Writer, data interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// writeJSONLine writes data to w as a single line of compact JSON
+func writeJSONLine(w io.Writer, data interface{}) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+// writeYAML writes data to w as YAML
+func writeYAML(w io.Writer, data interface{}) error {
+	encoder := yaml.NewEncoder(w)
 	defer encoder.Close()
 	return encoder.Encode(data)
 }
 
+// displayTemplate renders data through a Go text/template. Output is only
+// written once the template has executed successfully.
+func displayTemplate(data interface{}, tmpl string) error {
+	if tmpl == "" {
+		return fmt.Errorf("a template is required for the go-template format (use --template)")
+	}
+
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	_, err = os.Stdout.Write(buf.Bytes())
+	return err
+}
+
 // displayTopicTable displays topics in table format
-func displayTopicTable(topicList *types.TopicList) error {
+func displayTopicTable(topicList *types.TopicList, c *colors) error {
 	if len(topicList.Topics) == 0 {
 		fmt.Println("No topics found")
 		return nil
 	}
 
+	// Size columns are only shown when sizes were requested
+	withSize := false
+	for _, topic := range topicList.Topics {
+		if topic.MessageCount != nil {
+			withSize = true
+			break
+		}
+	}
+
 	// Print header
-	fmt.Printf("%-50s %-12s %-20s %-10s\n", "TOPIC NAME", "PARTITIONS", "REPLICATION FACTOR", "INTERNAL")
-	fmt.Println(strings.Repeat("-", 92))
+	if withSize {
+		fmt.Println(c.header(fmt.Sprintf("%-50s %-12s %-20s %-10s %-15s %-12s", "TOPIC NAME", "PARTITIONS", "REPLICATION FACTOR", "INTERNAL", "MESSAGES", "SIZE")))
+		fmt.Println(strings.Repeat("-", 120))
+	} else {
+		fmt.Println(c.header(fmt.Sprintf("%-50s %-12s %-20s %-10s", "TOPIC NAME", "PARTITIONS", "REPLICATION FACTOR", "INTERNAL")))
+		fmt.Println(strings.Repeat("-", 92))
+	}
 
 	// Print topics
 	for _, topic := range topicList.Topics {
@@ -151,8 +492,18 @@ func displayTopicTable(topicList *types.TopicList) error {
 		if topic.Internal {
 			internal = "true"
 		}
-		fmt.Printf("%-50s %-12d %-20d %-10s\n",
-			topic.Name, topic.Partitions, topic.ReplicationFactor, internal)
+		if !withSize {
+			fmt.Printf("%-50s %-12d %-20d %-10s\n",
+				topic.Name, topic.Partitions, topic.ReplicationFactor, internal)
+			continue
+		}
+
+		size := "-"
+		if topic.SizeBytes != nil {
+			size = formatBytes(*topic.SizeBytes)
+		}
+		fmt.Printf("%-50s %-12d %-20d %-10s %-15s %-12s\n",
+			topic.Name, topic.Partitions, topic.ReplicationFactor, internal, formatOptionalInt64(topic.MessageCount), size)
 	}
 
 	// Print pagination info
@@ -167,7 +518,7 @@ func displayTopicTable(topicList *types.TopicList) error {
 }
 
 // displayTopicDetailsTable displays topic details in table format
-func displayTopicDetailsTable(details *types.TopicDetails) error {
+func displayTopicDetailsTable(details *types.TopicDetails, c *colors) error {
 	fmt.Printf("Topic: %s\n", details.Name)
 	fmt.Println(strings.Repeat("=", 50))
 
@@ -177,19 +528,57 @@ func displayTopicDetailsTable(details *types.TopicDetails) error {
 	fmt.Printf("Internal: %t\n", details.Internal)
 	fmt.Println()
 
+	// Health warnings
+	if details.UnderReplicated {
+		fmt.Println(c.bad("WARNING: topic is under-replicated (some partitions have fewer in-sync replicas than replicas)"))
+	}
+	if len(details.OfflinePartitions) > 0 {
+		fmt.Println(c.bad("WARNING: offline partitions: " + formatInt32Slice(details.OfflinePartitions)))
+	}
+	if details.UnderReplicated || len(details.OfflinePartitions) > 0 {
+		fmt.Println()
+	}
+
 	// Partition details
 	if len(details.PartitionDetails) > 0 {
+		// Offset columns are only shown when offsets were requested
+		withOffsets := false
+		for _, partition := range details.PartitionDetails {
+			if partition.LatestOffset != nil {
+				withOffsets = true
+				break
+			}
+		}
+
 		fmt.Println("Partition Details:")
-		fmt.Printf("%-10s %-8s %-20s %-20s %-20s\n", "PARTITION", "LEADER", "REPLICAS", "IN-SYNC", "OFFLINE")
-		fmt.Println(strings.Repeat("-", 78))
+		header := fmt.Sprintf("%-10s %-8s %-20s %-20s %-20s", "PARTITION", "LEADER", "REPLICAS", "IN-SYNC", "OFFLINE")
+		width := 78
+		if withOffsets {
+			header += fmt.Sprintf(" %-15s %-15s %-15s", "EARLIEST", "LATEST", "MESSAGES")
+			width += 48
+		}
+		fmt.Println(c.header(header))
+		fmt.Println(strings.Repeat("-", width))
 
 		for _, partition := range details.PartitionDetails {
-			fmt.Printf("%-10d %-8d %-20s %-20s %-20s\n",
+			row := fmt.Sprintf("%-10d %-8d %-20s %-20s %-20s",
 				partition.ID,
 				partition.Leader,
 				formatInt32Slice(partition.Replicas),
 				formatInt32Slice(partition.InSyncReplicas),
 				formatInt32Slice(partition.OfflineReplicas))
+			if withOffsets {
+				row += fmt.Sprintf(" %-15s %-15s %-15s",
+					formatOptionalInt64(partition.EarliestOffset),
+					formatOptionalInt64(partition.LatestOffset),
+					formatOptionalInt64(partition.MessageCount))
+			}
+
+			// Highlight under-replicated and offline partitions
+			if len(partition.InSyncReplicas) < len(partition.Replicas) || len(partition.OfflineReplicas) > 0 || partition.Leader < 0 {
+				row = c.bad(row)
+			}
+			fmt.Println(row)
 		}
 		fmt.Println()
 	}
@@ -197,7 +586,7 @@ func displayTopicDetailsTable(details *types.TopicDetails) error {
 	// Configuration
 	if len(details.Configs) > 0 {
 		fmt.Println("Configuration:")
-		fmt.Printf("%-30s %s\n", "KEY", "VALUE")
+		fmt.Println(c.header(fmt.Sprintf("%-30s %s", "KEY", "VALUE")))
 		fmt.Println(strings.Repeat("-", 80))
 
 		for key, value := range details.Configs {
@@ -208,21 +597,119 @@ func displayTopicDetailsTable(details *types.TopicDetails) error {
 	return nil
 }
 
+// displayTopicConfigTable displays topic configuration in table format
+func displayTopicConfigTable(topicConfig *types.TopicConfig, c *colors) error {
+	fmt.Printf("Topic: %s\n", topicConfig.Topic)
+	fmt.Println(strings.Repeat("=", 50))
+
+	if len(topicConfig.Configs) == 0 {
+		fmt.Println("No configuration entries found")
+		return nil
+	}
+
+	keys := make([]string, 0, len(topicConfig.Configs))
+	for key := range topicConfig.Configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Print header
+	fmt.Println(c.header(fmt.Sprintf("%-40s %-25s %-55s %-10s", "KEY", "VALUE", "HUMANIZED", "SOURCE")))
+	fmt.Println(strings.Repeat("-", 133))
+
+	// Print entries
+	for _, key := range keys {
+		entry := topicConfig.Configs[key]
+
+		value := entry.Value
+		if entry.Sensitive {
+			value = "(sensitive)"
+		}
+
+		source := "default"
+		if !entry.Default {
+			source = "overridden"
+		}
+
+		fmt.Printf("%-40s %-25s %-55s %-10s\n", key, value, entry.Formatted, source)
+	}
+
+	return nil
+}
+
+// displayTopicDiffTable prints the diff in unified diff style, one line per
+// side of each differing key
+func displayTopicDiffTable(diff *types.TopicDiff, c *colors) error {
+	if diff.Identical() {
+		fmt.Printf("Topics '%s' and '%s' are identical\n", diff.TopicA, diff.TopicB)
+		return nil
+	}
+
+	fmt.Println(c.header("--- " + diff.TopicA))
+	fmt.Println(c.header("+++ " + diff.TopicB))
+	for _, section := range []struct {
+		name    string
+		entries []*types.DiffEntry
+	}{
+		{"structure", diff.Structure},
+		{"configs", diff.Configs},
+	} {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Printf("@@ %s @@\n", section.name)
+		for _, entry := range section.entries {
+			if entry.A != nil {
+				fmt.Println(c.bad(fmt.Sprintf("-%s=%s", entry.Key, *entry.A)))
+			}
+			if entry.B != nil {
+				fmt.Println(c.good(fmt.Sprintf("+%s=%s", entry.Key, *entry.B)))
+			}
+		}
+	}
+	return nil
+}
+
 // displayGroupTable displays consumer groups in table format
-func displayGroupTable(groupList *types.GroupList) error {
+func displayGroupTable(groupList *types.GroupList, c *colors) error {
 	if len(groupList.Groups) == 0 {
 		fmt.Println("No consumer groups found")
 		return nil
 	}
 
+	// The lag column is only shown when lag was requested
+	withLag := false
+	for _, group := range groupList.Groups {
+		if group.TotalLag != nil {
+			withLag = true
+			break
+		}
+	}
+
 	// Print header
-	fmt.Printf("%-40s %-15s %-15s %-10s\n", "GROUP ID", "STATE", "PROTOCOL TYPE", "MEMBERS")
-	fmt.Println(strings.Repeat("-", 80))
+	if withLag {
+		fmt.Println(c.header(fmt.Sprintf("%-40s %-15s %-15s %-10s %-12s", "GROUP ID", "STATE", "PROTOCOL TYPE", "MEMBERS", "LAG")))
+		fmt.Println(strings.Repeat("-", 93))
+	} else {
+		fmt.Println(c.header(fmt.Sprintf("%-40s %-15s %-15s %-10s", "GROUP ID", "STATE", "PROTOCOL TYPE", "MEMBERS")))
+		fmt.Println(strings.Repeat("-", 80))
+	}
 
 	// Print groups
 	for _, group := range groupList.Groups {
-		fmt.Printf("%-40s %-15s %-15s %-10d\n",
-			group.GroupID, group.State, group.ProtocolType, group.MemberCount)
+		state := c.groupState(group.State, fmt.Sprintf("%-15s", group.State))
+		if !withLag {
+			fmt.Printf("%-40s %s %-15s %-10d\n",
+				group.GroupID, state, group.ProtocolType, group.MemberCount)
+			continue
+		}
+
+		lag := "-"
+		if group.TotalLag != nil {
+			lag = strconv.FormatInt(*group.TotalLag, 10)
+		}
+		fmt.Printf("%-40s %s %-15s %-10d %-12s\n",
+			group.GroupID, state, group.ProtocolType, group.MemberCount, lag)
 	}
 
 	// Print pagination info
@@ -236,18 +723,54 @@ func displayGroupTable(groupList *types.GroupList) error {
 	return nil
 }
 
-// displayGroupDetailsTable displays consumer group details in table format
-func displayGroupDetailsTable(details *types.GroupDetails) error {
+// displayGroupMembersTable displays consumer group members in table format
+func displayGroupMembersTable(members []*types.MemberInfo, c *colors) error {
+	if len(members) == 0 {
+		fmt.Println("No members found")
+		return nil
+	}
+
+	// Print header
+	fmt.Println(c.header(fmt.Sprintf("%-50s %-25s %-25s %-10s", "MEMBER ID", "CLIENT ID", "HOST", "PARTITIONS")))
+	fmt.Println(strings.Repeat("-", 113))
+
+	// Print members
+	for _, member := range members {
+		fmt.Printf("%-50s %-25s %-25s %-10d\n",
+			member.MemberID, member.ClientID, member.Host, len(member.AssignedPartitions))
+	}
+
+	return nil
+}
+
+// displayGroupDetailsTable displays consumer group details in table format,
+// highlighting lag above threshold
+func displayGroupDetailsTable(details *types.GroupDetails, c *colors, threshold int64) error {
 	fmt.Printf("Consumer Group: %s\n", details.GroupID)
 	fmt.Println(strings.Repeat("=", 50))
 
 	// Basic information
-	fmt.Printf("State: %s\n", details.State)
+	fmt.Printf("State: %s\n", c.groupState(details.State, details.State))
 	fmt.Printf("Protocol Type: %s\n", details.ProtocolType)
 	fmt.Printf("Protocol: %s\n", details.Protocol)
 	fmt.Printf("Total Lag: %d\n", details.TotalLag)
 	fmt.Println()
 
+	// Lag summary per topic
+	if len(details.LagByTopic) > 0 {
+		topics := make([]string, 0, len(details.LagByTopic))
+		for topic := range details.LagByTopic {
+			topics = append(topics, topic)
+		}
+		sort.Strings(topics)
+
+		fmt.Println("Lag by Topic:")
+		for _, topic := range topics {
+			fmt.Printf("  %s: %d\n", topic, details.LagByTopic[topic])
+		}
+		fmt.Println()
+	}
+
 	// Coordinator information
 	if details.Coordinator != nil {
 		fmt.Println("Coordinator:")
@@ -265,20 +788,20 @@ func displayGroupDetailsTable(details *types.GroupDetails) error {
 			fmt.Printf("  Member ID: %s\n", member.MemberID)
 			fmt.Printf("  Client ID: %s\n", member.ClientID)
 			fmt.Printf("  Host: %s\n", member.Host)
-			fmt.Printf("  Total Lag: %d\n", member.TotalLag)
+			fmt.Printf("  Total Lag: %s\n", c.lag(member.TotalLag, threshold, fmt.Sprint(member.TotalLag)))
 
 			if len(member.AssignedPartitions) > 0 {
 				fmt.Println("  Assigned Partitions:")
-				fmt.Printf("    %-20s %-10s %-15s %-15s %-10s\n", "TOPIC", "PARTITION", "CURRENT OFFSET", "LOG END OFFSET", "LAG")
+				fmt.Println("    " + c.header(fmt.Sprintf("%-20s %-10s %-15s %-15s %-10s", "TOPIC", "PARTITION", "CURRENT OFFSET", "LOG END OFFSET", "LAG")))
 				fmt.Println("    " + strings.Repeat("-", 70))
 
 				for _, assignment := range member.AssignedPartitions {
-					fmt.Printf("    %-20s %-10d %-15d %-15d %-10d\n",
+					fmt.Printf("    %-20s %-10d %-15d %-15d %s\n",
 						assignment.Topic,
 						assignment.Partition,
 						assignment.CurrentOffset,
 						assignment.LogEndOffset,
-						assignment.Lag)
+						c.lag(assignment.Lag, threshold, fmt.Sprintf("%-10d", assignment.Lag)))
 				}
 			}
 			fmt.Println()
@@ -289,24 +812,44 @@ func displayGroupDetailsTable(details *types.GroupDetails) error {
 }
 
 // displayMessageTable displays a message in table format
-func displayMessageTable(message *types.Message) error {
-	fmt.Printf("Topic: %s | Partition: %d | Offset: %d | Timestamp: %s\n",
+func displayMessageTable(w io.Writer, message *types.Message) error {
+	fmt.Fprintf(w, "Topic: %s | Partition: %d | Offset: %d | Timestamp: %s\n",
 		message.Topic, message.Partition, message.Offset, message.Timestamp.Format(time.RFC3339))
 
 	if message.Key != "" {
-		fmt.Printf("Key: %s\n", message.Key)
+		fmt.Fprintf(w, "Key: %s\n", message.Key)
 	}
 
-	fmt.Printf("Value: %s\n", message.Value)
+	fmt.Fprintf(w, "Value: %s\n", message.Value)
 
 	if len(message.Headers) > 0 {
-		fmt.Println("Headers:")
+		fmt.Fprintln(w, "Headers:")
 		for key, value := range message.Headers {
-			fmt.Printf("  %s: %s\n", key, value)
+			fmt.Fprintf(w, "  %s: %s\n", key, value)
 		}
 	}
 
-	fmt.Println(strings.Repeat("-", 80))
+	_, err := fmt.Fprintln(w, strings.Repeat("-", 80))
+	return err
+}
+
+// displayMessageListTable displays a page of messages in table format
+func displayMessageListTable(messageList *types.MessageList) error {
+	if len(messageList.Messages) == 0 {
+		fmt.Println("No messages found")
+	}
+
+	for _, message := range messageList.Messages {
+		if err := displayMessageTable(os.Stdout, message); err != nil {
+			return err
+		}
+	}
+
+	if messageList.Pagination != nil && messageList.Pagination.NextOffset != nil {
+		fmt.Printf("\n%d messages (next offset: %d)\n",
+			len(messageList.Messages), *messageList.Pagination.NextOffset)
+	}
+
 	return nil
 }
 
@@ -321,14 +864,14 @@ func displayProduceResponseTable(response *types.ProduceResponse) error {
 }
 
 // displayProfileTable displays profiles in table format
-func displayProfileTable(profiles []*types.ProfileInfo) error {
+func displayProfileTable(profiles []*types.ProfileInfo, c *colors) error {
 	if len(profiles) == 0 {
 		fmt.Println("No profiles found")
 		return nil
 	}
 
 	// Print header
-	fmt.Printf("%-20s %-8s %-50s %-8s\n", "NAME", "TYPE", "DETAILS", "ACTIVE")
+	fmt.Println(c.header(fmt.Sprintf("%-20s %-8s %-50s %-8s", "NAME", "TYPE", "DETAILS", "ACTIVE")))
 	fmt.Println(strings.Repeat("-", 86))
 
 	// Print profiles
@@ -344,6 +887,330 @@ func displayProfileTable(profiles []*types.ProfileInfo) error {
 	return nil
 }
 
+// displayProfileDetailsTable displays profile details in table format.
+// Settings that are not configured are left out.
+func displayProfileDetailsTable(details *types.ProfileDetails) error {
+	fmt.Printf("Profile: %s\n", details.Name)
+	fmt.Println(strings.Repeat("=", 50))
+
+	fmt.Printf("%-22s %s\n", "Type:", details.Type)
+	fmt.Printf("%-22s %t\n", "Active:", details.Active)
+
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"Bootstrap Servers:", details.BootstrapServers},
+		{"Region:", details.Region},
+		{"Cluster ARN:", details.ClusterARN},
+		{"Auth Method:", details.AuthMethod},
+		{"AWS Profile:", details.AWSProfile},
+		{"AWS Role ARN:", details.AWSRoleARN},
+		{"Security Protocol:", details.SecurityProtocol},
+		{"SASL Mechanism:", details.SASLMechanism},
+		{"SASL Username:", details.SASLUsername},
+		{"SASL Password:", details.SASLPassword},
+		{"OAuth Token Command:", details.OAuthTokenCommand},
+		{"OAuth Token Env:", details.OAuthTokenEnv},
+		{"SSL CA File:", details.SSLCAFile},
+		{"SSL Cert File:", details.SSLCertFile},
+		{"SSL Key File:", details.SSLKeyFile},
+		{"SSL Password:", details.SSLPassword},
+		{"TLS Min Version:", details.TLSMinVersion},
+		{"TLS Server Name:", details.TLSServerName},
+		{"Schema Registry URL:", details.SchemaRegistryURL},
+		{"Kafka Version:", details.KafkaVersion},
+		{"Client ID:", details.ClientID},
+		{"Dial Timeout:", details.DialTimeout},
+		{"Read Timeout:", details.ReadTimeout},
+		{"Write Timeout:", details.WriteTimeout},
+	}
+	for _, field := range fields {
+		if field.value != "" {
+			fmt.Printf("%-22s %s\n", field.label, field.value)
+		}
+	}
+	if details.SSLCheckHostname {
+		fmt.Printf("%-22s %t\n", "SSL Check Hostname:", details.SSLCheckHostname)
+	}
+	if details.MetadataRetryMax > 0 {
+		fmt.Printf("%-22s %d\n", "Metadata Retry Max:", details.MetadataRetryMax)
+	}
+
+	if len(details.Extra) > 0 {
+		fmt.Println()
+		fmt.Println("Extra:")
+		keys := make([]string, 0, len(details.Extra))
+		for key := range details.Extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("  %s: %s\n", key, details.Extra[key])
+		}
+	}
+
+	return nil
+}
+
+// displayACLTable displays ACLs in table format
+func displayACLTable(acls []*types.ACLBinding, c *colors) error {
+	if len(acls) == 0 {
+		fmt.Println("No ACLs found")
+		return nil
+	}
+
+	// Print header
+	fmt.Println(c.header(fmt.Sprintf("%-30s %-16s %-30s %-10s %-16s %-16s %-10s",
+		"PRINCIPAL", "RESOURCE TYPE", "RESOURCE NAME", "PATTERN", "HOST", "OPERATION", "PERMISSION")))
+	fmt.Println(strings.Repeat("-", 134))
+
+	// Print ACLs
+	for _, acl := range acls {
+		fmt.Printf("%-30s %-16s %-30s %-10s %-16s %-16s %-10s\n",
+			acl.Principal, acl.ResourceType, acl.ResourceName, acl.PatternType,
+			acl.Host, acl.Operation, acl.Permission)
+	}
+
+	return nil
+}
+
+// displayQuotaTable displays quotas in table format
+func displayQuotaTable(quotas []*types.QuotaEntry, c *colors) error {
+	if len(quotas) == 0 {
+		fmt.Println("No quotas found")
+		return nil
+	}
+
+	// Print header
+	fmt.Println(c.header(fmt.Sprintf("%-25s %-25s %-18s %-18s %-12s",
+		"USER", "CLIENT ID", "PRODUCER RATE", "CONSUMER RATE", "REQUEST %")))
+	fmt.Println(strings.Repeat("-", 102))
+
+	// Print quotas
+	for _, quota := range quotas {
+		fmt.Printf("%-25s %-25s %-18s %-18s %-12s\n",
+			orDash(quota.User), orDash(quota.ClientID),
+			formatByteRate(quota.Values, "producer_byte_rate"),
+			formatByteRate(quota.Values, "consumer_byte_rate"),
+			formatQuotaValue(quota.Values, "request_percentage"))
+	}
+
+	return nil
+}
+
+// displayClusterHealthTable displays a cluster health check in table format
+func displayClusterHealthTable(health *types.ClusterHealth, c *colors) error {
+	status := strings.ToUpper(health.Status)
+	switch health.Status {
+	case types.HealthPass:
+		status = c.good(status)
+	case types.HealthWarn:
+		status = c.warn(status)
+	default:
+		status = c.bad(status)
+	}
+
+	clusterID := health.ClusterID
+	if clusterID == "" {
+		clusterID = "N/A"
+	}
+
+	fmt.Printf("Status: %s\n", status)
+	fmt.Printf("Cluster ID: %s\n", clusterID)
+	fmt.Printf("Controller: %d\n", health.ControllerID)
+	fmt.Printf("Brokers: %d\n", health.Brokers)
+	fmt.Printf("Topics: %d\n", health.Topics)
+	fmt.Printf("Partitions: %d\n", health.Partitions)
+	fmt.Printf("Under-replicated partitions: %d\n", health.UnderReplicatedPartitions)
+	fmt.Printf("Offline partitions: %d\n", health.OfflinePartitions)
+
+	if len(health.ProblemTopics) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(c.header(fmt.Sprintf("%-40s %-25s %-25s", "TOPIC", "UNDER-REPLICATED", "OFFLINE")))
+	fmt.Println(strings.Repeat("-", 92))
+
+	for _, topic := range health.ProblemTopics {
+		row := fmt.Sprintf("%-40s %-25s %-25s", topic.Topic,
+			formatInt32Slice(topic.UnderReplicatedPartitions), formatInt32Slice(topic.OfflinePartitions))
+		if len(topic.OfflinePartitions) > 0 {
+			row = c.bad(row)
+		} else {
+			row = c.warn(row)
+		}
+		fmt.Println(row)
+	}
+
+	return nil
+}
+
+// displayLeaderElectionTable displays the partitions whose leader changed in
+// table format, followed by the partitions that failed
+func displayLeaderElectionTable(election *types.LeaderElection, c *colors) error {
+	fmt.Printf("Elected preferred leaders for %d of %d partition(s)\n", len(election.Changed), election.Partitions)
+
+	if len(election.Changed) > 0 {
+		fmt.Println()
+		fmt.Println(c.header(fmt.Sprintf("%-40s %-10s %-16s %-10s", "TOPIC", "PARTITION", "PREVIOUS LEADER", "LEADER")))
+		fmt.Println(strings.Repeat("-", 79))
+		for _, partition := range election.Changed {
+			fmt.Printf("%-40s %-10d %-16d %-10d\n", partition.Topic, partition.Partition, partition.PreviousLeader, partition.Leader)
+		}
+	}
+
+	if len(election.Failed) > 0 {
+		fmt.Println()
+		fmt.Println(c.header(fmt.Sprintf("%-40s %-10s %s", "TOPIC", "PARTITION", "ERROR")))
+		fmt.Println(strings.Repeat("-", 79))
+		for _, partition := range election.Failed {
+			fmt.Println(c.bad(fmt.Sprintf("%-40s %-10d %s", partition.Topic, partition.Partition, partition.Error)))
+		}
+	}
+
+	return nil
+}
+
+// displayVersionInfoTable displays version information in table format
+func displayVersionInfoTable(info *types.VersionInfo) error {
+	fmt.Printf("%-24s %s\n", "Version:", info.Version)
+	fmt.Printf("%-24s %s\n", "Commit:", info.Commit)
+	fmt.Printf("%-24s %s\n", "Build Date:", info.BuildDate)
+	fmt.Printf("%-24s %s\n", "Go Version:", info.GoVersion)
+	fmt.Printf("%-24s %s\n", "Sarama Version:", info.SaramaVersion)
+	fmt.Printf("%-24s %s\n", "Kafka Protocol Version:", info.KafkaProtocolVersion)
+	return nil
+}
+
+// displayClusterInfoTable displays cluster information in table format
+func displayClusterInfoTable(info *types.ClusterInfo, c *colors) error {
+	clusterID := info.ClusterID
+	if clusterID == "" {
+		clusterID = "N/A"
+	}
+
+	fmt.Printf("Cluster ID: %s\n", clusterID)
+	fmt.Printf("Controller: %d\n", info.ControllerID)
+	fmt.Printf("Brokers: %d\n", len(info.Brokers))
+	fmt.Println()
+
+	if len(info.Brokers) == 0 {
+		fmt.Println("No brokers found")
+		return nil
+	}
+
+	// Print header
+	fmt.Println(c.header(fmt.Sprintf("%-10s %-40s %-8s %-10s", "ID", "HOST", "PORT", "CONTROLLER")))
+	fmt.Println(strings.Repeat("-", 71))
+
+	// Print brokers
+	for _, broker := range info.Brokers {
+		controller := ""
+		if broker.ID == info.ControllerID {
+			controller = "*"
+		}
+		fmt.Printf("%-10d %-40s %-8d %-10s\n", broker.ID, broker.Host, broker.Port, controller)
+	}
+
+	return nil
+}
+
+// displayBrokerConfigTable displays broker configuration in table format
+func displayBrokerConfigTable(brokerConfig *types.BrokerConfig, c *colors) error {
+	fmt.Printf("Broker: %d\n", brokerConfig.BrokerID)
+	fmt.Println(strings.Repeat("=", 50))
+
+	if len(brokerConfig.Configs) == 0 {
+		fmt.Println("No configuration entries found")
+		return nil
+	}
+
+	keys := make([]string, 0, len(brokerConfig.Configs))
+	for key := range brokerConfig.Configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Print header
+	fmt.Println(c.header(fmt.Sprintf("%-50s %-40s %-22s", "KEY", "VALUE", "SOURCE")))
+	fmt.Println(strings.Repeat("-", 114))
+
+	// Print entries
+	for _, key := range keys {
+		entry := brokerConfig.Configs[key]
+
+		value := entry.Value
+		if entry.Sensitive {
+			value = "(sensitive)"
+		} else if entry.Formatted != "" {
+			value = entry.Formatted
+		}
+
+		source := entry.Source
+		if !entry.Default {
+			source += " (overridden)"
+		}
+
+		fmt.Printf("%-50s %-40s %-22s\n", key, value, source)
+	}
+
+	return nil
+}
+
+// formatBytes formats a byte count using binary units, e.g. "1.50 MB"
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+
+	size := float64(bytes)
+	idx := 0
+	for size >= unit && idx < len(units)-1 {
+		size /= unit
+		idx++
+	}
+
+	if idx == 0 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	return fmt.Sprintf("%.2f %s", size, units[idx])
+}
+
+// formatByteRate formats a byte-rate quota, or "-" if it is not set
+func formatByteRate(values map[string]float64, key string) string {
+	value, ok := values[key]
+	if !ok {
+		return "-"
+	}
+	return formatBytes(int64(value)) + "/s"
+}
+
+// formatQuotaValue formats a quota value, or "-" if it is not set
+func formatQuotaValue(values map[string]float64, key string) string {
+	value, ok := values[key]
+	if !ok {
+		return "-"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// orDash returns s, or "-" if s is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatOptionalInt64 formats a value that may be unavailable, shown as "-"
+func formatOptionalInt64(value *int64) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.FormatInt(*value, 10)
+}
+
 // formatInt32Slice formats a slice of int32 as a comma-separated string
 func formatInt32Slice(slice []int32) string {
 	if len(slice) == 0 {
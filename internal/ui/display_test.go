@@ -110,6 +110,31 @@ func TestDisplayTopicDetails(t *testing.T) {
 	if !strings.Contains(output, "test-topic") {
 		t.Error("Output should contain topic name")
 	}
+	if strings.Contains(output, "WARNING") {
+		t.Errorf("Healthy topic should not show warnings, got:\n%s", output)
+	}
+}
+
+func TestDisplayTopicDetailsHealthWarnings(t *testing.T) {
+	details := &types.TopicDetails{
+		Name:              "test-topic",
+		Partitions:        2,
+		UnderReplicated:   true,
+		OfflinePartitions: []int32{1},
+	}
+
+	output := captureOutput(func() {
+		if err := DisplayTopicDetails(details, &types.DisplayOptions{Format: "table"}); err != nil {
+			t.Errorf("DisplayTopicDetails failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "WARNING: topic is under-replicated") {
+		t.Errorf("Expected an under-replication warning, got:\n%s", output)
+	}
+	if !strings.Contains(output, "WARNING: offline partitions: [1]") {
+		t.Errorf("Expected an offline partitions warning, got:\n%s", output)
+	}
 }
 
 func TestDisplayGroupList(t *testing.T) {
@@ -212,6 +237,63 @@ func TestDisplayMessage(t *testing.T) {
 	}
 }
 
+func TestDisplayMessageJSONLines(t *testing.T) {
+	message := &types.Message{
+		Topic:     "test-topic",
+		Partition: 0,
+		Offset:    42,
+		Key:       "test-key",
+		Value:     "{\n  \"id\": 1\n}",
+		Headers:   map[string]string{"source": "app"},
+		Timestamp: time.Now(),
+	}
+
+	opts := &types.DisplayOptions{Format: "jsonl"}
+	output := captureOutput(func() {
+		err := DisplayMessage(message, opts)
+		if err != nil {
+			t.Errorf("DisplayMessage jsonl failed: %v", err)
+		}
+	})
+
+	if !strings.HasSuffix(output, "\n") {
+		t.Fatal("JSON line should be terminated by a newline")
+	}
+	if strings.Count(output, "\n") != 1 {
+		t.Errorf("Expected a single line, got: %q", output)
+	}
+	for _, field := range []string{`"key":"test-key"`, `"value":`, `"headers":{"source":"app"}`} {
+		if !strings.Contains(output, field) {
+			t.Errorf("JSON line should contain %s, got: %s", field, output)
+		}
+	}
+}
+
+func TestDisplayMessageList(t *testing.T) {
+	next := int64(43)
+	messageList := &types.MessageList{
+		Messages: []*types.Message{
+			{Topic: "test-topic", Partition: 0, Offset: 42, Value: "hello", Timestamp: time.Now()},
+		},
+		Pagination: &types.Pagination{NextOffset: &next},
+	}
+
+	opts := &types.DisplayOptions{Format: "table"}
+	output := captureOutput(func() {
+		err := DisplayMessageList(messageList, opts)
+		if err != nil {
+			t.Errorf("DisplayMessageList failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "hello") {
+		t.Error("Output should contain message value")
+	}
+	if !strings.Contains(output, "next offset: 43") {
+		t.Error("Output should contain the next offset")
+	}
+}
+
 func TestDisplayProfileList(t *testing.T) {
 	profiles := []*types.ProfileInfo{
 		{
@@ -245,6 +327,132 @@ func TestDisplayProfileList(t *testing.T) {
 	}
 }
 
+func TestDisplayACLList(t *testing.T) {
+	acls := []*types.ACLBinding{
+		{
+			ResourceType: "Topic",
+			ResourceName: "orders",
+			PatternType:  "Literal",
+			Principal:    "User:alice",
+			Host:         "*",
+			Operation:    "Read",
+			Permission:   "Allow",
+		},
+	}
+
+	// Test table format
+	opts := &types.DisplayOptions{Format: "table"}
+	output := captureOutput(func() {
+		if err := DisplayACLList(acls, opts); err != nil {
+			t.Errorf("DisplayACLList failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "User:alice") || !strings.Contains(output, "orders") {
+		t.Error("Output should contain the principal and resource name")
+	}
+
+	// Test empty list
+	output = captureOutput(func() {
+		if err := DisplayACLList([]*types.ACLBinding{}, opts); err != nil {
+			t.Errorf("DisplayACLList failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "No ACLs found") {
+		t.Error("Output should report that no ACLs were found")
+	}
+}
+
+func TestDisplayQuotaList(t *testing.T) {
+	quotas := []*types.QuotaEntry{
+		{User: "alice", Values: map[string]float64{"producer_byte_rate": 1048576}},
+	}
+
+	opts := &types.DisplayOptions{Format: "table"}
+	output := captureOutput(func() {
+		if err := DisplayQuotaList(quotas, opts); err != nil {
+			t.Errorf("DisplayQuotaList failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "alice") {
+		t.Error("Output should contain the user")
+	}
+	if !strings.Contains(output, "1.00 MB/s") {
+		t.Errorf("Output should contain the humanized producer rate, got:\n%s", output)
+	}
+}
+
+func TestDisplayClusterInfo(t *testing.T) {
+	info := &types.ClusterInfo{
+		ClusterID:    "abc123",
+		ControllerID: 2,
+		Brokers: []*types.BrokerInfo{
+			{ID: 1, Host: "broker-1", Port: 9092},
+			{ID: 2, Host: "broker-2", Port: 9092},
+		},
+	}
+
+	// Test table format
+	opts := &types.DisplayOptions{Format: "table"}
+	output := captureOutput(func() {
+		err := DisplayClusterInfo(info, opts)
+		if err != nil {
+			t.Errorf("DisplayClusterInfo failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "abc123") {
+		t.Error("Output should contain cluster ID")
+	}
+	if !strings.Contains(output, "broker-2") {
+		t.Error("Output should contain broker host")
+	}
+
+	// Test JSON format
+	opts.Format = "json"
+	output = captureOutput(func() {
+		err := DisplayClusterInfo(info, opts)
+		if err != nil {
+			t.Errorf("DisplayClusterInfo JSON failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, `"controller_id": 2`) {
+		t.Error("JSON output should contain controller ID")
+	}
+}
+
+func TestDisplayBrokerConfig(t *testing.T) {
+	brokerConfig := &types.BrokerConfig{
+		BrokerID: 1,
+		Configs: map[string]*types.ConfigEntry{
+			"log.retention.ms":      {Value: "604800000", Formatted: "7 days 0 hours", Source: "StaticBroker"},
+			"num.io.threads":        {Value: "8", Source: "Default", Default: true},
+			"ssl.keystore.password": {Source: "StaticBroker", Sensitive: true},
+		},
+	}
+
+	opts := &types.DisplayOptions{Format: "table"}
+	output := captureOutput(func() {
+		err := DisplayBrokerConfig(brokerConfig, opts)
+		if err != nil {
+			t.Errorf("DisplayBrokerConfig failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "7 days 0 hours") {
+		t.Error("Output should contain formatted value")
+	}
+	if !strings.Contains(output, "(overridden)") {
+		t.Error("Output should mark overridden entries")
+	}
+	if !strings.Contains(output, "(sensitive)") {
+		t.Error("Output should mask sensitive entries")
+	}
+}
+
 func TestDisplayInvalidFormat(t *testing.T) {
 	topicList := &types.TopicList{
 		Topics: []*types.TopicInfo{
@@ -332,3 +540,80 @@ func TestDisplayNilData(t *testing.T) {
 		t.Error("Should return error for nil profile list")
 	}
 }
+
+func TestDisplayTopicListWithSize(t *testing.T) {
+	count, size := int64(12), int64(1536)
+	topicList := &types.TopicList{
+		Topics: []*types.TopicInfo{
+			{Name: "orders", Partitions: 2, ReplicationFactor: 2, MessageCount: &count, SizeBytes: &size},
+			{Name: "payments", Partitions: 1, ReplicationFactor: 1, MessageCount: new(int64)},
+		},
+	}
+
+	output := captureOutput(func() {
+		if err := DisplayTopicList(topicList, &types.DisplayOptions{Format: "table"}); err != nil {
+			t.Errorf("DisplayTopicList failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "MESSAGES") || !strings.Contains(output, "SIZE") {
+		t.Errorf("Expected size columns, got:\n%s", output)
+	}
+	if !strings.Contains(output, "12") || !strings.Contains(output, "1.50 KB") {
+		t.Errorf("Expected the orders size, got:\n%s", output)
+	}
+}
+
+func TestDisplayTopicListTemplate(t *testing.T) {
+	topicList := &types.TopicList{
+		Topics: []*types.TopicInfo{
+			{Name: "orders", Partitions: 3},
+			{Name: "payments", Partitions: 6},
+		},
+	}
+
+	opts := &types.DisplayOptions{
+		Format:   "go-template",
+		Template: "{{range .Topics}}{{.Name}}:{{.Partitions}}\n{{end}}",
+	}
+
+	var err error
+	output := captureOutput(func() {
+		err = DisplayTopicList(topicList, opts)
+	})
+	if err != nil {
+		t.Fatalf("DisplayTopicList failed: %v", err)
+	}
+	if output != "orders:3\npayments:6\n" {
+		t.Errorf("Unexpected template output: %q", output)
+	}
+}
+
+func TestDisplayTemplateErrors(t *testing.T) {
+	topicList := &types.TopicList{Topics: []*types.TopicInfo{{Name: "orders"}}}
+
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{"missing template", "", "template is required"},
+		{"parse error", "{{range .Topics}", "invalid template"},
+		{"unknown field", "{{.Missing}}", "failed to execute template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			output := captureOutput(func() {
+				err = DisplayTopicList(topicList, &types.DisplayOptions{Format: "go-template", Template: tt.template})
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if output != "" {
+				t.Errorf("Nothing should be written on error, got %q", output)
+			}
+		})
+	}
+}
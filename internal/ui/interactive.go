@@ -3,6 +3,8 @@ package ui
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/nipunap/kim/internal/client"
@@ -17,20 +19,28 @@ import (
 
 // InteractiveMode represents the interactive UI state
 type InteractiveMode struct {
-	cfg           *config.Config
-	log           *logger.Logger
-	clientManager *client.Manager
-	currentView   string
-	content       string
-	statusMsg     string
-	commandMode   bool
-	searchMode    bool
-	currentCmd    string
-	searchPattern string
-	scrollOffset  int
-	maxLines      int
-	width         int
-	height        int
+	cfg            *config.Config
+	log            *logger.Logger
+	clientManager  *client.Manager
+	currentView    string
+	content        string
+	statusMsg      string
+	commandMode    bool
+	searchMode     bool
+	confirmMode    bool
+	confirmPrompt  string
+	confirmAction  func() (tea.Model, tea.Cmd)
+	currentCmd     string
+	commandHistory []string
+	historyIndex   int
+	searchPattern  string
+	searchRegex    *regexp.Regexp
+	searchMatches  []int
+	matchIndex     int
+	scrollOffset   int
+	maxLines       int
+	width          int
+	height         int
 }
 
 // NewInteractiveMode creates a new interactive mode instance
@@ -48,13 +58,22 @@ func NewInteractiveMode(cfg *config.Config, log *logger.Logger) *InteractiveMode
 	}
 }
 
-// Run starts the interactive mode
+// Run starts the interactive mode. The session's connections are closed when it exits.
 func (im *InteractiveMode) Run() error {
+	defer im.close()
+
 	p := tea.NewProgram(im, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
 
+// close closes the clients opened during the session
+func (im *InteractiveMode) close() {
+	if err := im.clientManager.CloseAll(); err != nil {
+		im.log.Warn("Failed to close clients", "error", err)
+	}
+}
+
 // Init implements tea.Model
 func (im *InteractiveMode) Init() tea.Cmd {
 	return nil
@@ -108,7 +127,7 @@ func (im *InteractiveMode) View() string {
 	// Build content with scrolling
 	contentLines := strings.Split(im.content, "\n")
 	visibleLines := im.getVisibleContent(contentLines)
-	content := strings.Join(visibleLines, "\n")
+	content := im.renderContent(visibleLines)
 
 	// Build status bar
 	scrollInfo := ""
@@ -124,6 +143,8 @@ func (im *InteractiveMode) View() string {
 	commandLine := ""
 	if im.commandMode {
 		commandLine = commandStyle.Render(":" + im.currentCmd)
+	} else if im.confirmMode {
+		commandLine = commandStyle.Render(im.confirmPrompt + " (y/n)")
 	} else if im.searchMode {
 		commandLine = commandStyle.Render("/" + im.searchPattern)
 	} else {
@@ -147,6 +168,8 @@ func (im *InteractiveMode) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return im.handleCommandMode(msg)
 	case im.searchMode:
 		return im.handleSearchMode(msg)
+	case im.confirmMode:
+		return im.handleConfirmMode(msg)
 	default:
 		return im.handleNormalMode(msg)
 	}
@@ -161,6 +184,7 @@ func (im *InteractiveMode) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	case ":":
 		im.commandMode = true
 		im.currentCmd = ""
+		im.historyIndex = len(im.commandHistory)
 		return im, nil
 
 	case "/":
@@ -192,6 +216,14 @@ func (im *InteractiveMode) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		im.scrollToBottom()
 		return im, nil
 
+	case "n":
+		im.nextMatch()
+		return im, nil
+
+	case "N":
+		im.previousMatch()
+		return im, nil
+
 	case "r":
 		return im.refreshCurrentView()
 	}
@@ -206,6 +238,9 @@ func (im *InteractiveMode) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		cmd := im.currentCmd
 		im.commandMode = false
 		im.currentCmd = ""
+		if strings.TrimSpace(cmd) != "" {
+			im.commandHistory = append(im.commandHistory, cmd)
+		}
 		return im.executeCommand(cmd)
 
 	case "esc":
@@ -213,6 +248,25 @@ func (im *InteractiveMode) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		im.currentCmd = ""
 		return im, nil
 
+	case "up":
+		// Recall the previous command
+		if im.historyIndex > 0 {
+			im.historyIndex--
+			im.currentCmd = im.commandHistory[im.historyIndex]
+		}
+		return im, nil
+
+	case "down":
+		// Move towards the most recent command, ending on an empty line
+		if im.historyIndex < len(im.commandHistory)-1 {
+			im.historyIndex++
+			im.currentCmd = im.commandHistory[im.historyIndex]
+		} else {
+			im.historyIndex = len(im.commandHistory)
+			im.currentCmd = ""
+		}
+		return im, nil
+
 	case "backspace":
 		if len(im.currentCmd) > 0 {
 			im.currentCmd = im.currentCmd[:len(im.currentCmd)-1]
@@ -256,6 +310,39 @@ func (im *InteractiveMode) handleSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	}
 }
 
+// handleConfirmMode handles confirmation mode key presses. Only 'y' runs the
+// pending action; 'n' and ESC cancel it.
+func (im *InteractiveMode) handleConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		action := im.confirmAction
+		im.clearConfirm()
+		return action()
+
+	case "n", "N", "esc":
+		im.clearConfirm()
+		im.statusMsg = "Cancelled"
+		return im, nil
+	}
+
+	return im, nil
+}
+
+// requestConfirm enters confirmation mode, running action once the user confirms
+func (im *InteractiveMode) requestConfirm(prompt string, action func() (tea.Model, tea.Cmd)) {
+	im.confirmMode = true
+	im.confirmPrompt = prompt
+	im.confirmAction = action
+	im.statusMsg = prompt + " (y/n)"
+}
+
+// clearConfirm leaves confirmation mode without running the pending action
+func (im *InteractiveMode) clearConfirm() {
+	im.confirmMode = false
+	im.confirmPrompt = ""
+	im.confirmAction = nil
+}
+
 // executeCommand executes a command
 func (im *InteractiveMode) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 	parts := strings.Fields(cmd)
@@ -263,6 +350,9 @@ func (im *InteractiveMode) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 		return im, nil
 	}
 
+	// Matches refer to the current content, which commands may replace
+	im.clearSearch()
+
 	switch parts[0] {
 	case "q", "quit":
 		return im, tea.Quit
@@ -279,6 +369,19 @@ func (im *InteractiveMode) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 	case "groups":
 		return im.showGroups()
 
+	case "create-topic":
+		return im.createTopic(parts[1:])
+
+	case "delete-topic":
+		if len(parts) != 2 {
+			im.statusMsg = "Usage: delete-topic <name>"
+			return im, nil
+		}
+		name := parts[1]
+		im.requestConfirm(fmt.Sprintf("Delete topic '%s'?", name), func() (tea.Model, tea.Cmd) {
+			return im.deleteTopic(name)
+		})
+
 	case "profile":
 		if len(parts) > 1 {
 			return im.handleProfileCommand(parts[1:])
@@ -294,19 +397,11 @@ func (im *InteractiveMode) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 
 // showTopics displays the topics view
 func (im *InteractiveMode) showTopics() (tea.Model, tea.Cmd) {
-	profile, err := im.cfg.GetActiveProfile()
-	if err != nil {
-		im.statusMsg = "No active profile set"
+	topicManager, ok := im.topicManager()
+	if !ok {
 		return im, nil
 	}
 
-	kafkaClient, err := im.clientManager.GetClient(profile)
-	if err != nil {
-		im.statusMsg = fmt.Sprintf("Failed to connect: %s", err.Error())
-		return im, nil
-	}
-
-	topicManager := manager.NewTopicManager(kafkaClient, im.log)
 	opts := &types.ListOptions{
 		Page:     1,
 		PageSize: 100,
@@ -345,17 +440,96 @@ func (im *InteractiveMode) showTopics() (tea.Model, tea.Cmd) {
 	return im, nil
 }
 
-// showGroups displays the consumer groups view
-func (im *InteractiveMode) showGroups() (tea.Model, tea.Cmd) {
+// createTopic creates a topic from create-topic arguments and shows the topics view
+func (im *InteractiveMode) createTopic(args []string) (tea.Model, tea.Cmd) {
+	if len(args) != 3 {
+		im.statusMsg = "Usage: create-topic <name> <partitions> <replication-factor>"
+		return im, nil
+	}
+
+	partitions, err := strconv.ParseInt(args[1], 10, 32)
+	if err != nil || partitions < 1 {
+		im.statusMsg = fmt.Sprintf("Invalid partition count: %s", args[1])
+		return im, nil
+	}
+
+	replicationFactor, err := strconv.ParseInt(args[2], 10, 16)
+	if err != nil || replicationFactor < 1 {
+		im.statusMsg = fmt.Sprintf("Invalid replication factor: %s", args[2])
+		return im, nil
+	}
+
+	topicManager, ok := im.topicManager()
+	if !ok {
+		return im, nil
+	}
+
+	req := &types.CreateTopicRequest{
+		Name:              args[0],
+		Partitions:        int32(partitions),
+		ReplicationFactor: int16(replicationFactor),
+	}
+
+	if err := topicManager.CreateTopic(context.Background(), req); err != nil {
+		im.statusMsg = fmt.Sprintf("Failed to create topic: %s", err.Error())
+		return im, nil
+	}
+
+	im.showTopics()
+	im.statusMsg = fmt.Sprintf("Topic '%s' created", req.Name)
+	return im, nil
+}
+
+// deleteTopic deletes a topic and shows the topics view
+func (im *InteractiveMode) deleteTopic(name string) (tea.Model, tea.Cmd) {
+	topicManager, ok := im.topicManager()
+	if !ok {
+		return im, nil
+	}
+
+	if err := topicManager.DeleteTopic(context.Background(), name); err != nil {
+		im.statusMsg = fmt.Sprintf("Failed to delete topic: %s", err.Error())
+		return im, nil
+	}
+
+	im.showTopics()
+	im.statusMsg = fmt.Sprintf("Topic '%s' deleted", name)
+	return im, nil
+}
+
+// topicManager creates a topic manager for the active profile. On failure the
+// reason is shown in the status bar and false is returned.
+func (im *InteractiveMode) topicManager() (*manager.TopicManager, bool) {
+	kafkaClient, ok := im.activeClient()
+	if !ok {
+		return nil, false
+	}
+	return manager.NewTopicManager(kafkaClient, im.log), true
+}
+
+// activeClient returns the client of the active profile. The client manager
+// caches it, so all views share one connection until the profile is switched.
+// On failure the reason is shown in the status bar and false is returned.
+func (im *InteractiveMode) activeClient() (*client.Client, bool) {
 	profile, err := im.cfg.GetActiveProfile()
 	if err != nil {
 		im.statusMsg = "No active profile set"
-		return im, nil
+		return nil, false
 	}
 
 	kafkaClient, err := im.clientManager.GetClient(profile)
 	if err != nil {
 		im.statusMsg = fmt.Sprintf("Failed to connect: %s", err.Error())
+		return nil, false
+	}
+
+	return kafkaClient, true
+}
+
+// showGroups displays the consumer groups view
+func (im *InteractiveMode) showGroups() (tea.Model, tea.Cmd) {
+	kafkaClient, ok := im.activeClient()
+	if !ok {
 		return im, nil
 	}
 
@@ -381,12 +555,12 @@ func (im *InteractiveMode) showGroups() (tea.Model, tea.Cmd) {
 	if len(groupList.Groups) == 0 {
 		content.WriteString("No consumer groups found\n")
 	} else {
-		content.WriteString(fmt.Sprintf("%-30s %-15s %-15s\n", "GROUP ID", "STATE", "PROTOCOL TYPE"))
-		content.WriteString(strings.Repeat("-", 60) + "\n")
+		content.WriteString(fmt.Sprintf("%-30s %-20s %-8s %-15s\n", "GROUP ID", "STATE", "MEMBERS", "PROTOCOL TYPE"))
+		content.WriteString(strings.Repeat("-", 75) + "\n")
 
 		for _, group := range groupList.Groups {
-			content.WriteString(fmt.Sprintf("%-30s %-15s %-15s\n",
-				group.GroupID, group.State, group.ProtocolType))
+			content.WriteString(fmt.Sprintf("%-30s %-20s %-8d %-15s\n",
+				group.GroupID, group.State, group.MemberCount, group.ProtocolType))
 		}
 	}
 
@@ -450,10 +624,18 @@ func (im *InteractiveMode) handleProfileCommand(args []string) (tea.Model, tea.C
 			return im, nil
 		}
 
+		previous := im.cfg.ActiveProfile
 		if err := im.cfg.SetActiveProfile(args[1]); err != nil {
 			im.statusMsg = fmt.Sprintf("Failed to set profile: %s", err.Error())
-		} else {
-			im.statusMsg = fmt.Sprintf("Switched to profile: %s", args[1])
+			return im, nil
+		}
+		im.statusMsg = fmt.Sprintf("Switched to profile: %s", args[1])
+
+		// The previous profile's connection is not used anymore
+		if previous != args[1] {
+			if err := im.clientManager.Invalidate(previous); err != nil {
+				im.log.Warn("Failed to close client", "profile", previous, "error", err)
+			}
 		}
 
 	case "list":
@@ -481,22 +663,78 @@ func (im *InteractiveMode) refreshCurrentView() (tea.Model, tea.Cmd) {
 	return im, nil
 }
 
-// performSearch performs a search in the current content
+// performSearch finds all lines of the current content containing the pattern
+// (case-insensitive) and scrolls to the first match
 func (im *InteractiveMode) performSearch(pattern string) {
 	if pattern == "" {
 		return
 	}
 
-	lines := strings.Split(im.content, "\n")
-	for i, line := range lines {
-		if strings.Contains(strings.ToLower(line), strings.ToLower(pattern)) {
-			im.scrollOffset = max(0, i-2) // Show 2 lines before the match
-			im.statusMsg = fmt.Sprintf("Found '%s' at line %d", pattern, i+1)
-			return
+	im.clearSearch()
+	im.searchRegex = regexp.MustCompile("(?i)" + regexp.QuoteMeta(pattern))
+
+	for i, line := range strings.Split(im.content, "\n") {
+		if im.searchRegex.MatchString(line) {
+			im.searchMatches = append(im.searchMatches, i)
 		}
 	}
 
-	im.statusMsg = fmt.Sprintf("Pattern '%s' not found", pattern)
+	if len(im.searchMatches) == 0 {
+		im.searchRegex = nil
+		im.statusMsg = fmt.Sprintf("Pattern '%s' not found", pattern)
+		return
+	}
+
+	im.showMatch(0)
+}
+
+// nextMatch jumps to the next search match, wrapping around at the end
+func (im *InteractiveMode) nextMatch() {
+	if len(im.searchMatches) == 0 {
+		im.statusMsg = "No active search"
+		return
+	}
+	im.showMatch((im.matchIndex + 1) % len(im.searchMatches))
+}
+
+// previousMatch jumps to the previous search match, wrapping around at the start
+func (im *InteractiveMode) previousMatch() {
+	if len(im.searchMatches) == 0 {
+		im.statusMsg = "No active search"
+		return
+	}
+	im.showMatch((im.matchIndex - 1 + len(im.searchMatches)) % len(im.searchMatches))
+}
+
+// showMatch scrolls the given search match to the top of the view, as far as the
+// content allows, and reports it in the status bar
+func (im *InteractiveMode) showMatch(index int) {
+	lines := strings.Split(im.content, "\n")
+	im.matchIndex = index
+	im.scrollOffset = min(im.searchMatches[index], max(0, len(lines)-im.maxLines))
+	im.statusMsg = fmt.Sprintf("match %d of %d", index+1, len(im.searchMatches))
+}
+
+// clearSearch removes the active search and its highlighting
+func (im *InteractiveMode) clearSearch() {
+	im.searchRegex = nil
+	im.searchMatches = nil
+	im.matchIndex = 0
+}
+
+// renderContent joins the visible lines, highlighting search matches in reverse video
+func (im *InteractiveMode) renderContent(lines []string) string {
+	if im.searchRegex == nil {
+		return strings.Join(lines, "\n")
+	}
+
+	highlighted := make([]string, len(lines))
+	for i, line := range lines {
+		highlighted[i] = im.searchRegex.ReplaceAllStringFunc(line, func(match string) string {
+			return "\x1b[7m" + match + "\x1b[27m"
+		})
+	}
+	return strings.Join(highlighted, "\n")
 }
 
 // Scrolling methods
@@ -552,6 +790,9 @@ COMMANDS:
   :help                 Show this help
   :topics               List all topics
   :groups               List consumer groups
+  :create-topic <name> <partitions> <replication-factor>
+                        Create a topic
+  :delete-topic <name>  Delete a topic (asks for confirmation)
   :profile list         List profiles
   :profile use <name>   Switch to profile
   :q or :quit           Quit
@@ -567,9 +808,12 @@ NAVIGATION:
 
 SEARCH:
   /<pattern>           Search for pattern
+  n                    Next match
+  N                    Previous match
 
 MODES:
   :                    Enter command mode
+  ↑/↓                  Previous/next command (in command mode)
   /                    Enter search mode
   ESC                  Exit current mode
 
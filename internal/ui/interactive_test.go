@@ -0,0 +1,309 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nipunap/kim/internal/client"
+	"github.com/nipunap/kim/internal/config"
+	"github.com/nipunap/kim/internal/testutil"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestInteractiveMode creates an interactive mode backed by the test config
+func newTestInteractiveMode() *InteractiveMode {
+	return NewInteractiveMode(testutil.TestConfig(), testutil.TestLogger())
+}
+
+// typeKeys sends each rune of s as a key press
+func typeKeys(im *InteractiveMode, s string) {
+	for _, r := range s {
+		im.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// pressKey sends a special key press
+func pressKey(im *InteractiveMode, keyType tea.KeyType) {
+	im.Update(tea.KeyMsg{Type: keyType})
+}
+
+// runCommand enters command mode, types a command and executes it
+func runCommand(im *InteractiveMode, cmd string) {
+	typeKeys(im, ":")
+	typeKeys(im, cmd)
+	pressKey(im, tea.KeyEnter)
+}
+
+func TestInteractiveCommandHistory(t *testing.T) {
+	im := newTestInteractiveMode()
+
+	runCommand(im, "help")
+	runCommand(im, "profile list")
+
+	typeKeys(im, ":")
+
+	pressKey(im, tea.KeyUp)
+	if im.currentCmd != "profile list" {
+		t.Errorf("First up should recall the last command, got %q", im.currentCmd)
+	}
+
+	pressKey(im, tea.KeyUp)
+	if im.currentCmd != "help" {
+		t.Errorf("Second up should recall the first command, got %q", im.currentCmd)
+	}
+
+	// Up at the oldest command stays there
+	pressKey(im, tea.KeyUp)
+	if im.currentCmd != "help" {
+		t.Errorf("Up at the oldest command should keep it, got %q", im.currentCmd)
+	}
+
+	pressKey(im, tea.KeyDown)
+	if im.currentCmd != "profile list" {
+		t.Errorf("Down should move to the next command, got %q", im.currentCmd)
+	}
+
+	pressKey(im, tea.KeyDown)
+	if im.currentCmd != "" {
+		t.Errorf("Down past the newest command should clear the line, got %q", im.currentCmd)
+	}
+
+	// Leaving and re-entering command mode starts from the newest command again
+	pressKey(im, tea.KeyEsc)
+	typeKeys(im, ":")
+	pressKey(im, tea.KeyUp)
+	if im.currentCmd != "profile list" {
+		t.Errorf("History index should reset on entering command mode, got %q", im.currentCmd)
+	}
+}
+
+func TestInteractiveCommandHistorySkipsEmpty(t *testing.T) {
+	im := newTestInteractiveMode()
+
+	runCommand(im, "help")
+	runCommand(im, "")
+
+	if len(im.commandHistory) != 1 {
+		t.Errorf("Empty commands should not be recorded, got %v", im.commandHistory)
+	}
+}
+
+func TestInteractiveSearch(t *testing.T) {
+	im := newTestInteractiveMode()
+	im.maxLines = 5
+
+	lines := make([]string, 30)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("topic-%02d", i)
+	}
+	lines[8] = "orders-events"
+	lines[20] = "ORDERS-archive"
+	im.content = strings.Join(lines, "\n")
+
+	typeKeys(im, "/orders")
+	pressKey(im, tea.KeyEnter)
+
+	if im.scrollOffset != 8 {
+		t.Errorf("Expected scroll offset 8 for the first match, got %d", im.scrollOffset)
+	}
+	if im.statusMsg != "match 1 of 2" {
+		t.Errorf("Unexpected status: %q", im.statusMsg)
+	}
+
+	// Matches are highlighted in reverse video, preserving the original case
+	view := im.renderContent([]string{lines[8], lines[20], lines[0]})
+	if !strings.Contains(view, "\x1b[7morders\x1b[27m-events") || !strings.Contains(view, "\x1b[7mORDERS\x1b[27m-archive") {
+		t.Errorf("Matches should be highlighted, got %q", view)
+	}
+	if strings.Contains(view, "\x1b[7mtopic") {
+		t.Error("Non-matching lines should not be highlighted")
+	}
+
+	typeKeys(im, "n")
+	if im.scrollOffset != 20 || im.statusMsg != "match 2 of 2" {
+		t.Errorf("n should jump to the second match, got offset %d (%q)", im.scrollOffset, im.statusMsg)
+	}
+
+	// n wraps around to the first match
+	typeKeys(im, "n")
+	if im.scrollOffset != 8 {
+		t.Errorf("n should wrap to the first match, got offset %d", im.scrollOffset)
+	}
+
+	// N wraps around to the last match
+	typeKeys(im, "N")
+	if im.scrollOffset != 20 {
+		t.Errorf("N should wrap to the last match, got offset %d", im.scrollOffset)
+	}
+}
+
+func TestInteractiveSearchNotFound(t *testing.T) {
+	im := newTestInteractiveMode()
+	im.content = "alpha\nbeta"
+
+	im.performSearch("gamma")
+
+	if im.statusMsg != "Pattern 'gamma' not found" {
+		t.Errorf("Unexpected status: %q", im.statusMsg)
+	}
+	if im.renderContent([]string{"alpha"}) != "alpha" {
+		t.Error("Content should not be highlighted without matches")
+	}
+}
+
+func TestInteractiveGroupsView(t *testing.T) {
+	im := newTestInteractiveMode()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	mock.AddMockGroup("orders-service", "Stable", "consumer", 3)
+	mock.AddMockGroup("billing-service", "Empty", "consumer", 0)
+	im.clientManager = mock.ClientManager()
+
+	im.executeCommand("groups")
+
+	if im.currentView != "groups" {
+		t.Errorf("Expected groups view, got %s", im.currentView)
+	}
+	if im.statusMsg != "Showing 2 consumer groups" {
+		t.Errorf("Unexpected status: %q", im.statusMsg)
+	}
+
+	lines := strings.Split(im.content, "\n")
+	assertRow := func(groupID string, fields ...string) {
+		t.Helper()
+		for _, line := range lines {
+			if strings.HasPrefix(line, groupID+" ") {
+				got := strings.Fields(line)
+				want := append([]string{groupID}, fields...)
+				if strings.Join(got, " ") != strings.Join(want, " ") {
+					t.Errorf("Unexpected row for %s: %q", groupID, line)
+				}
+				return
+			}
+		}
+		t.Errorf("No row for group %s in:\n%s", groupID, im.content)
+	}
+	assertRow("orders-service", "Stable", "3", "consumer")
+	assertRow("billing-service", "Empty", "0", "consumer")
+
+	// Refreshing reloads the groups
+	mock.AddMockGroup("audit-service", "Stable", "consumer", 1)
+	typeKeys(im, "r")
+	if !strings.Contains(im.content, "audit-service") {
+		t.Errorf("Refresh should show the new group, got:\n%s", im.content)
+	}
+}
+
+func TestInteractiveViewsReuseClient(t *testing.T) {
+	im := newTestInteractiveMode()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	mock.AddMockTopic("orders", 1, 1)
+	mock.AddMockGroup("orders-service", "Stable", "consumer", 1)
+
+	var clients []*client.Client
+	im.clientManager = client.NewManagerWithFactory(testutil.TestLogger(), func(*config.Profile) (*client.Client, error) {
+		kafkaClient := mock.KafkaClient()
+		clients = append(clients, kafkaClient)
+		return kafkaClient, nil
+	})
+
+	im.executeCommand("topics")
+	im.executeCommand("groups")
+	typeKeys(im, "r")
+
+	if im.currentView != "groups" {
+		t.Errorf("Expected groups view, got %s", im.currentView)
+	}
+	if len(clients) != 1 {
+		t.Errorf("Expected the views to share one client, got %d", len(clients))
+	}
+}
+
+func TestInteractiveGroupsViewConnectionError(t *testing.T) {
+	im := newTestInteractiveMode()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	mock.SetShouldFailOps(true)
+	im.clientManager = mock.ClientManager()
+
+	im.executeCommand("groups")
+
+	if !strings.HasPrefix(im.statusMsg, "Failed to connect") {
+		t.Errorf("Connection errors should be shown in the status bar, got %q", im.statusMsg)
+	}
+	if im.currentView != "help" {
+		t.Errorf("View should not change on error, got %s", im.currentView)
+	}
+}
+
+func TestInteractiveCreateTopic(t *testing.T) {
+	im := newTestInteractiveMode()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	im.clientManager = mock.ClientManager()
+
+	runCommand(im, "create-topic foo 3 1")
+
+	meta, exists := mock.MockTopic("foo")
+	if !exists {
+		t.Fatal("Mock admin should have received the create")
+	}
+	if len(meta.Partitions) != 3 || len(meta.Partitions[0].Replicas) != 1 {
+		t.Errorf("Expected 3 partitions with replication factor 1, got %d partitions", len(meta.Partitions))
+	}
+
+	if im.currentView != "topics" || !strings.Contains(im.content, "foo") {
+		t.Errorf("Topics view should be refreshed after create, got view %s:\n%s", im.currentView, im.content)
+	}
+	if im.statusMsg != "Topic 'foo' created" {
+		t.Errorf("Unexpected status: %q", im.statusMsg)
+	}
+
+	// Invalid arguments are reported without contacting the cluster
+	runCommand(im, "create-topic bar zero 1")
+	if !strings.HasPrefix(im.statusMsg, "Invalid partition count") {
+		t.Errorf("Unexpected status: %q", im.statusMsg)
+	}
+	if _, exists := mock.MockTopic("bar"); exists {
+		t.Error("Topic should not be created with invalid arguments")
+	}
+}
+
+func TestInteractiveDeleteTopicConfirmation(t *testing.T) {
+	im := newTestInteractiveMode()
+
+	mock := testutil.NewMockClient(testutil.TestProfile(), testutil.TestLogger())
+	mock.AddMockTopic("foo", 1, 1)
+	im.clientManager = mock.ClientManager()
+
+	// Declining keeps the topic
+	runCommand(im, "delete-topic foo")
+	if !im.confirmMode {
+		t.Fatal("delete-topic should ask for confirmation")
+	}
+	typeKeys(im, "n")
+	if im.confirmMode {
+		t.Error("'n' should leave confirmation mode")
+	}
+	if _, exists := mock.MockTopic("foo"); !exists {
+		t.Fatal("Topic should not be deleted when declined")
+	}
+
+	// Other keys are ignored until the user answers
+	runCommand(im, "delete-topic foo")
+	typeKeys(im, "x")
+	if !im.confirmMode {
+		t.Error("Unrelated keys should not leave confirmation mode")
+	}
+
+	typeKeys(im, "y")
+	if _, exists := mock.MockTopic("foo"); exists {
+		t.Error("Topic should be deleted after confirmation")
+	}
+	if im.currentView != "topics" || im.statusMsg != "Topic 'foo' deleted" {
+		t.Errorf("Unexpected view %s with status %q", im.currentView, im.statusMsg)
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// isWriterTerminal reports whether w is a terminal. Tests replace it to force
+// paging.
+var isWriterTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Pager pipes output through $PAGER, or less, like git does. When the output
+// is not a terminal or the pager cannot be started, writes go straight to the
+// output.
+type Pager struct {
+	out   io.Writer
+	stdin io.WriteCloser
+	cmd   *exec.Cmd
+}
+
+// NewPager starts a pager that displays on out
+func NewPager(out io.Writer) *Pager {
+	p := &Pager{out: out}
+	if !isWriterTerminal(out) {
+		return p
+	}
+
+	args := strings.Fields(os.Getenv("PAGER"))
+	if len(args) == 0 {
+		args = []string{"less"}
+	}
+	if args[0] == "cat" {
+		return p
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		// Quit if the output fits on one screen, keep colors and do not
+		// clear the screen on exit
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return p
+	}
+	if err := cmd.Start(); err != nil {
+		return p
+	}
+
+	p.stdin = stdin
+	p.cmd = cmd
+	return p
+}
+
+// Enabled reports whether output is going through a pager
+func (p *Pager) Enabled() bool {
+	return p.cmd != nil
+}
+
+// Write writes b to the pager, or to the output when paging is disabled
+func (p *Pager) Write(b []byte) (int, error) {
+	if p.stdin != nil {
+		return p.stdin.Write(b)
+	}
+	return p.out.Write(b)
+}
+
+// Close closes the pager's input and waits for the user to quit it
+func (p *Pager) Close() error {
+	if p.cmd == nil {
+		return nil
+	}
+	if err := p.stdin.Close(); err != nil {
+		return err
+	}
+	return p.cmd.Wait()
+}
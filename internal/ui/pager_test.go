@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/nipunap/kim/pkg/types"
+)
+
+func TestPagerDisabledWhenNotTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	pager := NewPager(&buf)
+
+	if pager.Enabled() {
+		t.Fatal("Expected paging to be disabled for a non-terminal writer")
+	}
+
+	message := &types.Message{
+		Topic:     "orders",
+		Partition: 1,
+		Offset:    42,
+		Key:       "order-1",
+		Value:     "created",
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := DisplayMessageTo(pager, message, &types.DisplayOptions{Format: "table"}); err != nil {
+		t.Fatalf("DisplayMessageTo failed: %v", err)
+	}
+	if err := pager.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Topic: orders | Partition: 1 | Offset: 42", "Key: order-1", "Value: created"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got %q", want, output)
+		}
+	}
+}
+
+func TestPagerUsesPagerCommand(t *testing.T) {
+	old := isWriterTerminal
+	isWriterTerminal = func(w io.Writer) bool { return true }
+	t.Cleanup(func() { isWriterTerminal = old })
+	t.Setenv("PAGER", "tr a-z A-Z")
+
+	var buf bytes.Buffer
+	pager := NewPager(&buf)
+	if !pager.Enabled() {
+		t.Fatal("Expected paging to be enabled")
+	}
+
+	if _, err := pager.Write([]byte("paged output\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := pager.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got := buf.String(); got != "PAGED OUTPUT\n" {
+		t.Errorf("Expected output through the pager, got %q", got)
+	}
+}
+
+func TestPagerWriteFailsAfterQuit(t *testing.T) {
+	old := isWriterTerminal
+	isWriterTerminal = func(w io.Writer) bool { return true }
+	t.Cleanup(func() { isWriterTerminal = old })
+	// The pager exits straight away, like a user quitting less
+	t.Setenv("PAGER", "true")
+
+	var buf bytes.Buffer
+	pager := NewPager(&buf)
+	if !pager.Enabled() {
+		t.Fatal("Expected paging to be enabled")
+	}
+
+	var err error
+	for deadline := time.Now().Add(5 * time.Second); err == nil && time.Now().Before(deadline); {
+		_, err = pager.Write([]byte("paged output\n"))
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !errors.Is(err, syscall.EPIPE) {
+		t.Errorf("Expected EPIPE once the pager quit, got %v", err)
+	}
+	if err := pager.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
@@ -7,10 +7,11 @@ import (
 
 // Pagination represents pagination information
 type Pagination struct {
-	CurrentPage int `json:"current_page"`
-	TotalPages  int `json:"total_pages"`
-	PageSize    int `json:"page_size"`
-	TotalItems  int `json:"total_items"`
+	CurrentPage int    `json:"current_page"`
+	TotalPages  int    `json:"total_pages"`
+	PageSize    int    `json:"page_size"`
+	TotalItems  int    `json:"total_items"`
+	NextOffset  *int64 `json:"next_offset,omitempty"` // offset-based pages only
 }
 
 // ListOptions represents common listing options
@@ -20,8 +21,31 @@ type ListOptions struct {
 	Pattern  string `json:"pattern,omitempty"`
 	SortBy   string `json:"sort_by"`
 	Order    string `json:"order"` // "asc" or "desc"
+
+	// Glob patterns of names to hide, applied after Pattern
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Partition count range for topic lists; zero means no bound
+	MinPartitions int32 `json:"min_partitions,omitempty"`
+	MaxPartitions int32 `json:"max_partitions,omitempty"`
+
+	// Consumer group states to include in group lists; empty means all
+	States []string `json:"states,omitempty"`
+
+	// Estimate the message count and size of each listed topic
+	WithSize bool `json:"with_size,omitempty"`
+
+	// Which internal topics to include in topic lists; empty means InternalExclude
+	Internal string `json:"internal,omitempty"`
 }
 
+// Internal topic filters for topic lists
+const (
+	InternalExclude = "exclude"
+	InternalInclude = "include"
+	InternalOnly    = "only"
+)
+
 // Topic-related types
 
 // TopicInfo represents basic topic information
@@ -30,6 +54,10 @@ type TopicInfo struct {
 	Partitions        int32  `json:"partitions"`
 	ReplicationFactor int32  `json:"replication_factor"`
 	Internal          bool   `json:"internal"`
+
+	// Estimated size, only set when requested with ListOptions.WithSize
+	MessageCount *int64 `json:"message_count,omitempty"`
+	SizeBytes    *int64 `json:"size_bytes,omitempty"`
 }
 
 // TopicList represents a paginated list of topics
@@ -45,6 +73,24 @@ type PartitionInfo struct {
 	Replicas        []int32 `json:"replicas"`
 	InSyncReplicas  []int32 `json:"in_sync_replicas"`
 	OfflineReplicas []int32 `json:"offline_replicas"`
+
+	// Offsets, only set when requested with DescribeTopicOptions.WithOffsets
+	EarliestOffset *int64 `json:"earliest_offset,omitempty"`
+	LatestOffset   *int64 `json:"latest_offset,omitempty"`
+	MessageCount   *int64 `json:"message_count,omitempty"`
+}
+
+// DescribeTopicOptions represents optional details of a topic describe
+type DescribeTopicOptions struct {
+	// Fetch the earliest and latest offset of each partition
+	WithOffsets bool `json:"with_offsets,omitempty"`
+}
+
+// DescribeTopicResult represents the outcome of describing one of several topics
+type DescribeTopicResult struct {
+	Name    string        `json:"name"`
+	Details *TopicDetails `json:"details,omitempty"`
+	Error   string        `json:"error,omitempty"`
 }
 
 // TopicDetails represents detailed topic information
@@ -55,6 +101,36 @@ type TopicDetails struct {
 	Internal          bool              `json:"internal"`
 	Configs           map[string]string `json:"configs"`
 	PartitionDetails  []*PartitionInfo  `json:"partition_details"`
+	UnderReplicated   bool              `json:"under_replicated"`   // Some partition has fewer in-sync replicas than replicas
+	OfflinePartitions []int32           `json:"offline_partitions"` // Partitions with offline replicas or no leader
+}
+
+// TopicDiff represents the differences between two topics. Entries are
+// sorted by key and only keys whose values differ are listed.
+type TopicDiff struct {
+	TopicA    string       `json:"topic_a"`
+	TopicB    string       `json:"topic_b"`
+	Structure []*DiffEntry `json:"structure"`
+	Configs   []*DiffEntry `json:"configs"`
+}
+
+// Identical reports whether the two topics have no differences
+func (d *TopicDiff) Identical() bool {
+	return len(d.Structure) == 0 && len(d.Configs) == 0
+}
+
+// DiffEntry represents a key whose value differs between two resources. A nil
+// value means the key is not set on that side.
+type DiffEntry struct {
+	Key string  `json:"key"`
+	A   *string `json:"a,omitempty"`
+	B   *string `json:"b,omitempty"`
+}
+
+// DeleteTopicResult represents the outcome of deleting a topic
+type DeleteTopicResult struct {
+	Topic string `json:"topic"`
+	Error string `json:"error,omitempty"`
 }
 
 // CreateTopicRequest represents a request to create a topic
@@ -63,6 +139,9 @@ type CreateTopicRequest struct {
 	Partitions        int32             `json:"partitions"`
 	ReplicationFactor int16             `json:"replication_factor"`
 	Configs           map[string]string `json:"configs,omitempty"`
+
+	// ValidateOnly asks the broker to validate the request without creating the topic
+	ValidateOnly bool `json:"validate_only,omitempty"`
 }
 
 // Consumer Group related types
@@ -73,6 +152,8 @@ type GroupInfo struct {
 	State        string `json:"state"`
 	ProtocolType string `json:"protocol_type"`
 	MemberCount  int    `json:"member_count"`
+	// TotalLag is only set when lag was requested, e.g. when sorting by lag
+	TotalLag *int64 `json:"total_lag,omitempty"`
 }
 
 // GroupList represents a paginated list of consumer groups
@@ -115,6 +196,21 @@ type GroupDetails struct {
 	Coordinator  *CoordinatorInfo `json:"coordinator"`
 	Members      []*MemberInfo    `json:"members"`
 	TotalLag     int64            `json:"total_lag"`
+	LagByTopic   map[string]int64 `json:"lag_by_topic"`
+}
+
+// GroupOffsets represents the committed offsets of a consumer group with the lag
+// of each partition
+type GroupOffsets struct {
+	GroupID  string                 `json:"group_id"`
+	Offsets  []*PartitionAssignment `json:"offsets"`
+	TotalLag int64                  `json:"total_lag"`
+}
+
+// DeleteGroupResult represents the outcome of deleting a consumer group
+type DeleteGroupResult struct {
+	GroupID string `json:"group_id"`
+	Error   string `json:"error,omitempty"`
 }
 
 // ResetOffsetsRequest represents a request to reset consumer group offsets
@@ -127,6 +223,136 @@ type ResetOffsetsRequest struct {
 	ToDateTime *time.Time `json:"to_datetime,omitempty"`
 }
 
+// Cluster related types
+
+// BrokerInfo represents a broker in the cluster
+type BrokerInfo struct {
+	ID   int32  `json:"id"`
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+}
+
+// VersionInfo describes the kim build and the Kafka client it uses
+type VersionInfo struct {
+	Version              string `json:"version"`
+	Commit               string `json:"commit"`
+	BuildDate            string `json:"build_date"`
+	GoVersion            string `json:"go_version"`
+	SaramaVersion        string `json:"sarama_version"`
+	KafkaProtocolVersion string `json:"kafka_protocol_version"`
+}
+
+// ClusterInfo represents cluster-level information
+type ClusterInfo struct {
+	ClusterID    string        `json:"cluster_id"`
+	ControllerID int32         `json:"controller_id"`
+	Brokers      []*BrokerInfo `json:"brokers"`
+}
+
+// Cluster health statuses, from best to worst
+const (
+	HealthPass = "pass"
+	HealthWarn = "warn"
+	HealthFail = "fail"
+)
+
+// TopicHealth lists the partitions of a topic that need attention
+type TopicHealth struct {
+	Topic                     string  `json:"topic"`
+	UnderReplicatedPartitions []int32 `json:"under_replicated_partitions,omitempty"`
+	OfflinePartitions         []int32 `json:"offline_partitions,omitempty"`
+}
+
+// ClusterHealth represents the result of a cluster health check. The status is
+// "fail" when partitions are offline or there is no controller, and "warn" when
+// partitions are under-replicated.
+type ClusterHealth struct {
+	Status                    string         `json:"status"`
+	ClusterID                 string         `json:"cluster_id"`
+	ControllerID              int32          `json:"controller_id"`
+	Brokers                   int            `json:"brokers"`
+	Topics                    int            `json:"topics"`
+	Partitions                int            `json:"partitions"`
+	UnderReplicatedPartitions int            `json:"under_replicated_partitions"`
+	OfflinePartitions         int            `json:"offline_partitions"`
+	ProblemTopics             []*TopicHealth `json:"problem_topics"`
+}
+
+// ElectedPartition is the outcome of a leader election for one partition
+type ElectedPartition struct {
+	Topic          string `json:"topic"`
+	Partition      int32  `json:"partition"`
+	PreviousLeader int32  `json:"previous_leader"`
+	Leader         int32  `json:"leader"`
+	Error          string `json:"error,omitempty"`
+}
+
+// LeaderElection represents the result of a preferred leader election.
+// Partitions already led by their preferred replica are only counted.
+type LeaderElection struct {
+	Partitions int                 `json:"partitions"`
+	Changed    []*ElectedPartition `json:"changed"`
+	Failed     []*ElectedPartition `json:"failed,omitempty"`
+}
+
+// ConfigEntry represents a single configuration entry and where its value comes from
+type ConfigEntry struct {
+	Value     string `json:"value"`
+	Formatted string `json:"formatted,omitempty"`
+	Source    string `json:"source"`
+	Default   bool   `json:"default"`
+	ReadOnly  bool   `json:"read_only"`
+	Sensitive bool   `json:"sensitive"`
+}
+
+// BrokerConfig represents the configuration of a single broker
+type BrokerConfig struct {
+	BrokerID int32                   `json:"broker_id"`
+	Configs  map[string]*ConfigEntry `json:"configs"`
+}
+
+// TopicConfig represents the configuration of a single topic
+type TopicConfig struct {
+	Topic   string                  `json:"topic"`
+	Configs map[string]*ConfigEntry `json:"configs"`
+}
+
+// ACL related types
+
+// ACLBinding represents a single ACL entry bound to a resource
+type ACLBinding struct {
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	PatternType  string `json:"pattern_type"`
+	Principal    string `json:"principal"`
+	Host         string `json:"host"`
+	Operation    string `json:"operation"`
+	Permission   string `json:"permission"`
+}
+
+// ACLFilter selects ACLs by principal and resource. Empty fields match everything.
+type ACLFilter struct {
+	Principal string `json:"principal,omitempty"`
+	Topic     string `json:"topic,omitempty"`
+	Group     string `json:"group,omitempty"`
+}
+
+// Quota related types
+
+// QuotaEntity identifies the user and/or client ID a quota applies to. The name
+// "<default>" refers to the default quota for all users or client IDs.
+type QuotaEntity struct {
+	User     string `json:"user,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// QuotaEntry represents the quotas configured for a single entity
+type QuotaEntry struct {
+	User     string             `json:"user,omitempty"`
+	ClientID string             `json:"client_id,omitempty"`
+	Values   map[string]float64 `json:"values"`
+}
+
 // Message related types
 
 // Message represents a Kafka message
@@ -153,6 +379,13 @@ type ProduceRequest struct {
 	Value     string            `json:"value"`
 	Partition *int32            `json:"partition,omitempty"`
 	Headers   map[string]string `json:"headers,omitempty"`
+	Tombstone bool              `json:"tombstone,omitempty"` // produce a null value
+	// ValueEncoding is how Value is encoded: raw (default), base64 or hex
+	ValueEncoding string `json:"value_encoding,omitempty"`
+	// Acks is how many acknowledgements to wait for: none, leader or all (default)
+	Acks string `json:"acks,omitempty"`
+	// Compression is the codec to compress with: none (default), gzip, snappy, lz4 or zstd
+	Compression string `json:"compression,omitempty"`
 }
 
 // ProduceResponse represents the response from producing a message
@@ -163,17 +396,43 @@ type ProduceResponse struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
-// ConsumeRequest represents a request to start consuming messages
+// ReplayRequest represents a request to copy messages from one topic to another.
+// Max bounds the number of messages copied; 0 means no limit.
+type ReplayRequest struct {
+	Source             string `json:"source"`
+	Destination        string `json:"destination"`
+	FromBeginning      bool   `json:"from_beginning"`
+	Max                int    `json:"max,omitempty"`
+	PreservePartitions bool   `json:"preserve_partitions"` // Produce to the partition each message was read from
+}
+
+// ReplayResult represents the outcome of a replay
+type ReplayResult struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Replayed    int    `json:"replayed"`
+}
+
+// ConsumeRequest represents a request to start consuming messages.
+// Partitions takes precedence over Partition, and AllPartitions over both.
 type ConsumeRequest struct {
-	Topic         string `json:"topic"`
-	Partition     int32  `json:"partition"`
-	GroupID       string `json:"group_id"`
-	FromBeginning bool   `json:"from_beginning"`
+	Topic         string     `json:"topic"`
+	Partition     int32      `json:"partition"`
+	Partitions    []int32    `json:"partitions,omitempty"`
+	AllPartitions bool       `json:"all_partitions,omitempty"`
+	GroupID       string     `json:"group_id"`
+	FromBeginning bool       `json:"from_beginning"`
+	ValueEncoding string     `json:"value_encoding,omitempty"` // raw (default), base64 or hex
+	Since         *time.Time `json:"since,omitempty"`          // start at the first message at or after this time
+	Until         *time.Time `json:"until,omitempty"`          // stop a partition at its first message after this time
 }
 
 // ConsumerInfo represents information about an active consumer
 type ConsumerInfo struct {
-	Topic         string `json:"topic"`
+	Topic      string  `json:"topic"`
+	Partitions []int32 `json:"partitions"`
+	// Deprecated: use Partitions. Partition is the first consumed partition,
+	// kept for readers of the single-partition JSON output.
 	Partition     int32  `json:"partition"`
 	GroupID       string `json:"group_id"`
 	FromBeginning bool   `json:"from_beginning"`
@@ -181,11 +440,14 @@ type ConsumerInfo struct {
 
 // GetMessagesRequest represents a request to get messages from a topic
 type GetMessagesRequest struct {
-	Topic         string `json:"topic"`
-	Partition     int32  `json:"partition"`
-	FromBeginning bool   `json:"from_beginning"`
-	Limit         int    `json:"limit"`
-	Offset        *int64 `json:"offset,omitempty"`
+	Topic         string     `json:"topic"`
+	Partition     int32      `json:"partition"`
+	FromBeginning bool       `json:"from_beginning"`
+	Limit         int        `json:"limit"`
+	Offset        *int64     `json:"offset,omitempty"`
+	ValueEncoding string     `json:"value_encoding,omitempty"` // raw (default), base64 or hex
+	Since         *time.Time `json:"since,omitempty"`          // start at the first message at or after this time
+	Until         *time.Time `json:"until,omitempty"`          // stop at the first message after this time
 }
 
 // Profile related types
@@ -198,14 +460,52 @@ type ProfileInfo struct {
 	Active  bool   `json:"active"`
 }
 
+// ProfileDetails represents the full configuration of a profile. Secrets are
+// redacted unless explicitly requested.
+type ProfileDetails struct {
+	Name              string            `json:"name"`
+	Type              string            `json:"type"`
+	Active            bool              `json:"active"`
+	BootstrapServers  string            `json:"bootstrap_servers,omitempty"`
+	Region            string            `json:"region,omitempty"`
+	ClusterARN        string            `json:"cluster_arn,omitempty"`
+	AuthMethod        string            `json:"auth_method,omitempty"`
+	AWSProfile        string            `json:"aws_profile,omitempty"`
+	AWSRoleARN        string            `json:"aws_role_arn,omitempty"`
+	SecurityProtocol  string            `json:"security_protocol,omitempty"`
+	SASLMechanism     string            `json:"sasl_mechanism,omitempty"`
+	SASLUsername      string            `json:"sasl_username,omitempty"`
+	SASLPassword      string            `json:"sasl_password,omitempty"`
+	OAuthTokenCommand string            `json:"oauth_token_command,omitempty"`
+	OAuthTokenEnv     string            `json:"oauth_token_env,omitempty"`
+	SSLCAFile         string            `json:"ssl_ca_file,omitempty"`
+	SSLCertFile       string            `json:"ssl_cert_file,omitempty"`
+	SSLKeyFile        string            `json:"ssl_key_file,omitempty"`
+	SSLPassword       string            `json:"ssl_password,omitempty"`
+	SSLCheckHostname  bool              `json:"ssl_check_hostname,omitempty"`
+	TLSMinVersion     string            `json:"tls_min_version,omitempty"`
+	TLSServerName     string            `json:"tls_server_name,omitempty"`
+	SchemaRegistryURL string            `json:"schema_registry_url,omitempty"`
+	KafkaVersion      string            `json:"kafka_version,omitempty"`
+	ClientID          string            `json:"client_id,omitempty"`
+	DialTimeout       string            `json:"dial_timeout,omitempty"`
+	ReadTimeout       string            `json:"read_timeout,omitempty"`
+	WriteTimeout      string            `json:"write_timeout,omitempty"`
+	MetadataRetryMax  int               `json:"metadata_retry_max,omitempty"`
+	Extra             map[string]string `json:"extra,omitempty"`
+}
+
 // UI related types
 
 // DisplayOptions represents display formatting options
 type DisplayOptions struct {
-	Format      string `json:"format"`       // "table", "json", "yaml"
-	ColorScheme string `json:"color_scheme"` // "default", "dark", "light"
+	Format      string `json:"format"`       // "table", "json", "yaml", "go-template"
+	Template    string `json:"template"`     // text/template used by the go-template format
+	ColorScheme string `json:"color_scheme"` // "default", "dark", "light", "none"
 	NoHeaders   bool   `json:"no_headers"`
 	Compact     bool   `json:"compact"`
+	// LagThreshold highlights consumer lag above it in group tables (0 = none)
+	LagThreshold int64 `json:"lag_threshold"`
 }
 
 // InteractiveState represents the state of interactive mode
@@ -228,6 +528,21 @@ type Command struct {
 
 // Error types
 
+// Error codes of connection failures
+const (
+	ErrCodeDNSResolution     = "DNS_RESOLUTION_FAILED"
+	ErrCodeConnectionTimeout = "CONNECTION_TIMEOUT"
+	ErrCodeConnectionFailed  = "CONNECTION_FAILED"
+	ErrCodeTLSHandshake      = "TLS_HANDSHAKE_FAILED"
+	ErrCodeAuthFailed        = "AUTH_FAILED"
+)
+
+// ErrCodeClusterUnhealthy is returned when a cluster health check does not pass
+const ErrCodeClusterUnhealthy = "CLUSTER_UNHEALTHY"
+
+// ErrCodeLagThresholdExceeded is returned when consumer lag is above a --lag-threshold
+const ErrCodeLagThresholdExceeded = "LAG_THRESHOLD_EXCEEDED"
+
 // KimError represents an application error
 type KimError struct {
 	Code    string `json:"code"`
@@ -206,7 +206,7 @@ func TestIntegrationMessageOperations(t *testing.T) {
 	output, err := runKimCommand("message", "produce", testTopicName,
 		"--key", testKey,
 		"--value", testValue,
-		"--header", "test-header:test-header-value")
+		"--header", "test-header=test-header-value")
 	if err != nil {
 		t.Fatalf("Failed to produce message: %v\nOutput: %s", err, output)
 	}
@@ -331,6 +331,26 @@ func TestIntegrationConsumerGroupOperations(t *testing.T) {
 		t.Errorf("Filtered group list should contain test group, got: %s", output)
 	}
 
+	// The group's offsets are committed to __consumer_offsets, which topic
+	// list hides unless --all is given
+	output, err = runKimCommand("topic", "list", "--pattern", "__consumer_offsets")
+	if err != nil {
+		t.Fatalf("Failed to list topics: %v\nOutput: %s", err, output)
+	}
+
+	if strings.Contains(output, "__consumer_offsets") {
+		t.Errorf("Internal topics should be hidden by default, got: %s", output)
+	}
+
+	output, err = runKimCommand("topic", "list", "--all", "--pattern", "__consumer_offsets")
+	if err != nil {
+		t.Fatalf("Failed to list topics with --all: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(output, "__consumer_offsets") {
+		t.Errorf("Internal topics should be listed with --all, got: %s", output)
+	}
+
 	// Wait for consumer to finish
 	time.Sleep(2 * time.Second)
 